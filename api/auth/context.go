@@ -33,3 +33,39 @@ func ContextWithUser(ctx context.Context, user *models.User) context.Context {
 func GetContextUser(ctx context.Context) *models.User {
 	return ctx.Value(CONTEXT_USER).(*models.User)
 }
+
+// CONTEXT_TOKEN is the context key under which the personal access token the
+// request was authenticated with is carried, if any.
+const CONTEXT_TOKEN authContextKey = "token"
+
+// ContextWithToken binds the personal access token a request was authenticated
+// with to its context, so that authorization honors its restrictions (see
+// [VerifyPermission]).
+func ContextWithToken(ctx context.Context, token *models.Token) context.Context {
+	return context.WithValue(ctx, CONTEXT_TOKEN, token)
+}
+
+// GetContextToken returns the personal access token previously bound to ctx
+// with [ContextWithToken], or nil when the request was authenticated otherwise
+// (session token, password).
+func GetContextToken(ctx context.Context) *models.Token {
+	token, _ := ctx.Value(CONTEXT_TOKEN).(*models.Token)
+	return token
+}
+
+// CONTEXT_SOURCE_IP is the context key under which the IP address a request
+// was received from is carried.
+const CONTEXT_SOURCE_IP authContextKey = "source_ip"
+
+// ContextWithSourceIP binds the IP address a request was received from to its
+// context, so that the changes it makes can be audited.
+func ContextWithSourceIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, CONTEXT_SOURCE_IP, ip)
+}
+
+// GetContextSourceIP returns the IP address previously bound to ctx with
+// [ContextWithSourceIP], or an empty string if none was.
+func GetContextSourceIP(ctx context.Context) string {
+	ip, _ := ctx.Value(CONTEXT_SOURCE_IP).(string)
+	return ip
+}
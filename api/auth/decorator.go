@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/swaggest/usecase/status"
 
 	"link-society.com/flowg/internal/models"
@@ -11,18 +12,130 @@ import (
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
 
+// VerifyPermission reports whether the caller bound to ctx holds a given
+// permission scope on a given resource: its user must be granted the scope, on
+// every resource or on this one, and the personal access token it
+// authenticated with, if any, must not be restricted to other scopes.
+//
+// Every entrypoint authorizing callers goes through it, so that a restricted
+// token is restricted everywhere.
+func VerifyPermission(
+	ctx context.Context,
+	authStorage storage.AuthStorage,
+	scope models.Scope,
+	resource models.Resource,
+) (bool, error) {
+	if token := GetContextToken(ctx); token != nil && !token.AllowsScope(scope) {
+		return false, nil
+	}
+
+	return authStorage.VerifyUserPermission(ctx, GetContextUser(ctx).Name, scope, resource)
+}
+
+// VerifyAnyPermission reports whether the caller bound to ctx holds a given
+// permission scope on every resource, or on some of them only. Callers still
+// have to verify the permission on the resources they act upon.
+func VerifyAnyPermission(
+	ctx context.Context,
+	authStorage storage.AuthStorage,
+	scope models.Scope,
+) (bool, error) {
+	authorized, err := VerifyPermission(ctx, authStorage, scope, nil)
+	if err != nil || authorized {
+		return authorized, err
+	}
+
+	if token := GetContextToken(ctx); token != nil && !token.AllowsScope(scope) {
+		return false, nil
+	}
+
+	resourceScopes, err := authStorage.ListUserResourceScopes(ctx, GetContextUser(ctx).Name)
+	if err != nil {
+		return false, err
+	}
+
+	for _, resourceScope := range resourceScopes {
+		if resourceScope.Scope().Implies(scope) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ResourceFilter returns a predicate reporting whether the caller bound to ctx
+// holds a given permission scope on the resource with the given name, e.g. to
+// filter the streams it lists.
+//
+// The predicate is resolved once: the scopes of the caller are not looked up
+// again for every resource.
+func ResourceFilter(
+	ctx context.Context,
+	authStorage storage.AuthStorage,
+	scope models.Scope,
+) (func(name string) bool, error) {
+	if token := GetContextToken(ctx); token != nil && !token.AllowsScope(scope) {
+		return func(string) bool { return false }, nil
+	}
+
+	username := GetContextUser(ctx).Name
+
+	global, err := authStorage.VerifyUserPermission(ctx, username, scope, nil)
+	if err != nil {
+		return nil, err
+	}
+	if global {
+		return func(string) bool { return true }, nil
+	}
+
+	resourceScopes, err := authStorage.ListUserResourceScopes(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	parameter := scope.ResourceParameter()
+
+	return func(name string) bool {
+		resource := models.Resource{parameter: name}
+		for _, resourceScope := range resourceScopes {
+			if resourceScope.Grants(scope, resource) {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
+
+// routeResource returns the resource named by the path parameters of the
+// route the request bound to ctx was routed through.
+func routeResource(ctx context.Context) models.Resource {
+	resource := models.Resource{}
+
+	if routeCtx := chi.RouteContext(ctx); routeCtx != nil {
+		for i, key := range routeCtx.URLParams.Keys {
+			resource[key] = routeCtx.URLParams.Values[i]
+		}
+	}
+
+	return resource
+}
+
 // RequireScopeApiDecorator guards a use-case interactor so that it only runs
 // for callers who have been granted a given permission scope.
 //
 // It exists to keep authorization out of the business logic: handlers declare
 // the scope they need and remain unaware of how permissions are resolved. The
-// returned interactor relies on the authenticated user being present in the
-// context (see [GetContextUser]) and behaves as follows:
+// scope is verified on the resource named by the path parameters of the route
+// (see [models.ResourceScope]). The returned interactor relies on the
+// authenticated user being present in the context (see [GetContextUser]) and
+// behaves as follows:
 //
 //   - the permission lookup fails: the error is wrapped as
 //     [status.PermissionDenied] and next is not invoked;
-//   - the user lacks the scope: [status.PermissionDenied] is returned and next
-//     is not invoked;
+//   - the user lacks the scope on the resource, or authenticated with a
+//     personal access token restricted to other scopes:
+//     [status.PermissionDenied] is returned and next is not invoked;
 //   - the user holds the scope: next is invoked and its result is returned
 //     unchanged.
 func RequireScopeApiDecorator[Req any, Resp any](
@@ -32,11 +145,7 @@ func RequireScopeApiDecorator[Req any, Resp any](
 ) func(context.Context, Req, *Resp) error {
 	return func(ctx context.Context, req Req, resp *Resp) error {
 		user := GetContextUser(ctx)
-		authorized, err := authStorage.VerifyUserPermission(
-			ctx,
-			user.Name,
-			scope,
-		)
+		authorized, err := VerifyPermission(ctx, authStorage, scope, routeResource(ctx))
 		if err != nil {
 			slog.ErrorContext(
 				ctx,
@@ -88,3 +197,61 @@ func RequireScopesApiDecorator[Req any, Resp any](
 		)
 	}
 }
+
+// RequireListScopeApiDecorator guards a use-case interactor listing resources,
+// so that it only runs for callers who have been granted a given permission
+// scope on every resource, or on some of them only.
+//
+// Unlike [RequireScopeApiDecorator], it lets through callers granted the scope
+// on resources matching some pattern only: next is then responsible for
+// listing only the resources they may see, using [ResourceFilter]. Failures
+// are reported as [status.PermissionDenied], as by [RequireScopeApiDecorator].
+func RequireListScopeApiDecorator[Req any, Resp any](
+	authStorage storage.AuthStorage,
+	scope models.Scope,
+	next func(context.Context, Req, *Resp) error,
+) func(context.Context, Req, *Resp) error {
+	return func(ctx context.Context, req Req, resp *Resp) error {
+		user := GetContextUser(ctx)
+		authorized, err := VerifyAnyPermission(ctx, authStorage, scope)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"Failed to verify user permission",
+				slog.String("channel", "api"),
+				slog.String("error", err.Error()),
+			)
+			return status.Wrap(err, status.PermissionDenied)
+		}
+
+		if !authorized {
+			return status.PermissionDenied
+		}
+
+		slog.DebugContext(
+			ctx,
+			"Authorized user",
+			slog.String("channel", "api"),
+			slog.String("user", user.Name),
+			slog.String("scope", string(scope)),
+		)
+
+		return next(ctx, req, resp)
+	}
+}
+
+// RequireUnrestrictedApiDecorator guards a use-case interactor managing the
+// credentials of the caller, so that it does not run for callers authenticated
+// with a personal access token restricted to some scopes: such a token could
+// otherwise mint an unrestricted one, or revoke the other tokens of its owner.
+func RequireUnrestrictedApiDecorator[Req any, Resp any](
+	next func(context.Context, Req, *Resp) error,
+) func(context.Context, Req, *Resp) error {
+	return func(ctx context.Context, req Req, resp *Resp) error {
+		if token := GetContextToken(ctx); token != nil && token.IsRestricted() {
+			return status.PermissionDenied
+		}
+
+		return next(ctx, req, resp)
+	}
+}
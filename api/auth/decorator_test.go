@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -29,7 +30,7 @@ func TestRequireScopeAllowsAuthorizedCaller(t *testing.T) {
 	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
 	mockStorage.On(
 		"VerifyUserPermission",
-		mock.Anything, "alice", models.SCOPE_READ_PIPELINES,
+		mock.Anything, "alice", models.SCOPE_READ_PIPELINES, mock.Anything,
 	).Return(true, nil)
 
 	called := false
@@ -55,7 +56,7 @@ func TestRequireScopeRejectsUnauthorizedCaller(t *testing.T) {
 	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
 	mockStorage.On(
 		"VerifyUserPermission",
-		mock.Anything, "alice", models.SCOPE_READ_PIPELINES,
+		mock.Anything, "alice", models.SCOPE_READ_PIPELINES, mock.Anything,
 	).Return(false, nil)
 
 	called := false
@@ -79,7 +80,7 @@ func TestRequireScopeForwardsLookupFailure(t *testing.T) {
 	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
 	mockStorage.On(
 		"VerifyUserPermission",
-		mock.Anything, "alice", models.SCOPE_READ_PIPELINES,
+		mock.Anything, "alice", models.SCOPE_READ_PIPELINES, mock.Anything,
 	).Return(false, errors.New("storage down"))
 
 	called := false
@@ -115,7 +116,7 @@ func TestRequireScopesWithoutScopesRunsNextUnchanged(t *testing.T) {
 
 	assert.True(t, called, "next must be invoked when no scope is required")
 	require.NoError(t, err)
-	mockStorage.AssertNotCalled(t, "VerifyUserPermission", mock.Anything, mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "VerifyUserPermission", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestRequireScopesRequiresEveryScope(t *testing.T) {
@@ -124,11 +125,11 @@ func TestRequireScopesRequiresEveryScope(t *testing.T) {
 	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
 	mockStorage.On(
 		"VerifyUserPermission",
-		mock.Anything, "alice", models.SCOPE_READ_PIPELINES,
+		mock.Anything, "alice", models.SCOPE_READ_PIPELINES, mock.Anything,
 	).Return(true, nil)
 	mockStorage.On(
 		"VerifyUserPermission",
-		mock.Anything, "alice", models.SCOPE_WRITE_PIPELINES,
+		mock.Anything, "alice", models.SCOPE_WRITE_PIPELINES, mock.Anything,
 	).Return(true, nil)
 
 	called := false
@@ -155,13 +156,13 @@ func TestRequireScopesRejectsWhenOneScopeMissing(t *testing.T) {
 	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
 	mockStorage.On(
 		"VerifyUserPermission",
-		mock.Anything, "alice", models.SCOPE_READ_PIPELINES,
+		mock.Anything, "alice", models.SCOPE_READ_PIPELINES, mock.Anything,
 	).Return(false, nil)
 	// The write scope may or may not be evaluated depending on short-circuit
 	// order; allow it without requiring it.
 	mockStorage.On(
 		"VerifyUserPermission",
-		mock.Anything, "alice", models.SCOPE_WRITE_PIPELINES,
+		mock.Anything, "alice", models.SCOPE_WRITE_PIPELINES, mock.Anything,
 	).Return(true, nil).Maybe()
 
 	called := false
@@ -180,3 +181,175 @@ func TestRequireScopesRejectsWhenOneScopeMissing(t *testing.T) {
 	assert.False(t, called, "next must not be invoked when a scope is missing")
 	require.Error(t, err)
 }
+
+func restrictedContext(scopes ...models.Scope) context.Context {
+	token := &models.Token{UUID: "uuid", Name: "shipper", Scopes: scopes}
+	return auth.ContextWithToken(authorizedContext(), token)
+}
+
+func TestRequireScopeHonorsTokenRestrictions(t *testing.T) {
+	// Contract: a personal access token restricted to other scopes is denied
+	// even though its owner holds the scope, without any permission lookup;
+	// one restricted to the scope still requires its owner to hold it.
+	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
+	mockStorage.On(
+		"VerifyUserPermission",
+		mock.Anything, "alice", models.SCOPE_SEND_LOGS, mock.Anything,
+	).Return(true, nil).Once()
+
+	called := false
+	next := func(ctx context.Context, r req, w *resp) error {
+		called = true
+		return nil
+	}
+
+	denied := auth.RequireScopeApiDecorator(
+		mockStorage, models.SCOPE_READ_STREAMS, next,
+	)
+	err := denied(restrictedContext(models.SCOPE_SEND_LOGS), req{}, &resp{})
+
+	assert.False(t, called, "next must not be invoked beyond the token scopes")
+	require.Error(t, err)
+
+	allowed := auth.RequireScopeApiDecorator(
+		mockStorage, models.SCOPE_SEND_LOGS, next,
+	)
+	err = allowed(restrictedContext(models.SCOPE_SEND_LOGS), req{}, &resp{})
+
+	assert.True(t, called, "next must be invoked within the token scopes")
+	require.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestRequireScopeAllowsReadWithRestrictedWriteToken(t *testing.T) {
+	// Contract: a personal access token restricted to a write scope may be
+	// used for the matching read scope, as a role granting it would.
+	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
+	mockStorage.On(
+		"VerifyUserPermission",
+		mock.Anything, "alice", models.SCOPE_READ_STREAMS, mock.Anything,
+	).Return(true, nil).Once()
+
+	called := false
+	next := func(ctx context.Context, r req, w *resp) error {
+		called = true
+		return nil
+	}
+
+	decorated := auth.RequireScopeApiDecorator(
+		mockStorage, models.SCOPE_READ_STREAMS, next,
+	)
+	err := decorated(restrictedContext(models.SCOPE_WRITE_STREAMS), req{}, &resp{})
+
+	assert.True(t, called, "next must be invoked for the read scope of the token")
+	require.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestRequireUnrestrictedRejectsRestrictedToken(t *testing.T) {
+	// Contract: credential management runs for sessions and unrestricted
+	// tokens, but never for a token restricted to some scopes.
+	called := false
+	next := func(ctx context.Context, r req, w *resp) error {
+		called = true
+		return nil
+	}
+
+	decorated := auth.RequireUnrestrictedApiDecorator(next)
+
+	err := decorated(restrictedContext(models.SCOPE_SEND_LOGS), req{}, &resp{})
+	assert.False(t, called, "next must not be invoked for a restricted token")
+	require.Error(t, err)
+
+	err = decorated(restrictedContext(), req{}, &resp{})
+	assert.True(t, called, "next must be invoked for an unrestricted token")
+	require.NoError(t, err)
+}
+
+func routedContext(ctx context.Context, params map[string]string) context.Context {
+	routeCtx := chi.NewRouteContext()
+	for key, value := range params {
+		routeCtx.URLParams.Add(key, value)
+	}
+
+	return context.WithValue(ctx, chi.RouteCtxKey, routeCtx)
+}
+
+func TestRequireScopeVerifiesRouteResource(t *testing.T) {
+	// Contract: the scope is verified on the resource named by the path
+	// parameters of the route.
+	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
+	mockStorage.On(
+		"VerifyUserPermission",
+		mock.Anything, "alice", models.SCOPE_READ_STREAMS,
+		models.Resource{"stream": "payments-eu"},
+	).Return(true, nil).Once()
+
+	called := false
+	next := func(ctx context.Context, r req, w *resp) error {
+		called = true
+		return nil
+	}
+
+	decorated := auth.RequireScopeApiDecorator(
+		mockStorage, models.SCOPE_READ_STREAMS, next,
+	)
+	ctx := routedContext(authorizedContext(), map[string]string{"stream": "payments-eu"})
+	err := decorated(ctx, req{}, &resp{})
+
+	assert.True(t, called, "next must be invoked for an authorized resource")
+	require.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestRequireListScopeAllowsResourceScopes(t *testing.T) {
+	// Contract: a caller granted the scope on some resources only may list
+	// them, and the filter only lets through the resources it was granted;
+	// one granted no such resource scope is denied.
+	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
+	mockStorage.On(
+		"VerifyUserPermission",
+		mock.Anything, "alice", models.SCOPE_READ_STREAMS, models.Resource(nil),
+	).Return(false, nil)
+	mockStorage.On("ListUserResourceScopes", mock.Anything, "alice").
+		Return(
+			[]models.ResourceScope{"write_streams:payments-*", "send_logs:pipeline=ingress"},
+			nil,
+		)
+
+	var visible []string
+	next := func(ctx context.Context, r req, w *resp) error {
+		canRead, err := auth.ResourceFilter(ctx, mockStorage, models.SCOPE_READ_STREAMS)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range []string{"payments-eu", "ingress", "audit"} {
+			if canRead(name) {
+				visible = append(visible, name)
+			}
+		}
+
+		return nil
+	}
+
+	decorated := auth.RequireListScopeApiDecorator(
+		mockStorage, models.SCOPE_READ_STREAMS, next,
+	)
+	err := decorated(authorizedContext(), req{}, &resp{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payments-eu"}, visible)
+
+	mockStorage.On(
+		"VerifyUserPermission",
+		mock.Anything, "alice", models.SCOPE_READ_ALERTS, models.Resource(nil),
+	).Return(false, nil)
+
+	denied := auth.RequireListScopeApiDecorator(
+		mockStorage, models.SCOPE_READ_ALERTS, next,
+	)
+	err = denied(authorizedContext(), req{}, &resp{})
+
+	require.Error(t, err)
+}
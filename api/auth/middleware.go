@@ -1,12 +1,14 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 
 	"encoding/json"
 	"strings"
 
+	"net"
 	"net/http"
 
 	"github.com/swaggest/rest"
@@ -27,7 +29,10 @@ import (
 // the next handler is invoked. Any failure — missing, malformed, or invalid
 // credential — stops the chain and produces a [status.Unauthenticated] error
 // response, so downstream handlers can assume a valid identity is always
-// present.
+// present. Expired personal access tokens are invalid, and the restrictions of
+// a valid one are bound to the request context too (see [ContextWithToken]),
+// along with the IP address the request was received from (see
+// [ContextWithSourceIP]).
 func ApiMiddleware(authStorage storage.AuthStorage) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		serveError := func(w http.ResponseWriter, r *http.Request, err error) {
@@ -44,7 +49,7 @@ func ApiMiddleware(authStorage storage.AuthStorage) func(http.Handler) http.Hand
 			}
 		}
 
-		serveNext := func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		serveNext := func(w http.ResponseWriter, r *http.Request, user *models.User, token *models.Token) {
 			slog.DebugContext(
 				r.Context(),
 				"Authenticated user",
@@ -53,52 +58,89 @@ func ApiMiddleware(authStorage storage.AuthStorage) func(http.Handler) http.Hand
 			)
 
 			ctx := ContextWithUser(r.Context(), user)
+			ctx = ContextWithSourceIP(ctx, sourceIP(r))
+			if token != nil {
+				ctx = ContextWithToken(ctx, token)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		}
 
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
+			user, token, err := VerifyBearerToken(
+				r.Context(),
+				authStorage,
+				r.Header.Get("Authorization"),
+			)
+			if err != nil {
+				serveError(w, r, err)
+				return
+			}
 
-			switch {
-			case strings.HasPrefix(strings.ToLower(authHeader), "bearer pat_"):
-				token := authHeader[len("bearer "):]
+			serveNext(w, r, user, token)
+		})
+	}
+}
 
-				user, err := authStorage.VerifyToken(r.Context(), token)
-				switch {
-				case err != nil:
-					serveError(w, r, err)
+// sourceIP returns the IP address of the peer a request was received from.
+// Headers set by proxies are not trusted, as any client could forge them.
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
 
-				case user == nil:
-					serveError(w, r, errors.New("invalid token"))
+	return host
+}
 
-				default:
-					serveNext(w, r, user)
-				}
+// VerifyBearerToken resolves the user identified by a bearer credential, as
+// found in an Authorization header ("Bearer pat_..." or "Bearer jwt_..."),
+// along with the description of the personal access token if it is one.
+//
+// It is shared by every entrypoint accepting FlowG tokens, so that they all
+// authenticate callers the same way. An error is returned when the credential
+// is missing, malformed, expired, or does not resolve to a user.
+func VerifyBearerToken(
+	ctx context.Context,
+	authStorage storage.AuthStorage,
+	authHeader string,
+) (*models.User, *models.Token, error) {
+	switch {
+	case strings.HasPrefix(strings.ToLower(authHeader), "bearer pat_"):
+		token := authHeader[len("bearer "):]
+
+		user, description, err := authStorage.VerifyToken(ctx, token)
+		switch {
+		case err != nil:
+			return nil, nil, err
+
+		case user == nil:
+			return nil, nil, errors.New("invalid token")
+
+		default:
+			return user, description, nil
+		}
 
-			case strings.HasPrefix(strings.ToLower(authHeader), "bearer jwt_"):
-				token := authHeader[len("bearer "):]
+	case strings.HasPrefix(strings.ToLower(authHeader), "bearer jwt_"):
+		token := authHeader[len("bearer "):]
 
-				username, err := VerifyJWT(token)
-				if err != nil {
-					serveError(w, r, err)
-					return
-				}
+		username, err := VerifyJWT(token)
+		if err != nil {
+			return nil, nil, err
+		}
 
-				user, err := authStorage.FetchUser(r.Context(), username)
-				switch {
-				case err != nil:
-					serveError(w, r, err)
+		user, err := authStorage.FetchUser(ctx, username)
+		switch {
+		case err != nil:
+			return nil, nil, err
 
-				case user == nil:
-					serveError(w, r, errors.New("invalid token"))
+		case user == nil:
+			return nil, nil, errors.New("invalid token")
 
-				default:
-					serveNext(w, r, user)
-				}
+		default:
+			return user, nil, nil
+		}
 
-			default:
-				serveError(w, r, errors.New("missing token"))
-			}
-		})
+	default:
+		return nil, nil, errors.New("missing token")
 	}
 }
@@ -48,7 +48,7 @@ func TestApiMiddlewareAuthenticatesPersonalAccessToken(t *testing.T) {
 	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
 	user := &models.User{Name: "alice", Roles: []string{"admin"}}
 	mockStorage.On("VerifyToken", mock.Anything, "pat_secret").
-		Return(user, nil)
+		Return(user, &models.Token{UUID: "uuid"}, nil)
 
 	var captured *models.User
 	handler := auth.ApiMiddleware(mockStorage)(newCapturingHandler(&captured))
@@ -64,12 +64,59 @@ func TestApiMiddlewareAuthenticatesPersonalAccessToken(t *testing.T) {
 	mockStorage.AssertExpectations(t)
 }
 
+func TestApiMiddlewareBindsPersonalAccessToken(t *testing.T) {
+	// Contract: the description of the personal access token the request was
+	// authenticated with is bound to the context, so its restrictions apply.
+	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
+	user := &models.User{Name: "alice", Roles: []string{"admin"}}
+	token := &models.Token{UUID: "uuid", Scopes: []models.Scope{models.SCOPE_SEND_LOGS}}
+	mockStorage.On("VerifyToken", mock.Anything, "pat_shipper").
+		Return(user, token, nil)
+
+	var captured *models.Token
+	handler := auth.ApiMiddleware(mockStorage)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = auth.GetContextToken(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer pat_shipper")
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Same(t, token, captured)
+}
+
+func TestApiMiddlewareBindsSourceIP(t *testing.T) {
+	// Contract: the IP address of the peer the request was received from is
+	// bound to the context, so that the changes it makes can be audited.
+	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
+	user := &models.User{Name: "alice", Roles: []string{"admin"}}
+	mockStorage.On("VerifyToken", mock.Anything, "pat_secret").
+		Return(user, &models.Token{UUID: "uuid"}, nil)
+
+	var captured string
+	handler := auth.ApiMiddleware(mockStorage)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = auth.GetContextSourceIP(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:51234"
+	req.Header.Set("Authorization", "Bearer pat_secret")
+	req.Header.Set("X-Forwarded-For", "192.168.0.1")
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "10.0.0.1", captured)
+}
+
 func TestApiMiddlewareRejectsUnknownPersonalAccessToken(t *testing.T) {
 	// Contract: a personal access token that resolves to no user is treated
 	// as invalid and stops the chain.
 	mockStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
 	mockStorage.On("VerifyToken", mock.Anything, "pat_unknown").
-		Return((*models.User)(nil), nil)
+		Return((*models.User)(nil), (*models.Token)(nil), nil)
 
 	nextCalled := false
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"strings"
+
+	"encoding/base64"
+
+	"net/http"
+
+	"link-society.com/flowg/api/auth"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// basicAuth resolves the HTTP Basic credentials log shippers send into a FlowG
+// user, so downstream handlers can enforce the user's permissions.
+func basicAuth(authStorage storage.AuthStorage, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		basicAuthData, found := strings.CutPrefix(authHeader, "Basic ")
+		if !found {
+			http.Error(w, "Invalid Authorization header", http.StatusBadRequest)
+			return
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(basicAuthData)
+		if err != nil {
+			http.Error(w, "Invalid Authorization header", http.StatusBadRequest)
+			return
+		}
+
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) != 2 {
+			http.Error(w, "Invalid Authorization header", http.StatusBadRequest)
+			return
+		}
+
+		username, password := parts[0], parts[1]
+		ok, err := authStorage.VerifyUserPassword(r.Context(), username, password)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user, err := authStorage.FetchUser(r.Context(), username)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := auth.ContextWithUser(r.Context(), user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -4,13 +4,11 @@ import (
 	"errors"
 	"log/slog"
 
-	"encoding/base64"
 	"encoding/json"
 
 	"fmt"
 	"slices"
 	"strconv"
-	"strings"
 
 	"net/http"
 
@@ -20,6 +18,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/internal/engines/pipelines"
 	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/timestamp"
 
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -51,55 +50,10 @@ func elasticProduct(next http.Handler) http.Handler {
 	})
 }
 
-// elasticAuth resolves the HTTP Basic credentials Elasticsearch clients send
-// into a FlowG user, so downstream handlers can enforce the user's permissions.
-func elasticAuth(deps ElasticDeps, next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
-		basicAuthData := authHeader[len("Basic "):]
-		decoded, err := base64.StdEncoding.DecodeString(basicAuthData)
-		if err != nil {
-			http.Error(w, "Invalid Authorization header", http.StatusBadRequest)
-			return
-		}
-
-		parts := strings.SplitN(string(decoded), ":", 2)
-		if len(parts) != 2 {
-			http.Error(w, "Invalid Authorization header", http.StatusBadRequest)
-			return
-		}
-
-		username, password := parts[0], parts[1]
-		ok, err := deps.AuthStorage.VerifyUserPassword(r.Context(), username, password)
-		if err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
-		}
-
-		if !ok {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
-		user, err := deps.AuthStorage.FetchUser(r.Context(), username)
-		if err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
-		}
-
-		ctx := auth.ContextWithUser(r.Context(), user)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
-}
-
 // NewElasticHandler serves the subset of the Elasticsearch API that log
 // shippers exercise: checking that an index (a FlowG pipeline) exists, and
-// indexing a document (running a log record through that pipeline).
+// indexing documents, one at a time or through the bulk API (running each of
+// them as a log record through that pipeline).
 func NewElasticHandler(deps ElasticDeps) http.Handler {
 	logger := slog.Default().With(slog.String("channel", "input.middleware.elastic"))
 	mux := http.NewServeMux()
@@ -109,11 +63,11 @@ func NewElasticHandler(deps ElasticDeps) http.Handler {
 		func(w http.ResponseWriter, r *http.Request) {
 			index := r.PathValue("index")
 
-			user := auth.GetContextUser(r.Context())
-			authorized, err := deps.AuthStorage.VerifyUserPermission(
+			authorized, err := auth.VerifyPermission(
 				r.Context(),
-				user.Name,
+				deps.AuthStorage,
 				models.SCOPE_READ_PIPELINES,
+				models.Resource{"pipeline": index},
 			)
 			if err != nil {
 				logger.ErrorContext(
@@ -155,11 +109,11 @@ func NewElasticHandler(deps ElasticDeps) http.Handler {
 		func(w http.ResponseWriter, r *http.Request) {
 			index := r.PathValue("index")
 
-			user := auth.GetContextUser(r.Context())
-			authorized, err := deps.AuthStorage.VerifyUserPermission(
+			authorized, err := auth.VerifyPermission(
 				r.Context(),
-				user.Name,
+				deps.AuthStorage,
 				models.SCOPE_SEND_LOGS,
+				models.Resource{"pipeline": index},
 			)
 			if err != nil {
 				logger.ErrorContext(
@@ -191,48 +145,20 @@ func NewElasticHandler(deps ElasticDeps) http.Handler {
 				return
 			}
 
-			fields := map[string]string{}
-
-			joinKey := func(prefix, key string) string {
-				if prefix == "" {
-					return key
-				}
-				return prefix + "." + key
-			}
-
-			var flatten func(prefix string, v any)
-			flatten = func(prefix string, v any) {
-				switch t := v.(type) {
-				case map[string]any:
-					for k, val := range t {
-						flatten(joinKey(prefix, k), val)
-					}
-
-				case []any:
-					for i, val := range t {
-						flatten(joinKey(prefix, strconv.Itoa(i)), val)
-					}
-
-				case nil:
-					fields[prefix] = ""
-
-				case string:
-					fields[prefix] = t
-
-				case json.Number:
-					fields[prefix] = t.String()
-
-				case bool:
-					fields[prefix] = strconv.FormatBool(t)
-
-				default:
-					fields[prefix] = fmt.Sprint(t)
-				}
+			systemConfig, err := deps.ConfigStorage.ReadSystemConfig(r.Context())
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"Failed to read system configuration",
+					slog.String("error", err.Error()),
+				)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
 			}
 
-			flatten("", doc)
+			fields := flattenElasticDocument(doc)
 
-			record := models.NewLogRecord(fields)
+			record := newElasticRecord(fields, systemConfig.TimestampPolicy)
 			err = deps.PipelineRunner.Run(
 				r.Context(),
 				index,
@@ -240,7 +166,8 @@ func NewElasticHandler(deps ElasticDeps) http.Handler {
 				record,
 			)
 			if err != nil {
-				if errors.Is(err, &pipelines.PipelineNotFoundError{}) {
+				var notFoundErr *pipelines.PipelineNotFoundError
+				if errors.As(err, &notFoundErr) {
 					http.Error(w, err.Error(), http.StatusNotFound)
 					return
 				}
@@ -257,5 +184,79 @@ func NewElasticHandler(deps ElasticDeps) http.Handler {
 		},
 	)
 
-	return elasticProduct(elasticAuth(deps, mux))
+	mux.HandleFunc(
+		"POST /api/v1/middlewares/elastic/_bulk",
+		newElasticBulkHandler(deps, logger),
+	)
+
+	mux.HandleFunc(
+		"POST /api/v1/middlewares/elastic/{index}/_bulk",
+		newElasticBulkHandler(deps, logger),
+	)
+
+	return elasticProduct(basicAuth(deps.AuthStorage, mux))
+}
+
+// newElasticRecord builds the log record of an indexed document, stamped with
+// the document's "@timestamp" when it is a valid date within the bounds of
+// policy, and with the time of ingestion otherwise.
+func newElasticRecord(fields map[string]string, policy models.TimestampPolicy) *models.LogRecord {
+	record := models.NewLogRecord(fields)
+
+	if value, exists := fields["@timestamp"]; exists {
+		ts, err := timestamp.Parse(value, timestamp.FORMAT_AUTO)
+		if err == nil {
+			record.Timestamp = timestamp.ApplyPolicy(policy, ts, record.Timestamp)
+		}
+	}
+
+	return record
+}
+
+// flattenElasticDocument turns a JSON document into log record fields: nested
+// objects and arrays are flattened into dot-separated keys, and scalar values
+// are formatted as strings.
+func flattenElasticDocument(doc map[string]any) map[string]string {
+	fields := map[string]string{}
+
+	joinKey := func(prefix, key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	var flatten func(prefix string, v any)
+	flatten = func(prefix string, v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, val := range t {
+				flatten(joinKey(prefix, k), val)
+			}
+
+		case []any:
+			for i, val := range t {
+				flatten(joinKey(prefix, strconv.Itoa(i)), val)
+			}
+
+		case nil:
+			fields[prefix] = ""
+
+		case string:
+			fields[prefix] = t
+
+		case json.Number:
+			fields[prefix] = t.String()
+
+		case bool:
+			fields[prefix] = strconv.FormatBool(t)
+
+		default:
+			fields[prefix] = fmt.Sprint(t)
+		}
+	}
+
+	flatten("", doc)
+
+	return fields
 }
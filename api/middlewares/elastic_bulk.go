@@ -0,0 +1,307 @@
+package middlewares
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"encoding/json"
+
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/internal/engines/pipelines"
+	"link-society.com/flowg/internal/models"
+)
+
+// maxElasticBulkBodySize bounds the size of the body of a bulk request, and
+// thus of each of its lines.
+const maxElasticBulkBodySize = 64 << 20
+
+// elasticBulkAction is the action line preceding each document of a bulk
+// request, e.g. {"index": {"_index": "my-pipeline"}}.
+type elasticBulkAction map[string]struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id"`
+}
+
+// elasticBulkItemError describes why an item of a bulk request failed, in the
+// shape Elasticsearch clients expect.
+type elasticBulkItemError struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// elasticBulkItemResult is the outcome of one item of a bulk request.
+type elasticBulkItemResult struct {
+	Index  string                `json:"_index,omitempty"`
+	ID     string                `json:"_id,omitempty"`
+	Status int                   `json:"status"`
+	Result string                `json:"result,omitempty"`
+	Error  *elasticBulkItemError `json:"error,omitempty"`
+}
+
+// elasticBulkResponse is the body of a bulk response: one result per action,
+// in request order, keyed by the action's name.
+type elasticBulkResponse struct {
+	Took   int64                              `json:"took"`
+	Errors bool                               `json:"errors"`
+	Items  []map[string]elasticBulkItemResult `json:"items"`
+}
+
+// newElasticBulkHandler serves the bulk API: the body is a sequence of NDJSON
+// action/document pairs, and each document is run through the pipeline named
+// by its action's "_index", defaulting to the one in the URL.
+//
+// Only the "index" and "create" actions carry documents FlowG can ingest;
+// "update" and "delete" items are rejected. Failures are reported per item, as
+// Elasticsearch does, so that clients only retry the documents that failed.
+// Bodies larger than maxElasticBulkBodySize are rejected with a 413, the items
+// read until then having already been ingested.
+func newElasticBulkHandler(deps ElasticDeps, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		defaultIndex := r.PathValue("index")
+
+		authorized, err := auth.VerifyAnyPermission(
+			r.Context(),
+			deps.AuthStorage,
+			models.SCOPE_SEND_LOGS,
+		)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to verify user permission",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		if !authorized {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		canSendLogs, err := auth.ResourceFilter(
+			r.Context(),
+			deps.AuthStorage,
+			models.SCOPE_SEND_LOGS,
+		)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to verify user permission",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		systemConfig, err := deps.ConfigStorage.ReadSystemConfig(r.Context())
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to read system configuration",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		defer r.Body.Close()
+		reader := bufio.NewReader(http.MaxBytesReader(w, r.Body, maxElasticBulkBodySize))
+
+		resp := elasticBulkResponse{
+			Items: []map[string]elasticBulkItemResult{},
+		}
+
+		for {
+			line, err := readElasticBulkLine(reader)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if isElasticBulkTooLarge(err) {
+				http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"Failed to read request body",
+					slog.String("error", err.Error()),
+				)
+				http.Error(w, "Bad Request", http.StatusBadRequest)
+				return
+			}
+
+			var action elasticBulkAction
+			if err := json.Unmarshal(line, &action); err != nil || len(action) != 1 {
+				http.Error(w, "Malformed action/metadata line", http.StatusBadRequest)
+				return
+			}
+
+			for name, meta := range action {
+				result := elasticBulkItemResult{
+					Index: meta.Index,
+					ID:    meta.ID,
+				}
+				if result.Index == "" {
+					result.Index = defaultIndex
+				}
+
+				var source []byte
+				if name != "delete" {
+					source, err = readElasticBulkLine(reader)
+					if isElasticBulkTooLarge(err) {
+						http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+						return
+					}
+					if err != nil {
+						http.Error(w, "Missing document after action/metadata line", http.StatusBadRequest)
+						return
+					}
+				}
+
+				switch {
+				case name != "index" && name != "create":
+					result.Status = http.StatusBadRequest
+					result.Error = &elasticBulkItemError{
+						Type:   "action_request_validation_exception",
+						Reason: "unsupported action: " + name,
+					}
+
+				case result.Index == "":
+					result.Status = http.StatusBadRequest
+					result.Error = &elasticBulkItemError{
+						Type:   "action_request_validation_exception",
+						Reason: "index is missing",
+					}
+
+				case !canSendLogs(result.Index):
+					result.Status = http.StatusForbidden
+					result.Error = &elasticBulkItemError{
+						Type:   "security_exception",
+						Reason: "not allowed to send logs to index: " + result.Index,
+					}
+
+				default:
+					if result.ID == "" {
+						result.ID = uuid.NewString()
+					}
+					runElasticBulkItem(deps, logger, r, systemConfig.TimestampPolicy, source, &result)
+				}
+
+				if result.Error != nil {
+					resp.Errors = true
+				}
+
+				resp.Items = append(resp.Items, map[string]elasticBulkItemResult{name: result})
+			}
+		}
+
+		resp.Took = time.Since(start).Milliseconds()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to encode response",
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// runElasticBulkItem runs one document of a bulk request through the pipeline
+// named by the item's index, recording the outcome in result.
+func runElasticBulkItem(
+	deps ElasticDeps,
+	logger *slog.Logger,
+	r *http.Request,
+	policy models.TimestampPolicy,
+	source []byte,
+	result *elasticBulkItemResult,
+) {
+	dec := json.NewDecoder(bytes.NewReader(source))
+	dec.UseNumber()
+
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		result.Status = http.StatusBadRequest
+		result.Error = &elasticBulkItemError{
+			Type:   "document_parsing_exception",
+			Reason: err.Error(),
+		}
+		return
+	}
+
+	record := newElasticRecord(flattenElasticDocument(doc), policy)
+	err := deps.PipelineRunner.Run(
+		r.Context(),
+		result.Index,
+		pipelines.DIRECT_ENTRYPOINT,
+		record,
+	)
+	if err != nil {
+		var notFoundErr *pipelines.PipelineNotFoundError
+		if errors.As(err, &notFoundErr) {
+			result.Status = http.StatusNotFound
+			result.Error = &elasticBulkItemError{
+				Type:   "index_not_found_exception",
+				Reason: err.Error(),
+			}
+			return
+		}
+
+		logger.ErrorContext(
+			r.Context(),
+			"Failed to process log entry",
+			slog.String("pipeline", result.Index),
+			slog.String("error", err.Error()),
+		)
+
+		result.Status = http.StatusInternalServerError
+		result.Error = &elasticBulkItemError{
+			Type:   "exception",
+			Reason: err.Error(),
+		}
+		return
+	}
+
+	result.Status = http.StatusCreated
+	result.Result = "created"
+}
+
+// readElasticBulkLine returns the next non-blank line of a bulk request body,
+// or io.EOF once the body is exhausted. A line cut short by any other error is
+// not returned.
+func readElasticBulkLine(reader *bufio.Reader) ([]byte, error) {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		line = bytes.TrimSpace(line)
+
+		if len(line) > 0 {
+			return line, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isElasticBulkTooLarge reports whether reading a bulk request body failed
+// because it exceeds maxElasticBulkBodySize.
+func isElasticBulkTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
@@ -2,13 +2,17 @@ package middlewares_test
 
 import (
 	"testing"
+	"time"
 
+	"net/http"
 	"net/http/httptest"
 
 	"github.com/stretchr/testify/mock"
 
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/elastic/go-elasticsearch/v9"
 
@@ -42,11 +46,13 @@ func TestElasticEndpoint(t *testing.T) {
 			},
 			nil,
 		)
-	mockAuthStorage.On("VerifyUserPermission", mock.Anything, "test", mock.Anything).
+	mockAuthStorage.On("VerifyUserPermission", mock.Anything, "test", mock.Anything, mock.Anything).
 		Return(true, nil)
 
 	mockConfigStorage.On("ListPipelines", mock.Anything).
 		Return([]string{"test"}, nil)
+	mockConfigStorage.On("ReadSystemConfig", mock.Anything).
+		Return(&models.SystemConfiguration{}, nil)
 
 	mockPipelineRunner.On("Run", mock.Anything, "test", pipelines.DIRECT_ENTRYPOINT, mock.Anything).
 		Return(nil)
@@ -93,3 +99,171 @@ func TestElasticEndpoint(t *testing.T) {
 	mockConfigStorage.AssertExpectations(t)
 	mockPipelineRunner.AssertExpectations(t)
 }
+
+// TestElasticBulkEndpoint guards against bulk requests failing as a whole when
+// only some of their documents are rejected, against documents being run
+// through the wrong pipeline, and against their "@timestamp" being dropped.
+func TestElasticBulkEndpoint(t *testing.T) {
+	mockAuthStorage := storageMocks.NewMockAuthStorage().(*storageMocks.MockAuthStorage)
+	mockConfigStorage := storageMocks.NewMockConfigStorage().(*storageMocks.MockConfigStorage)
+	mockPipelineRunner := pipelinesMocks.NewMockRunner().(*pipelinesMocks.MockRunner)
+
+	deps := middlewares.ElasticDeps{
+		AuthStorage:    mockAuthStorage,
+		ConfigStorage:  mockConfigStorage,
+		PipelineRunner: mockPipelineRunner,
+	}
+
+	mockAuthStorage.On("VerifyUserPassword", mock.Anything, "test", "test").
+		Return(true, nil)
+	mockAuthStorage.On("FetchUser", mock.Anything, "test").
+		Return(&models.User{Name: "test", Roles: []string{"admin"}}, nil)
+	mockAuthStorage.On("VerifyUserPermission", mock.Anything, "test", models.SCOPE_SEND_LOGS, mock.Anything).
+		Return(true, nil)
+
+	mockConfigStorage.On("ReadSystemConfig", mock.Anything).
+		Return(&models.SystemConfiguration{}, nil)
+
+	backfilled := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mockPipelineRunner.On("Run", mock.Anything, "test", pipelines.DIRECT_ENTRYPOINT, mock.Anything).
+		Return(nil).
+		Once()
+	mockPipelineRunner.On(
+		"Run",
+		mock.Anything,
+		"test",
+		pipelines.DIRECT_ENTRYPOINT,
+		mock.MatchedBy(func(record *models.LogRecord) bool {
+			return record.Timestamp.Equal(backfilled)
+		}),
+	).
+		Return(nil).
+		Once()
+	mockPipelineRunner.On("Run", mock.Anything, "missing", pipelines.DIRECT_ENTRYPOINT, mock.Anything).
+		Return(&pipelines.PipelineNotFoundError{Pipeline: "missing"}).
+		Once()
+
+	server := httptest.NewServer(middlewares.NewElasticHandler(deps))
+	defer server.Close()
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Username:  "test",
+		Password:  "test",
+		Addresses: []string{fmt.Sprintf("%s/api/v1/middlewares/elastic/", server.URL)},
+	})
+	if err != nil {
+		t.Fatalf("failed to create ElasticSearch client: %v", err)
+	}
+
+	body := bytes.NewReader([]byte(
+		`{"index": {}}` + "\n" +
+			`{"message": "default index"}` + "\n" +
+			`{"create": {"_index": "missing"}}` + "\n" +
+			`{"message": "unknown pipeline"}` + "\n" +
+			`{"delete": {"_id": "1"}}` + "\n" +
+			`{"index": {"_index": "test", "_id": "2"}}` + "\n" +
+			`{"@timestamp": "2024-05-01T12:00:00Z", "message": "explicit index"}` + "\n",
+	))
+
+	resp, err := client.Bulk(body, client.Bulk.WithIndex("test"), client.Bulk.WithContext(t.Context()))
+	if err != nil {
+		t.Fatalf("failed to send ElasticSearch request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		t.Fatalf("failed to send bulk request: %s", resp.String())
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Index  string `json:"_index"`
+			ID     string `json:"_id"`
+			Status int    `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode bulk response: %v", err)
+	}
+
+	if !result.Errors {
+		t.Fatalf("expected the bulk response to report errors")
+	}
+
+	expected := []struct {
+		action string
+		index  string
+		status int
+	}{
+		{"index", "test", 201},
+		{"create", "missing", 404},
+		{"delete", "test", 400},
+		{"index", "test", 201},
+	}
+
+	if len(result.Items) != len(expected) {
+		t.Fatalf("expected %d items, got %d", len(expected), len(result.Items))
+	}
+
+	for i, e := range expected {
+		item, exists := result.Items[i][e.action]
+		if !exists || item.Index != e.index || item.Status != e.status {
+			t.Fatalf("item %d: expected %s on %s with status %d, got %+v", i, e.action, e.index, e.status, result.Items[i])
+		}
+	}
+
+	if result.Items[3]["index"].ID != "2" {
+		t.Fatalf("expected the document ID to be kept, got %q", result.Items[3]["index"].ID)
+	}
+
+	mockPipelineRunner.AssertExpectations(t)
+}
+
+func TestElasticBulkEndpointTooLarge(t *testing.T) {
+	mockAuthStorage := storageMocks.NewMockAuthStorage().(*storageMocks.MockAuthStorage)
+	mockConfigStorage := storageMocks.NewMockConfigStorage().(*storageMocks.MockConfigStorage)
+	mockPipelineRunner := pipelinesMocks.NewMockRunner().(*pipelinesMocks.MockRunner)
+
+	deps := middlewares.ElasticDeps{
+		AuthStorage:    mockAuthStorage,
+		ConfigStorage:  mockConfigStorage,
+		PipelineRunner: mockPipelineRunner,
+	}
+
+	mockAuthStorage.On("VerifyUserPassword", mock.Anything, "test", "test").
+		Return(true, nil)
+	mockAuthStorage.On("FetchUser", mock.Anything, "test").
+		Return(&models.User{Name: "test", Roles: []string{"admin"}}, nil)
+	mockAuthStorage.On("VerifyUserPermission", mock.Anything, "test", models.SCOPE_SEND_LOGS, mock.Anything).
+		Return(true, nil)
+
+	mockConfigStorage.On("ReadSystemConfig", mock.Anything).
+		Return(&models.SystemConfiguration{}, nil)
+
+	server := httptest.NewServer(middlewares.NewElasticHandler(deps))
+	defer server.Close()
+
+	// A single line, without newline, larger than the body limit.
+	body := strings.NewReader(`{"index": {"_index": "` + strings.Repeat("a", 64<<20) + `"}}`)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/v1/middlewares/elastic/_bulk", body)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.SetBasicAuth("test", "test")
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected the oversized body to be rejected, got %d", resp.StatusCode)
+	}
+
+	mockPipelineRunner.AssertNotCalled(t, "Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,220 @@
+package middlewares
+
+import (
+	"errors"
+	"log/slog"
+
+	"maps"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/internal/engines/pipelines"
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/timestamp"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// LOKI_PIPELINE_LABEL is the stream label selecting the pipeline its entries
+// run through, taking precedence over the pipeline of the URL.
+const LOKI_PIPELINE_LABEL = "flowg_pipeline"
+
+// LOKI_LINE_FIELD is the log record field holding the line of an entry.
+const LOKI_LINE_FIELD = "line"
+
+// LokiDeps lists the dependencies of [NewLokiHandler]: the backends it uses to
+// authenticate callers and turn the entries they push into pipeline runs.
+type LokiDeps struct {
+	fx.In
+
+	AuthStorage    storage.AuthStorage
+	ConfigStorage  storage.ConfigStorage
+	PipelineRunner pipelines.Runner
+}
+
+func init() {
+	routing.RegisterMiddleware(
+		NewLokiHandler,
+		"/api/v1/middlewares/loki/",
+	)
+}
+
+// NewLokiHandler serves the push endpoint of the Loki API, as used by
+// Promtail, Grafana Agent and Alloy. Each entry of the pushed streams is run
+// as a log record through the pipeline named by the stream's
+// [LOKI_PIPELINE_LABEL] label, or by the URL otherwise.
+func NewLokiHandler(deps LokiDeps) http.Handler {
+	logger := slog.Default().With(slog.String("channel", "input.middleware.loki"))
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(
+		"POST /api/v1/middlewares/loki/loki/api/v1/push",
+		newLokiPushHandler(deps, logger),
+	)
+
+	mux.HandleFunc(
+		"POST /api/v1/middlewares/loki/{pipeline}/loki/api/v1/push",
+		newLokiPushHandler(deps, logger),
+	)
+
+	return basicAuth(deps.AuthStorage, mux)
+}
+
+func newLokiPushHandler(deps LokiDeps, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defaultPipeline := r.PathValue("pipeline")
+
+		authorized, err := auth.VerifyAnyPermission(
+			r.Context(),
+			deps.AuthStorage,
+			models.SCOPE_SEND_LOGS,
+		)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to verify user permission",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		if !authorized {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		defer r.Body.Close()
+		streams, err := decodeLokiPushRequest(
+			http.MaxBytesReader(w, r.Body, maxLokiBodySize),
+			r.Header.Get("Content-Type"),
+			r.Header.Get("Content-Encoding"),
+		)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) || errors.Is(err, errLokiBodyTooLarge) {
+				http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to decode request body",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		canSendLogs, err := auth.ResourceFilter(
+			r.Context(),
+			deps.AuthStorage,
+			models.SCOPE_SEND_LOGS,
+		)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to verify user permission",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		// Every stream must be routable, to a pipeline the caller may send logs
+		// to, before any of them is ingested, so a rejected push can be retried
+		// as a whole.
+		streamPipelines := make([]string, len(streams))
+		for i, stream := range streams {
+			pipeline := defaultPipeline
+			if label, exists := stream.Labels[LOKI_PIPELINE_LABEL]; exists && label != "" {
+				pipeline = label
+			}
+
+			if pipeline == "" {
+				http.Error(
+					w,
+					"No pipeline selected, set the "+LOKI_PIPELINE_LABEL+" label or push to /{pipeline}/loki/api/v1/push",
+					http.StatusBadRequest,
+				)
+				return
+			}
+
+			if !canSendLogs(pipeline) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			streamPipelines[i] = pipeline
+		}
+
+		systemConfig, err := deps.ConfigStorage.ReadSystemConfig(r.Context())
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to read system configuration",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		for i, stream := range streams {
+			pipeline := streamPipelines[i]
+
+			for _, entry := range stream.Entries {
+				record := newLokiRecord(stream.Labels, entry, systemConfig.TimestampPolicy)
+				err := deps.PipelineRunner.Run(
+					r.Context(),
+					pipeline,
+					pipelines.DIRECT_ENTRYPOINT,
+					record,
+				)
+				if err != nil {
+					var notFoundErr *pipelines.PipelineNotFoundError
+					if errors.As(err, &notFoundErr) {
+						http.Error(w, err.Error(), http.StatusNotFound)
+						return
+					}
+
+					logger.ErrorContext(
+						r.Context(),
+						"Failed to process log entry",
+						slog.String("pipeline", pipeline),
+						slog.String("error", err.Error()),
+					)
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// newLokiRecord builds the log record of a stream entry: the stream labels and
+// the entry's structured metadata become fields, alongside the line itself.
+// The record is stamped with the entry's timestamp when it is within the
+// bounds of policy, and with the time of ingestion otherwise.
+func newLokiRecord(
+	labels map[string]string,
+	entry lokiEntry,
+	policy models.TimestampPolicy,
+) *models.LogRecord {
+	fields := make(map[string]string, len(labels)+len(entry.Metadata)+1)
+	maps.Copy(fields, labels)
+	maps.Copy(fields, entry.Metadata)
+	fields[LOKI_LINE_FIELD] = entry.Line
+
+	record := models.NewLogRecord(fields)
+	if !entry.Timestamp.IsZero() && entry.Timestamp.UnixNano() != 0 {
+		record.Timestamp = timestamp.ApplyPolicy(policy, entry.Timestamp, record.Timestamp)
+	}
+
+	return record
+}
@@ -0,0 +1,360 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// maxLokiBodySize bounds the size of the body of a push request, both as sent
+// and once decompressed.
+const maxLokiBodySize = 64 << 20
+
+// errLokiBodyTooLarge is returned when the body of a push request exceeds
+// maxLokiBodySize once decompressed.
+var errLokiBodyTooLarge = fmt.Errorf("decompressed body exceeds %d bytes", maxLokiBodySize)
+
+// lokiStream is a stream of a Loki push request: the entries sharing a set of
+// labels.
+type lokiStream struct {
+	Labels  map[string]string
+	Entries []lokiEntry
+}
+
+// lokiEntry is a log line of a Loki stream, with its structured metadata.
+type lokiEntry struct {
+	Timestamp time.Time
+	Line      string
+	Metadata  map[string]string
+}
+
+// decodeLokiPushRequest decodes the body of a Loki push request, either
+// snappy-compressed protobuf (the default of Promtail, Grafana Agent and
+// Alloy) or JSON, optionally gzip-compressed. Compressed bodies larger than
+// maxLokiBodySize once decompressed are rejected with errLokiBodyTooLarge
+// before being decompressed in full.
+func decodeLokiPushRequest(body io.Reader, contentType string, contentEncoding string) ([]lokiStream, error) {
+	if strings.HasPrefix(contentType, "application/json") {
+		if contentEncoding == "gzip" {
+			reader, err := gzip.NewReader(body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid gzip body: %w", err)
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(io.LimitReader(reader, maxLokiBodySize+1))
+			if err != nil {
+				return nil, fmt.Errorf("invalid gzip body: %w", err)
+			}
+			if len(data) > maxLokiBodySize {
+				return nil, errLokiBodyTooLarge
+			}
+
+			body = bytes.NewReader(data)
+		}
+
+		return decodeLokiJsonPushRequest(body)
+	}
+
+	compressed, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snappy body: %w", err)
+	}
+	if size > maxLokiBodySize {
+		return nil, errLokiBodyTooLarge
+	}
+
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snappy body: %w", err)
+	}
+
+	return decodeLokiProtobufPushRequest(data)
+}
+
+// decodeLokiJsonPushRequest decodes the JSON flavor of a push request:
+//
+//	{"streams": [{"stream": {"job": "app"}, "values": [["<unix ns>", "line", {"key": "value"}]]}]}
+func decodeLokiJsonPushRequest(body io.Reader) ([]lokiStream, error) {
+	var req struct {
+		Streams []struct {
+			Stream map[string]string   `json:"stream"`
+			Values [][]json.RawMessage `json:"values"`
+		} `json:"streams"`
+	}
+
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	streams := make([]lokiStream, len(req.Streams))
+
+	for i, s := range req.Streams {
+		streams[i] = lokiStream{
+			Labels:  s.Stream,
+			Entries: make([]lokiEntry, len(s.Values)),
+		}
+
+		if streams[i].Labels == nil {
+			streams[i].Labels = map[string]string{}
+		}
+
+		for j, value := range s.Values {
+			if len(value) < 2 || len(value) > 3 {
+				return nil, fmt.Errorf("stream %d, entry %d: expected [timestamp, line, metadata?]", i, j)
+			}
+
+			var tsValue, line string
+			if err := json.Unmarshal(value[0], &tsValue); err != nil {
+				return nil, fmt.Errorf("stream %d, entry %d: invalid timestamp: %w", i, j, err)
+			}
+			if err := json.Unmarshal(value[1], &line); err != nil {
+				return nil, fmt.Errorf("stream %d, entry %d: invalid line: %w", i, j, err)
+			}
+
+			ns, err := strconv.ParseInt(tsValue, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("stream %d, entry %d: invalid timestamp: %w", i, j, err)
+			}
+
+			entry := lokiEntry{
+				Timestamp: time.Unix(0, ns),
+				Line:      line,
+			}
+
+			if len(value) == 3 {
+				if err := json.Unmarshal(value[2], &entry.Metadata); err != nil {
+					return nil, fmt.Errorf("stream %d, entry %d: invalid metadata: %w", i, j, err)
+				}
+			}
+
+			streams[i].Entries[j] = entry
+		}
+	}
+
+	return streams, nil
+}
+
+// decodeLokiProtobufPushRequest decodes the protobuf flavor of a push request,
+// as defined by Loki's logproto package:
+//
+//	message PushRequest { repeated StreamAdapter streams = 1; }
+//	message StreamAdapter { string labels = 1; repeated EntryAdapter entries = 2; uint64 hash = 3; }
+//	message EntryAdapter { google.protobuf.Timestamp timestamp = 1; string line = 2; repeated LabelPairAdapter structuredMetadata = 3; }
+//	message LabelPairAdapter { string name = 1; string value = 2; }
+func decodeLokiProtobufPushRequest(data []byte) ([]lokiStream, error) {
+	var streams []lokiStream
+
+	err := walkProtobufMessage(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != 1 || typ != protowire.BytesType {
+			return nil
+		}
+
+		stream, err := decodeLokiProtobufStream(value)
+		if err != nil {
+			return fmt.Errorf("stream %d: %w", len(streams), err)
+		}
+
+		streams = append(streams, stream)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return streams, nil
+}
+
+func decodeLokiProtobufStream(data []byte) (lokiStream, error) {
+	stream := lokiStream{Labels: map[string]string{}}
+
+	err := walkProtobufMessage(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+
+		switch num {
+		case 1:
+			labels, err := parseLokiLabels(string(value))
+			if err != nil {
+				return err
+			}
+
+			stream.Labels = labels
+
+		case 2:
+			entry, err := decodeLokiProtobufEntry(value)
+			if err != nil {
+				return fmt.Errorf("entry %d: %w", len(stream.Entries), err)
+			}
+
+			stream.Entries = append(stream.Entries, entry)
+		}
+
+		return nil
+	})
+
+	return stream, err
+}
+
+func decodeLokiProtobufEntry(data []byte) (lokiEntry, error) {
+	entry := lokiEntry{}
+
+	var seconds, nanos int64
+
+	err := walkProtobufMessage(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+
+		switch num {
+		case 1:
+			return walkProtobufMessage(value, func(num protowire.Number, typ protowire.Type, value []byte) error {
+				if typ != protowire.VarintType {
+					return nil
+				}
+
+				v, _ := protowire.ConsumeVarint(value)
+				switch num {
+				case 1:
+					seconds = int64(v)
+				case 2:
+					nanos = int64(int32(v))
+				}
+
+				return nil
+			})
+
+		case 2:
+			entry.Line = string(value)
+
+		case 3:
+			var name, labelValue string
+
+			err := walkProtobufMessage(value, func(num protowire.Number, typ protowire.Type, value []byte) error {
+				if typ != protowire.BytesType {
+					return nil
+				}
+
+				switch num {
+				case 1:
+					name = string(value)
+				case 2:
+					labelValue = string(value)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if entry.Metadata == nil {
+				entry.Metadata = map[string]string{}
+			}
+			entry.Metadata[name] = labelValue
+		}
+
+		return nil
+	})
+
+	entry.Timestamp = time.Unix(seconds, nanos)
+	return entry, err
+}
+
+// walkProtobufMessage calls visit with the number, wire type and raw value of
+// each field of a protobuf message. Varint values are passed encoded, and
+// length-delimited values without their length prefix.
+func walkProtobufMessage(
+	data []byte,
+	visit func(num protowire.Number, typ protowire.Type, value []byte) error,
+) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("invalid protobuf message: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		var value []byte
+
+		if typ == protowire.BytesType {
+			v, m := protowire.ConsumeBytes(data)
+			if m < 0 {
+				return fmt.Errorf("invalid protobuf message: %w", protowire.ParseError(m))
+			}
+
+			value = v
+			n = m
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return fmt.Errorf("invalid protobuf message: %w", protowire.ParseError(n))
+			}
+
+			value = data[:n]
+		}
+
+		if err := visit(num, typ, value); err != nil {
+			return err
+		}
+
+		data = data[n:]
+	}
+
+	return nil
+}
+
+// parseLokiLabels parses the labels of a stream, in the Prometheus notation
+// used by the protobuf flavor: {job="app", env="prod"}.
+func parseLokiLabels(notation string) (map[string]string, error) {
+	labels := map[string]string{}
+
+	rest := strings.TrimSpace(notation)
+	if !strings.HasPrefix(rest, "{") || !strings.HasSuffix(rest, "}") {
+		return nil, fmt.Errorf("invalid labels %q", notation)
+	}
+	rest = strings.TrimSpace(rest[1 : len(rest)-1])
+
+	for rest != "" {
+		name, value, found := strings.Cut(rest, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid labels %q", notation)
+		}
+
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labels %q", notation)
+		}
+
+		unquoted, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("invalid labels %q", notation), err)
+		}
+
+		labels[name] = unquoted
+
+		rest = strings.TrimSpace(value[len(quoted):])
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, ","))
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,223 @@
+package middlewares_test
+
+import (
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"bytes"
+	"fmt"
+
+	"link-society.com/flowg/internal/engines/pipelines"
+	"link-society.com/flowg/internal/models"
+
+	pipelinesMocks "link-society.com/flowg/internal/engines/pipelines/mocks"
+	storageMocks "link-society.com/flowg/internal/storage/mocks"
+
+	"link-society.com/flowg/api/middlewares"
+)
+
+// TestLokiPushEndpoint guards against entries losing their labels, metadata or
+// timestamp, against streams being run through the wrong pipeline, for both the
+// protobuf and the JSON flavors of the push API, and against oversized pushes
+// being decompressed or read in full.
+func TestLokiPushEndpoint(t *testing.T) {
+	mockAuthStorage := storageMocks.NewMockAuthStorage().(*storageMocks.MockAuthStorage)
+	mockConfigStorage := storageMocks.NewMockConfigStorage().(*storageMocks.MockConfigStorage)
+	mockPipelineRunner := pipelinesMocks.NewMockRunner().(*pipelinesMocks.MockRunner)
+
+	deps := middlewares.LokiDeps{
+		AuthStorage:    mockAuthStorage,
+		ConfigStorage:  mockConfigStorage,
+		PipelineRunner: mockPipelineRunner,
+	}
+
+	mockAuthStorage.On("VerifyUserPassword", mock.Anything, "test", "test").
+		Return(true, nil)
+	mockAuthStorage.On("FetchUser", mock.Anything, "test").
+		Return(&models.User{Name: "test", Roles: []string{"admin"}}, nil)
+	mockAuthStorage.On("VerifyUserPermission", mock.Anything, "test", models.SCOPE_SEND_LOGS, mock.Anything).
+		Return(true, nil)
+
+	mockConfigStorage.On("ReadSystemConfig", mock.Anything).
+		Return(&models.SystemConfiguration{}, nil)
+
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+
+	matchRecord := func(line string, fields map[string]string) any {
+		return mock.MatchedBy(func(record *models.LogRecord) bool {
+			if !record.Timestamp.Equal(ts) || record.Fields["line"] != line {
+				return false
+			}
+
+			for key, value := range fields {
+				if record.Fields[key] != value {
+					return false
+				}
+			}
+
+			return true
+		})
+	}
+
+	mockPipelineRunner.On(
+		"Run",
+		mock.Anything,
+		"default",
+		pipelines.DIRECT_ENTRYPOINT,
+		matchRecord("protobuf line", map[string]string{"job": "app", "trace_id": "abc"}),
+	).
+		Return(nil).
+		Once()
+	mockPipelineRunner.On(
+		"Run",
+		mock.Anything,
+		"labelled",
+		pipelines.DIRECT_ENTRYPOINT,
+		matchRecord("protobuf routed", map[string]string{"job": "worker"}),
+	).
+		Return(nil).
+		Once()
+	mockPipelineRunner.On(
+		"Run",
+		mock.Anything,
+		"labelled",
+		pipelines.DIRECT_ENTRYPOINT,
+		matchRecord("json line", map[string]string{"job": "api", "user": "alice"}),
+	).
+		Return(nil).
+		Once()
+
+	server := httptest.NewServer(middlewares.NewLokiHandler(deps))
+	defer server.Close()
+
+	push := func(path string, contentType string, body []byte) int {
+		req, err := http.NewRequestWithContext(
+			t.Context(),
+			http.MethodPost,
+			fmt.Sprintf("%s/api/v1/middlewares/loki/%s", server.URL, path),
+			bytes.NewReader(body),
+		)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		req.SetBasicAuth("test", "test")
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	protobufBody := snappy.Encode(nil, encodeLokiPushRequest(
+		encodeLokiStream(
+			`{job="app"}`,
+			encodeLokiEntry(ts, "protobuf line", map[string]string{"trace_id": "abc"}),
+		),
+		encodeLokiStream(
+			`{job="worker", flowg_pipeline="labelled"}`,
+			encodeLokiEntry(ts, "protobuf routed", nil),
+		),
+	))
+
+	status := push("default/loki/api/v1/push", "application/x-protobuf", protobufBody)
+	if status != http.StatusNoContent {
+		t.Fatalf("unexpected status for the protobuf push: %d", status)
+	}
+
+	jsonBody := fmt.Appendf(
+		nil,
+		`{"streams": [{"stream": {"job": "api", "flowg_pipeline": "labelled"}, "values": [["%d", "json line", {"user": "alice"}]]}]}`,
+		ts.UnixNano(),
+	)
+
+	status = push("loki/api/v1/push", "application/json", jsonBody)
+	if status != http.StatusNoContent {
+		t.Fatalf("unexpected status for the JSON push: %d", status)
+	}
+
+	status = push(
+		"loki/api/v1/push",
+		"application/json",
+		[]byte(`{"streams": [{"stream": {"job": "api"}, "values": [["0", "unrouted"]]}]}`),
+	)
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected a push without pipeline to be rejected, got: %d", status)
+	}
+
+	// a snappy block announcing 1 GiB of data, without the data
+	bombBody := protowire.AppendVarint(nil, 1<<30)
+	bombBody = append(bombBody, 0)
+
+	status = push("default/loki/api/v1/push", "application/x-protobuf", bombBody)
+	if status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected an oversized push to be rejected, got: %d", status)
+	}
+
+	status = push(
+		"default/loki/api/v1/push",
+		"application/json",
+		fmt.Appendf(nil, `{"streams": [], "padding": "%s"}`, bytes.Repeat([]byte("a"), 64<<20)),
+	)
+	if status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected an oversized push to be rejected, got: %d", status)
+	}
+
+	mockAuthStorage.AssertExpectations(t)
+	mockConfigStorage.AssertExpectations(t)
+	mockPipelineRunner.AssertExpectations(t)
+}
+
+func encodeLokiPushRequest(streams ...[]byte) []byte {
+	var data []byte
+	for _, stream := range streams {
+		data = protowire.AppendTag(data, 1, protowire.BytesType)
+		data = protowire.AppendBytes(data, stream)
+	}
+	return data
+}
+
+func encodeLokiStream(labels string, entries ...[]byte) []byte {
+	data := protowire.AppendTag(nil, 1, protowire.BytesType)
+	data = protowire.AppendString(data, labels)
+	for _, entry := range entries {
+		data = protowire.AppendTag(data, 2, protowire.BytesType)
+		data = protowire.AppendBytes(data, entry)
+	}
+	return data
+}
+
+func encodeLokiEntry(ts time.Time, line string, metadata map[string]string) []byte {
+	timestamp := protowire.AppendTag(nil, 1, protowire.VarintType)
+	timestamp = protowire.AppendVarint(timestamp, uint64(ts.Unix()))
+	timestamp = protowire.AppendTag(timestamp, 2, protowire.VarintType)
+	timestamp = protowire.AppendVarint(timestamp, uint64(ts.Nanosecond()))
+
+	data := protowire.AppendTag(nil, 1, protowire.BytesType)
+	data = protowire.AppendBytes(data, timestamp)
+	data = protowire.AppendTag(data, 2, protowire.BytesType)
+	data = protowire.AppendString(data, line)
+
+	for name, value := range metadata {
+		pair := protowire.AppendTag(nil, 1, protowire.BytesType)
+		pair = protowire.AppendString(pair, name)
+		pair = protowire.AppendTag(pair, 2, protowire.BytesType)
+		pair = protowire.AppendString(pair, value)
+
+		data = protowire.AppendTag(data, 3, protowire.BytesType)
+		data = protowire.AppendBytes(data, pair)
+	}
+
+	return data
+}
@@ -0,0 +1,243 @@
+package middlewares
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"encoding/json"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/internal/engines/pipelines"
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/timestamp"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// SplunkDeps lists the dependencies of [NewSplunkHandler]: the backends it
+// uses to authenticate callers and turn the events they send into pipeline
+// runs.
+type SplunkDeps struct {
+	fx.In
+
+	AuthStorage    storage.AuthStorage
+	ConfigStorage  storage.ConfigStorage
+	PipelineRunner pipelines.Runner
+}
+
+func init() {
+	routing.RegisterMiddleware(
+		NewSplunkHandler,
+		"/api/v1/middlewares/splunk/",
+	)
+}
+
+// NewSplunkHandler serves the event and raw endpoints of the Splunk HTTP Event
+// Collector (HEC) API. Each event is run as a log record through the pipeline
+// named by its index, or by its sourcetype when it has no index.
+func NewSplunkHandler(deps SplunkDeps) http.Handler {
+	logger := slog.Default().With(slog.String("channel", "input.middleware.splunk"))
+	mux := http.NewServeMux()
+
+	eventHandler := newSplunkHandler(deps, logger, decodeSplunkEvents)
+	rawHandler := newSplunkHandler(deps, logger, decodeSplunkRawEvents)
+
+	mux.HandleFunc("POST /api/v1/middlewares/splunk/services/collector", eventHandler)
+	mux.HandleFunc("POST /api/v1/middlewares/splunk/services/collector/event", eventHandler)
+	mux.HandleFunc("POST /api/v1/middlewares/splunk/services/collector/event/1.0", eventHandler)
+	mux.HandleFunc("POST /api/v1/middlewares/splunk/services/collector/raw", rawHandler)
+	mux.HandleFunc("POST /api/v1/middlewares/splunk/services/collector/raw/1.0", rawHandler)
+
+	return splunkAuth(deps.AuthStorage, logger, mux)
+}
+
+// splunkAuth resolves the "Splunk <token>" credential HEC clients send, where
+// the token is a FlowG personal access token, into a FlowG user, restricted to
+// the scopes of the token.
+func splunkAuth(authStorage storage.AuthStorage, logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			writeSplunkError(w, newSplunkTokenRequiredError())
+			return
+		}
+
+		token, found := strings.CutPrefix(authHeader, "Splunk ")
+		if !found || token == "" {
+			writeSplunkError(w, newSplunkInvalidAuthorizationError())
+			return
+		}
+
+		user, description, err := authStorage.VerifyToken(r.Context(), token)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to verify token",
+				slog.String("error", err.Error()),
+			)
+			writeSplunkError(w, newSplunkInternalError())
+			return
+		}
+
+		if user == nil {
+			writeSplunkError(w, newSplunkInvalidTokenError())
+			return
+		}
+
+		ctx := auth.ContextWithUser(r.Context(), user)
+		ctx = auth.ContextWithToken(ctx, description)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// newSplunkHandler serves a HEC endpoint whose body is decoded by decode.
+// Every event must select an existing pipeline the caller may send logs to
+// before any of them is ingested, so that a rejected request can be retried as
+// a whole.
+func newSplunkHandler(
+	deps SplunkDeps,
+	logger *slog.Logger,
+	decode func(body io.Reader, defaults splunkMetadata) ([]splunkEvent, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authorized, err := auth.VerifyAnyPermission(
+			r.Context(),
+			deps.AuthStorage,
+			models.SCOPE_SEND_LOGS,
+		)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to verify user permission",
+				slog.String("error", err.Error()),
+			)
+			writeSplunkError(w, newSplunkInternalError())
+			return
+		}
+
+		if !authorized {
+			writeSplunkError(w, newSplunkInvalidTokenError())
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := splunkRequestBody(w, r)
+		if err != nil {
+			writeSplunkError(w, splunkDecodeError(err, 0))
+			return
+		}
+		defer body.Close()
+
+		events, err := decode(body, splunkQueryMetadata(r.URL.Query()))
+		if err != nil {
+			var splunkErr *splunkError
+			if !errors.As(err, &splunkErr) {
+				splunkErr = newSplunkInternalError()
+			}
+
+			writeSplunkError(w, splunkErr)
+			return
+		}
+
+		pipelineNames, err := deps.ConfigStorage.ListPipelines(r.Context())
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to list pipelines",
+				slog.String("error", err.Error()),
+			)
+			writeSplunkError(w, newSplunkInternalError())
+			return
+		}
+
+		canSendLogs, err := auth.ResourceFilter(
+			r.Context(),
+			deps.AuthStorage,
+			models.SCOPE_SEND_LOGS,
+		)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to verify user permission",
+				slog.String("error", err.Error()),
+			)
+			writeSplunkError(w, newSplunkInternalError())
+			return
+		}
+
+		// As with Splunk, an index the token may not write to is reported as
+		// incorrect, not telling it apart from an unknown one.
+		for i, event := range events {
+			pipeline := event.Metadata.pipeline()
+			if !slices.Contains(pipelineNames, pipeline) || !canSendLogs(pipeline) {
+				writeSplunkError(w, newSplunkIncorrectIndexError(i))
+				return
+			}
+		}
+
+		systemConfig, err := deps.ConfigStorage.ReadSystemConfig(r.Context())
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to read system configuration",
+				slog.String("error", err.Error()),
+			)
+			writeSplunkError(w, newSplunkInternalError())
+			return
+		}
+
+		for i, event := range events {
+			pipeline := event.Metadata.pipeline()
+
+			record := models.NewLogRecord(event.Fields)
+			if !event.Time.IsZero() {
+				record.Timestamp = timestamp.ApplyPolicy(
+					systemConfig.TimestampPolicy,
+					event.Time,
+					record.Timestamp,
+				)
+			}
+
+			err := deps.PipelineRunner.Run(
+				r.Context(),
+				pipeline,
+				pipelines.DIRECT_ENTRYPOINT,
+				record,
+			)
+			if err != nil {
+				var notFoundErr *pipelines.PipelineNotFoundError
+				if errors.As(err, &notFoundErr) {
+					writeSplunkError(w, newSplunkIncorrectIndexError(i))
+					return
+				}
+
+				logger.ErrorContext(
+					r.Context(),
+					"Failed to process log entry",
+					slog.String("pipeline", pipeline),
+					slog.String("error", err.Error()),
+				)
+				writeSplunkError(w, newSplunkInternalError())
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"Success","code":0}` + "\n"))
+	}
+}
+
+// writeSplunkError replies with a HEC error.
+func writeSplunkError(w http.ResponseWriter, err *splunkError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.Status)
+	json.NewEncoder(w).Encode(err)
+}
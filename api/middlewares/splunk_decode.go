@@ -0,0 +1,298 @@
+package middlewares
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"compress/gzip"
+	"encoding/json"
+
+	"link-society.com/flowg/internal/utils/timestamp"
+)
+
+// splunkMetadata is the Splunk metadata of an event. The index, or the
+// sourcetype when there is none, names the pipeline the event runs through.
+type splunkMetadata struct {
+	Host       string
+	Source     string
+	Sourcetype string
+	Index      string
+}
+
+// pipeline returns the name of the pipeline selected by the metadata, if any.
+func (m splunkMetadata) pipeline() string {
+	if m.Index != "" {
+		return m.Index
+	}
+
+	return m.Sourcetype
+}
+
+// fields returns the metadata that was set, as log record fields.
+func (m splunkMetadata) fields() map[string]string {
+	fields := map[string]string{}
+
+	for key, value := range map[string]string{
+		"host":       m.Host,
+		"source":     m.Source,
+		"sourcetype": m.Sourcetype,
+		"index":      m.Index,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+
+	return fields
+}
+
+// splunkEvent is an event decoded from a HEC request.
+type splunkEvent struct {
+	Metadata splunkMetadata
+	Fields   map[string]string
+	// Time is the event's own time, zero when it has none.
+	Time time.Time
+}
+
+// splunkError is a HEC error response, in the shape HEC clients expect.
+type splunkError struct {
+	Status int    `json:"-"`
+	Text   string `json:"text"`
+	Code   int    `json:"code"`
+	// InvalidEvent is the index of the event the error relates to, if any.
+	InvalidEvent *int `json:"invalid-event-number,omitempty"`
+}
+
+func (e *splunkError) Error() string {
+	return e.Text
+}
+
+// The errors below are the subset of HEC's status codes the middleware
+// reports, see https://docs.splunk.com/Documentation/Splunk/latest/Data/TroubleshootHTTPEventCollector.
+func newSplunkTokenRequiredError() *splunkError {
+	return &splunkError{Status: http.StatusUnauthorized, Text: "Token is required", Code: 2}
+}
+
+func newSplunkInvalidAuthorizationError() *splunkError {
+	return &splunkError{Status: http.StatusUnauthorized, Text: "Invalid authorization", Code: 3}
+}
+
+func newSplunkInvalidTokenError() *splunkError {
+	return &splunkError{Status: http.StatusForbidden, Text: "Invalid token", Code: 4}
+}
+
+func newSplunkNoDataError() *splunkError {
+	return &splunkError{Status: http.StatusBadRequest, Text: "No data", Code: 5}
+}
+
+func newSplunkInvalidDataFormatError(event int) *splunkError {
+	return &splunkError{Status: http.StatusBadRequest, Text: "Invalid data format", Code: 6, InvalidEvent: &event}
+}
+
+func newSplunkIncorrectIndexError(event int) *splunkError {
+	return &splunkError{Status: http.StatusBadRequest, Text: "Incorrect index", Code: 7, InvalidEvent: &event}
+}
+
+func newSplunkInternalError() *splunkError {
+	return &splunkError{Status: http.StatusInternalServerError, Text: "Internal server error", Code: 8}
+}
+
+func newSplunkEventRequiredError(event int) *splunkError {
+	return &splunkError{Status: http.StatusBadRequest, Text: "Event field is required", Code: 12, InvalidEvent: &event}
+}
+
+func newSplunkEventBlankError(event int) *splunkError {
+	return &splunkError{Status: http.StatusBadRequest, Text: "Event field cannot be blank", Code: 13, InvalidEvent: &event}
+}
+
+// newSplunkContentTooLargeError reports a body exceeding maxSplunkBodySize. HEC
+// has no code of its own for it, so it carries the one of invalid data.
+func newSplunkContentTooLargeError() *splunkError {
+	return &splunkError{Status: http.StatusRequestEntityTooLarge, Text: "Content too large", Code: 6}
+}
+
+// splunkDecodeError turns the error reading the body of a HEC request into the
+// error reported to the client: invalid data, unless the body was too large.
+func splunkDecodeError(err error, event int) *splunkError {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return newSplunkContentTooLargeError()
+	}
+
+	return newSplunkInvalidDataFormatError(event)
+}
+
+// splunkQueryMetadata reads the metadata given as query parameters, which
+// applies to the events that do not set their own.
+func splunkQueryMetadata(query url.Values) splunkMetadata {
+	return splunkMetadata{
+		Host:       query.Get("host"),
+		Source:     query.Get("source"),
+		Sourcetype: query.Get("sourcetype"),
+		Index:      query.Get("index"),
+	}
+}
+
+// maxSplunkBodySize bounds the size of the body of a HEC request, both as sent
+// and once decompressed.
+const maxSplunkBodySize = 64 << 20
+
+// splunkRequestBody returns the body of a HEC request, decompressing it when
+// it is gzip-encoded. Reading more than maxSplunkBodySize from it, as sent or
+// once decompressed, fails with an [http.MaxBytesError].
+func splunkRequestBody(w http.ResponseWriter, r *http.Request) (io.ReadCloser, error) {
+	body := http.MaxBytesReader(w, r.Body, maxSplunkBodySize)
+
+	if r.Header.Get("Content-Encoding") != "gzip" {
+		return body, nil
+	}
+
+	reader, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.MaxBytesReader(w, reader, maxSplunkBodySize), nil
+}
+
+// decodeSplunkEvents decodes the body of a request to the event endpoint: one
+// or more concatenated JSON events, such as:
+//
+//	{"time": 1714564800.5, "index": "my-pipeline", "event": {"message": "hello"}}
+//	{"sourcetype": "my-pipeline", "event": "world", "fields": {"env": "prod"}}
+//
+// Object events are flattened into fields, other events are kept in the
+// "event" field. Indexed fields are merged into the record, the event taking
+// precedence over them, and them over the metadata. Metadata missing from an
+// event defaults to the one in defaults.
+func decodeSplunkEvents(body io.Reader, defaults splunkMetadata) ([]splunkEvent, error) {
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+
+	events := []splunkEvent{}
+
+	for i := 0; ; i++ {
+		var raw struct {
+			Time       any            `json:"time"`
+			Host       string         `json:"host"`
+			Source     string         `json:"source"`
+			Sourcetype string         `json:"sourcetype"`
+			Index      string         `json:"index"`
+			Event      any            `json:"event"`
+			Fields     map[string]any `json:"fields"`
+		}
+
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, splunkDecodeError(err, i)
+		}
+
+		switch event := raw.Event.(type) {
+		case nil:
+			return nil, newSplunkEventRequiredError(i)
+
+		case string:
+			if event == "" {
+				return nil, newSplunkEventBlankError(i)
+			}
+		}
+
+		metadata := splunkMetadata{
+			Host:       firstNonEmpty(raw.Host, defaults.Host),
+			Source:     firstNonEmpty(raw.Source, defaults.Source),
+			Sourcetype: firstNonEmpty(raw.Sourcetype, defaults.Sourcetype),
+			Index:      firstNonEmpty(raw.Index, defaults.Index),
+		}
+
+		fields := metadata.fields()
+
+		for key, value := range flattenElasticDocument(raw.Fields) {
+			fields[key] = value
+		}
+
+		doc, isObject := raw.Event.(map[string]any)
+		if !isObject {
+			doc = map[string]any{"event": raw.Event}
+		}
+
+		for key, value := range flattenElasticDocument(doc) {
+			fields[key] = value
+		}
+
+		event := splunkEvent{
+			Metadata: metadata,
+			Fields:   fields,
+		}
+
+		if raw.Time != nil {
+			ts, err := timestamp.Parse(fmt.Sprint(raw.Time), timestamp.FORMAT_UNIX)
+			if err != nil {
+				return nil, newSplunkInvalidDataFormatError(i)
+			}
+
+			event.Time = ts
+		}
+
+		events = append(events, event)
+	}
+
+	if len(events) == 0 {
+		return nil, newSplunkNoDataError()
+	}
+
+	return events, nil
+}
+
+// decodeSplunkRawEvents decodes the body of a request to the raw endpoint:
+// each non-empty line is an event, kept in the "event" field, and described
+// by the metadata given as query parameters.
+func decodeSplunkRawEvents(body io.Reader, metadata splunkMetadata) ([]splunkEvent, error) {
+	events := []splunkEvent{}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := metadata.fields()
+		fields["event"] = line
+
+		events = append(events, splunkEvent{
+			Metadata: metadata,
+			Fields:   fields,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, splunkDecodeError(err, len(events))
+	}
+
+	if len(events) == 0 {
+		return nil, newSplunkNoDataError()
+	}
+
+	return events, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
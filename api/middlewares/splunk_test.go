@@ -0,0 +1,171 @@
+package middlewares_test
+
+import (
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/mock"
+
+	"encoding/json"
+	"strings"
+
+	"link-society.com/flowg/internal/engines/pipelines"
+	"link-society.com/flowg/internal/models"
+
+	pipelinesMocks "link-society.com/flowg/internal/engines/pipelines/mocks"
+	storageMocks "link-society.com/flowg/internal/storage/mocks"
+
+	"link-society.com/flowg/api/middlewares"
+)
+
+// TestSplunkEndpoint guards against batched events being run through the
+// wrong pipeline or losing their time, against raw lines losing their
+// metadata, and against partially ingesting requests with an unknown index.
+func TestSplunkEndpoint(t *testing.T) {
+	mockAuthStorage := storageMocks.NewMockAuthStorage().(*storageMocks.MockAuthStorage)
+	mockConfigStorage := storageMocks.NewMockConfigStorage().(*storageMocks.MockConfigStorage)
+	mockPipelineRunner := pipelinesMocks.NewMockRunner().(*pipelinesMocks.MockRunner)
+
+	deps := middlewares.SplunkDeps{
+		AuthStorage:    mockAuthStorage,
+		ConfigStorage:  mockConfigStorage,
+		PipelineRunner: mockPipelineRunner,
+	}
+
+	mockAuthStorage.On("VerifyToken", mock.Anything, "pat_secret").
+		Return(&models.User{Name: "test", Roles: []string{"admin"}}, &models.Token{UUID: "uuid"}, nil)
+	mockAuthStorage.On("VerifyToken", mock.Anything, "pat_unknown").
+		Return((*models.User)(nil), (*models.Token)(nil), nil)
+	mockAuthStorage.On("VerifyUserPermission", mock.Anything, "test", models.SCOPE_SEND_LOGS, mock.Anything).
+		Return(true, nil)
+
+	mockConfigStorage.On("ListPipelines", mock.Anything).
+		Return([]string{"main", "firewall"}, nil)
+	mockConfigStorage.On("ReadSystemConfig", mock.Anything).
+		Return(&models.SystemConfiguration{}, nil)
+
+	backfilled := time.Date(2024, 5, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	mockPipelineRunner.On(
+		"Run",
+		mock.Anything,
+		"main",
+		pipelines.DIRECT_ENTRYPOINT,
+		mock.MatchedBy(func(record *models.LogRecord) bool {
+			return record.Timestamp.Equal(backfilled) &&
+				record.Fields["message"] == "hello" &&
+				record.Fields["user.name"] == "alice" &&
+				record.Fields["host"] == "web-1"
+		}),
+	).
+		Return(nil).
+		Once()
+	mockPipelineRunner.On(
+		"Run",
+		mock.Anything,
+		"firewall",
+		pipelines.DIRECT_ENTRYPOINT,
+		mock.MatchedBy(func(record *models.LogRecord) bool {
+			return record.Fields["event"] == "world" && record.Fields["env"] == "prod"
+		}),
+	).
+		Return(nil).
+		Once()
+	mockPipelineRunner.On(
+		"Run",
+		mock.Anything,
+		"firewall",
+		pipelines.DIRECT_ENTRYPOINT,
+		mock.MatchedBy(func(record *models.LogRecord) bool {
+			return strings.HasPrefix(record.Fields["event"], "deny ") &&
+				record.Fields["source"] == "fw-1"
+		}),
+	).
+		Return(nil).
+		Twice()
+
+	server := httptest.NewServer(middlewares.NewSplunkHandler(deps))
+	defer server.Close()
+
+	type hecResponse struct {
+		Text         string `json:"text"`
+		Code         int    `json:"code"`
+		InvalidEvent *int   `json:"invalid-event-number"`
+	}
+
+	send := func(path string, token string, body string) (int, hecResponse) {
+		req, err := http.NewRequestWithContext(
+			t.Context(),
+			http.MethodPost,
+			server.URL+"/api/v1/middlewares/splunk/"+path,
+			strings.NewReader(body),
+		)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		req.Header.Set("Authorization", "Splunk "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var result hecResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		return resp.StatusCode, result
+	}
+
+	status, result := send(
+		"services/collector/event",
+		"pat_secret",
+		`{"time": 1714564800.5, "host": "web-1", "index": "main", "event": {"message": "hello", "user": {"name": "alice"}}}`+
+			`{"sourcetype": "firewall", "event": "world", "fields": {"env": "prod"}}`,
+	)
+	if status != http.StatusOK || result.Code != 0 {
+		t.Fatalf("unexpected response to the event batch: %d %+v", status, result)
+	}
+
+	status, result = send(
+		"services/collector/raw?sourcetype=firewall&source=fw-1",
+		"pat_secret",
+		"deny tcp 10.0.0.1\n\ndeny udp 10.0.0.2\n",
+	)
+	if status != http.StatusOK || result.Code != 0 {
+		t.Fatalf("unexpected response to the raw events: %d %+v", status, result)
+	}
+
+	status, result = send(
+		"services/collector/event",
+		"pat_secret",
+		`{"index": "main", "event": "ok"}{"index": "missing", "event": "rejected"}`,
+	)
+	if status != http.StatusBadRequest || result.Code != 7 || result.InvalidEvent == nil || *result.InvalidEvent != 1 {
+		t.Fatalf("expected the unknown index to be rejected: %d %+v", status, result)
+	}
+
+	status, result = send(
+		"services/collector/raw?sourcetype=firewall",
+		"pat_secret",
+		strings.Repeat(strings.Repeat("a", 1<<20)+"\n", 64),
+	)
+	if status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected the oversized body to be rejected: %d %+v", status, result)
+	}
+
+	status, result = send("services/collector/event", "pat_unknown", `{"index": "main", "event": "denied"}`)
+	if status != http.StatusForbidden || result.Code != 4 {
+		t.Fatalf("expected the unknown token to be rejected: %d %+v", status, result)
+	}
+
+	mockAuthStorage.AssertExpectations(t)
+	mockConfigStorage.AssertExpectations(t)
+	mockPipelineRunner.AssertExpectations(t)
+}
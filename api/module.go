@@ -6,6 +6,7 @@ import (
 	_ "link-society.com/flowg/api/middlewares"
 	_ "link-society.com/flowg/api/operations"
 	"link-society.com/flowg/api/routing"
+	_ "link-society.com/flowg/api/sso"
 )
 
 // Module bundles everything needed to serve FlowG's REST API into a single
@@ -0,0 +1,152 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+	"link-society.com/flowg/internal/utils/langs/filtering"
+)
+
+// AggregateLogsDeps lists the dependencies of [NewAggregateLogsUsecase].
+type AggregateLogsDeps struct {
+	fx.In
+
+	AuthStorage storage.AuthStorage
+	LogStorage  storage.LogStorage
+}
+
+// NewAggregateLogsUsecase computes statistics over a stream's logs within a
+// time range: counts, distinct counts and numeric min/max/avg, optionally
+// grouped by field values and split into a time histogram.
+//
+// The records never leave the server: only one bucket per group and time
+// interval is returned. Callers must have the read-streams permission, and the
+// read-ACLs permission to aggregate the audit stream. A filter that fails to compile, an unknown aggregation or an invalid interval
+// is reported as an invalid-argument error.
+func NewAggregateLogsUsecase(deps AggregateLogsDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_READ_STREAMS,
+			func(
+				ctx context.Context,
+				req schemas.AggregateLogsRequest,
+				resp *schemas.AggregateLogsResponse,
+			) error {
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Stream); err != nil {
+					return err
+				}
+
+				var filter filtering.Filter
+
+				if req.Filter != nil && *req.Filter != "" {
+					var err error
+					filter, err = filtering.Compile(*req.Filter)
+					if err != nil {
+						logger.ErrorContext(
+							ctx,
+							"Failed to compile filter",
+							slog.String("stream", req.Stream),
+							slog.String("error", err.Error()),
+						)
+
+						resp.Success = false
+						return status.Wrap(err, status.InvalidArgument)
+					}
+				}
+
+				query := models.AggregationQuery{
+					GroupBy: req.GroupBy,
+				}
+
+				for _, raw := range req.Aggregations {
+					aggregation, err := models.ParseAggregation(raw)
+					if err != nil {
+						resp.Success = false
+						return status.Wrap(err, status.InvalidArgument)
+					}
+
+					query.Aggregations = append(query.Aggregations, aggregation)
+				}
+
+				if len(query.Aggregations) == 0 {
+					query.Aggregations = []models.Aggregation{
+						{Function: models.AGGREGATION_COUNT},
+					}
+				}
+
+				if req.Interval != nil && *req.Interval != "" {
+					interval, err := time.ParseDuration(*req.Interval)
+					if err == nil && interval <= 0 {
+						err = fmt.Errorf("interval must be positive, got %s", interval)
+					}
+					if err != nil {
+						resp.Success = false
+						return status.Wrap(err, status.InvalidArgument)
+					}
+
+					query.Interval = interval
+				}
+
+				buckets, err := deps.LogStorage.Aggregate(
+					ctx,
+					req.Stream,
+					req.From, req.To,
+					filter,
+					req.Indexing,
+					query,
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to aggregate logs",
+						slog.String("stream", req.Stream),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				resp.Success = true
+				resp.Buckets = buckets
+				return nil
+			},
+		),
+	)
+
+	u.SetName("aggregate_logs")
+	u.SetTitle("Aggregate Logs")
+	u.SetDescription("Compute counts and statistics over the logs of a stream, optionally grouped and bucketed by time")
+	u.SetTags("streams")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.InvalidArgument, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewAggregateLogsUsecase,
+		http.MethodGet,
+		"/api/v1/streams/{stream}/aggregate",
+	)
+}
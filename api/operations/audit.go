@@ -0,0 +1,94 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"time"
+
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// recordAuditEvent records a change made by the caller in the audit log: the
+// operation that made it, its target, and the state of the target before and
+// after the change (nil when it did not exist, or no longer exists).
+//
+// The change being made already, failing to record it is logged rather than
+// reported to the caller.
+func recordAuditEvent(
+	ctx context.Context,
+	recorder audit.Recorder,
+	operation string,
+	target string,
+	before any,
+	after any,
+) {
+	event := models.AuditEvent{
+		Timestamp: time.Now(),
+		Actor:     auth.GetContextUser(ctx).Name,
+		Operation: operation,
+		Target:    target,
+		SourceIP:  auth.GetContextSourceIP(ctx),
+		Before:    before,
+		After:     after,
+	}
+
+	if err := recorder.Record(ctx, event); err != nil {
+		logging.Logger().ErrorContext(
+			ctx,
+			"Failed to record audit event",
+			slog.String("operation", operation),
+			slog.String("target", target),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// canReadAuditStream reports whether the caller bound to ctx may read the
+// reserved [models.AUDIT_STREAM]: the read-streams permission is not enough,
+// the audit log is only served to callers holding the read-ACLs permission.
+func canReadAuditStream(ctx context.Context, authStorage storage.AuthStorage) (bool, error) {
+	return auth.VerifyPermission(ctx, authStorage, models.SCOPE_READ_ACLS, nil)
+}
+
+// requireStreamAccess guards the routes reading a stream, on top of their
+// read-streams permission, so that [models.AUDIT_STREAM] is only read by
+// callers allowed to by [canReadAuditStream]. Other streams are let through.
+func requireStreamAccess(
+	ctx context.Context,
+	authStorage storage.AuthStorage,
+	stream string,
+) error {
+	if stream != models.AUDIT_STREAM {
+		return nil
+	}
+
+	authorized, err := canReadAuditStream(ctx, authStorage)
+	if err != nil {
+		logging.Logger().ErrorContext(
+			ctx,
+			"Failed to verify user permission",
+			slog.String("stream", stream),
+			slog.String("error", err.Error()),
+		)
+
+		return status.Wrap(err, status.Internal)
+	}
+
+	if !authorized {
+		return status.Wrap(
+			fmt.Errorf("not allowed to read stream %q", stream),
+			status.PermissionDenied,
+		)
+	}
+
+	return nil
+}
@@ -36,7 +36,8 @@ type BackupLogsDeps struct {
 //
 // It is the export half of the log backup story; the snapshot can later be
 // reloaded with [NewRestoreLogsUsecase]. Callers must have the
-// read-streams permission.
+// read-streams permission, and the read-ACLs permission since the snapshot
+// holds the audit stream.
 func NewBackupLogsUsecase(deps BackupLogsDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -49,6 +50,11 @@ func NewBackupLogsUsecase(deps BackupLogsDeps) usecase.Interactor {
 				req schemas.BackupLogsRequest,
 				resp *schemas.BackupLogsResponse,
 			) error {
+				// The snapshot holds every stream, the audit stream included.
+				if err := requireStreamAccess(ctx, deps.AuthStorage, models.AUDIT_STREAM); err != nil {
+					return err
+				}
+
 				resp.Writer.(http.ResponseWriter).Header().Set("Content-Type", "application/octet-stream")
 				resp.Writer.(http.ResponseWriter).Header().Set("Content-Disposition", "attachment; filename=logs.db")
 				resp.Writer.(http.ResponseWriter).Header().Set("Cache-Control", "no-cache")
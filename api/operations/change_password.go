@@ -18,6 +18,7 @@ import (
 	"link-society.com/flowg/api/schemas"
 
 	applog "link-society.com/flowg/internal/app/logging"
+	"link-society.com/flowg/internal/engines/audit"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
 
@@ -25,7 +26,8 @@ import (
 type ChangePasswordDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewChangePasswordUsecase lets the authenticated user change their own password.
@@ -34,7 +36,8 @@ type ChangePasswordDeps struct {
 // session alone cannot lock the owner out. It requires authentication but no
 // particular permission; a wrong current password is reported as
 // permission-denied. The request is marked sensitive so the passwords are kept
-// out of FlowG's own logs.
+// out of FlowG's own logs, and neither of them is recorded in the audit log,
+// only the fact that the password changed.
 func NewChangePasswordUsecase(deps ChangePasswordDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -77,6 +80,15 @@ func NewChangePasswordUsecase(deps ChangePasswordDeps) usecase.Interactor {
 				return status.Wrap(err, status.Internal)
 			}
 
+			recordAuditEvent(
+				ctx,
+				deps.AuditRecorder,
+				"change_password",
+				"user:"+user.Name,
+				nil,
+				nil,
+			)
+
 			resp.Success = true
 
 			return nil
@@ -2,6 +2,7 @@ package operations
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,14 +26,17 @@ import (
 type ConfigureStreamDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
-	LogStorage  storage.LogStorage
+	AuthStorage   storage.AuthStorage
+	LogStorage    storage.LogStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewConfigureStreamUsecase sets the retention and indexing configuration of a
 // stream.
 //
-// Callers must have the write-streams permission.
+// Callers must have the write-streams permission. The change is recorded in
+// the audit log. The retention of the reserved [models.AUDIT_STREAM] cannot be
+// changed, only its indexes.
 func NewConfigureStreamUsecase(deps ConfigureStreamDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -44,7 +49,39 @@ func NewConfigureStreamUsecase(deps ConfigureStreamDeps) usecase.Interactor {
 				req schemas.ConfigureStreamRequest,
 				resp *schemas.ConfigureStreamResponse,
 			) error {
-				err := deps.LogStorage.ConfigureStream(ctx, req.Stream, req.Config)
+				streamConfigs, err := deps.LogStorage.ListStreamConfigs(ctx)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to list stream configurations",
+						slog.String("error", err.Error()),
+					)
+
+					return status.Wrap(err, status.Internal)
+				}
+
+				var before *models.StreamConfig
+				if streamConfig, exists := streamConfigs[req.Stream]; exists {
+					before = &streamConfig
+				}
+
+				if req.Stream == models.AUDIT_STREAM {
+					current := models.StreamConfig{}
+					if before != nil {
+						current = *before
+					}
+
+					if req.Config.RetentionTime != current.RetentionTime ||
+						req.Config.RetentionSize != current.RetentionSize {
+						resp.Success = false
+						return status.Wrap(
+							fmt.Errorf("retention of stream %q is reserved", req.Stream),
+							status.FailedPrecondition,
+						)
+					}
+				}
+
+				err = deps.LogStorage.ConfigureStream(ctx, req.Stream, req.Config)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -56,6 +93,15 @@ func NewConfigureStreamUsecase(deps ConfigureStreamDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"configure_stream",
+					"stream:"+req.Stream,
+					before,
+					&req.Config,
+				)
+
 				resp.Success = true
 
 				return nil
@@ -68,7 +114,11 @@ func NewConfigureStreamUsecase(deps ConfigureStreamDeps) usecase.Interactor {
 	u.SetDescription("Configure Stream retention and indexes")
 	u.SetTags("streams")
 
-	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
+	u.SetExpectedErrors(
+		status.PermissionDenied,
+		status.FailedPrecondition,
+		status.Internal,
+	)
 
 	return u
 }
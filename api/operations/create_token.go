@@ -2,7 +2,9 @@ package operations
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"time"
 
 	"net/http"
 
@@ -16,6 +18,8 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
 
@@ -23,44 +27,75 @@ import (
 type CreateTokenDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewCreateTokenUsecase issues a new personal access token for the calling user.
 //
-// The secret value is returned only in this response and cannot be retrieved
-// later; only its UUID is persisted for management. It requires authentication
-// but no particular permission.
+// The token is named, and optionally restricted to a subset of the scopes of
+// the user and given an expiry. The secret value is returned only in this
+// response and cannot be retrieved later; only its description is persisted
+// for management, and recorded in the audit log. It requires authentication but no particular permission,
+// though a token restricted to some scopes cannot be used to issue another.
 func NewCreateTokenUsecase(deps CreateTokenDeps) usecase.Interactor {
 	logger := logging.Logger()
 
 	u := usecase.NewInteractor(
-		func(
-			ctx context.Context,
-			req schemas.CreateTokenRequest,
-			resp *schemas.CreateTokenResponse,
-		) error {
-			user := auth.GetContextUser(ctx)
-
-			token, tokenUuid, err := deps.AuthStorage.CreateToken(ctx, user.Name)
-			if err != nil {
-				logger.ErrorContext(
+		auth.RequireUnrestrictedApiDecorator(
+			func(
+				ctx context.Context,
+				req schemas.CreateTokenRequest,
+				resp *schemas.CreateTokenResponse,
+			) error {
+				user := auth.GetContextUser(ctx)
+
+				if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+					resp.Success = false
+					return status.Wrap(
+						errors.New("token expiry must be in the future"),
+						status.InvalidArgument,
+					)
+				}
+
+				token, tokenInfo, err := deps.AuthStorage.CreateToken(
 					ctx,
-					"Failed to create token",
-					slog.String("user", user.Name),
-					slog.String("error", err.Error()),
+					user.Name,
+					models.Token{
+						Name:      req.Name,
+						Scopes:    req.Scopes,
+						ExpiresAt: req.ExpiresAt,
+					},
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to create token",
+						slog.String("user", user.Name),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"create_token",
+					"token:"+tokenInfo.UUID,
+					nil,
+					tokenInfo,
 				)
 
-				resp.Success = false
-				return status.Wrap(err, status.Internal)
-			}
-
-			resp.Success = true
-			resp.Token = token
-			resp.TokenUUID = tokenUuid
+				resp.Success = true
+				resp.Token = token
+				resp.TokenUUID = tokenInfo.UUID
+				resp.TokenInfo = tokenInfo
 
-			return nil
-		},
+				return nil
+			},
+		),
 	)
 
 	u.SetName("create_token")
@@ -68,7 +103,12 @@ func NewCreateTokenUsecase(deps CreateTokenDeps) usecase.Interactor {
 	u.SetDescription("Create a new Personal Access Token for the current user")
 	u.SetTags("acls")
 
-	u.SetExpectedErrors(status.NotFound, status.Internal)
+	u.SetExpectedErrors(
+		status.InvalidArgument,
+		status.PermissionDenied,
+		status.NotFound,
+		status.Internal,
+	)
 
 	return u
 }
@@ -0,0 +1,120 @@
+package operations
+
+import (
+	"context"
+	"log/slog"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/engines/alerts"
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// DeleteAlertDeps lists the dependencies of [NewDeleteAlertUsecase].
+type DeleteAlertDeps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+	AlertManager  alerts.Manager
+	AuditRecorder audit.Recorder
+}
+
+// NewDeleteAlertUsecase removes an alert rule along with its firing history.
+//
+// Callers must have the write-alerts permission. Deleting an absent alert rule
+// is treated as a success. The alert manager is reloaded so that the rule stops
+// being evaluated. The change is recorded in the audit log.
+func NewDeleteAlertUsecase(deps DeleteAlertDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_WRITE_ALERTS,
+			func(
+				ctx context.Context,
+				req schemas.DeleteAlertRequest,
+				resp *schemas.DeleteAlertResponse,
+			) error {
+				before, err := deps.ConfigStorage.ReadAlert(ctx, req.Alert)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read alert",
+						slog.String("alert", req.Alert),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.ConfigStorage.DeleteAlert(ctx, req.Alert)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to delete alert",
+						slog.String("alert", req.Alert),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"delete_alert",
+					"alert:"+req.Alert,
+					before,
+					nil,
+				)
+
+				if err := deps.AlertManager.Reload(ctx); err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to reload alerts after delete",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				resp.Success = true
+				return nil
+			},
+		),
+	)
+
+	u.SetName("delete_alert")
+	u.SetTitle("Delete Alert")
+	u.SetDescription("Delete alert")
+	u.SetTags("alerts")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewDeleteAlertUsecase,
+		http.MethodDelete,
+		"/api/v1/alerts/{alert}",
+	)
+}
@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,13 +25,15 @@ import (
 type DeleteAuthProviderDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewDeleteAuthProviderUsecase removes an auth provider.
 //
-// Callers must have the write-auth-providers permission. Deleting an absent auth provider is
-// treated as a success.
+// Callers must have the write-auth-providers permission. Deleting an absent
+// auth provider is treated as a success. The change is recorded in the audit
+// log.
 func NewDeleteAuthProviderUsecase(deps DeleteAuthProviderDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -43,7 +46,20 @@ func NewDeleteAuthProviderUsecase(deps DeleteAuthProviderDeps) usecase.Interacto
 				req schemas.DeleteAuthProviderRequest,
 				resp *schemas.DeleteAuthProviderResponse,
 			) error {
-				err := deps.AuthStorage.DeleteAuthProvider(ctx, req.AuthProvider)
+				before, err := deps.AuthStorage.FetchAuthProvider(ctx, req.AuthProvider)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read auth provider",
+						slog.String("auth_provider", req.AuthProvider),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.AuthStorage.DeleteAuthProvider(ctx, req.AuthProvider)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -56,6 +72,15 @@ func NewDeleteAuthProviderUsecase(deps DeleteAuthProviderDeps) usecase.Interacto
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"delete_auth_provider",
+					"auth_provider:"+req.AuthProvider,
+					before,
+					nil,
+				)
+
 				resp.Success = true
 				return nil
 			},
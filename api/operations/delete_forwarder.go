@@ -16,6 +16,9 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/alerts"
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/engines/pipelines"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,14 +27,20 @@ import (
 type DeleteForwarderDeps struct {
 	fx.In
 
-	AuthStorage   storage.AuthStorage
-	ConfigStorage storage.ConfigStorage
+	AuthStorage    storage.AuthStorage
+	ConfigStorage  storage.ConfigStorage
+	PipelineRunner pipelines.Runner
+	AlertManager   alerts.Manager
+	AuditRecorder  audit.Recorder
 }
 
 // NewDeleteForwarderUsecase removes a forwarder.
 //
 // Callers must have the write-forwarders permission. Deleting an absent
-// forwarder is treated as a success.
+// forwarder is treated as a success. Deleting a forwarder invalidates cached
+// pipeline builds and reloads the alert rules, so that no runtime of the
+// deleted forwarder is kept alive; rules still naming it are left unloaded. The
+// change is recorded in the audit log.
 func NewDeleteForwarderUsecase(deps DeleteForwarderDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -44,7 +53,20 @@ func NewDeleteForwarderUsecase(deps DeleteForwarderDeps) usecase.Interactor {
 				req schemas.DeleteForwarderRequest,
 				resp *schemas.DeleteForwarderResponse,
 			) error {
-				err := deps.ConfigStorage.DeleteForwarder(ctx, req.Forwarder)
+				before, err := deps.ConfigStorage.ReadForwarder(ctx, req.Forwarder)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read forwarder",
+						slog.String("forwarder", req.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.ConfigStorage.DeleteForwarder(ctx, req.Forwarder)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -57,6 +79,37 @@ func NewDeleteForwarderUsecase(deps DeleteForwarderDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"delete_forwarder",
+					"forwarder:"+req.Forwarder,
+					before,
+					nil,
+				)
+
+				if err := deps.PipelineRunner.InvalidateAllCachedBuilds(ctx); err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to refresh pipeline cache after delete",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				if err := deps.AlertManager.Reload(ctx); err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to reload alerts after delete",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
 				resp.Success = true
 				return nil
 			},
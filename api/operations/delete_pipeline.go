@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -26,12 +27,13 @@ type DeletePipelineDeps struct {
 
 	AuthStorage   storage.AuthStorage
 	ConfigStorage storage.ConfigStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewDeletePipelineUsecase removes a pipeline.
 //
-// Callers must have the write-pipelines permission. Deleting an absent pipeline is
-// treated as a success.
+// Callers must have the write-pipelines permission. Deleting an absent pipeline
+// is treated as a success. The change is recorded in the audit log.
 func NewDeletePipelineUsecase(deps DeletePipelineDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -44,7 +46,20 @@ func NewDeletePipelineUsecase(deps DeletePipelineDeps) usecase.Interactor {
 				req schemas.DeletePipelineRequest,
 				resp *schemas.DeletePipelineResponse,
 			) error {
-				err := deps.ConfigStorage.DeletePipeline(ctx, req.Pipeline)
+				before, err := deps.ConfigStorage.ReadPipeline(ctx, req.Pipeline)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read pipeline",
+						slog.String("pipeline", req.Pipeline),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.ConfigStorage.DeletePipeline(ctx, req.Pipeline)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -57,6 +72,15 @@ func NewDeletePipelineUsecase(deps DeletePipelineDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"delete_pipeline",
+					"pipeline:"+req.Pipeline,
+					before,
+					nil,
+				)
+
 				resp.Success = true
 				return nil
 			},
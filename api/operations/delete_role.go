@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,13 +25,14 @@ import (
 type DeleteRoleDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewDeleteRoleUsecase removes a role.
 //
 // Callers must have the write-ACLs permission. Deleting an absent role is
-// treated as a success.
+// treated as a success. The change is recorded in the audit log.
 func NewDeleteRoleUsecase(deps DeleteRoleDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -43,7 +45,20 @@ func NewDeleteRoleUsecase(deps DeleteRoleDeps) usecase.Interactor {
 				req schemas.DeleteRoleRequest,
 				resp *schemas.DeleteRoleResponse,
 			) error {
-				err := deps.AuthStorage.DeleteRole(ctx, req.Role)
+				before, err := deps.AuthStorage.FetchRole(ctx, req.Role)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read role",
+						slog.String("role", req.Role),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.AuthStorage.DeleteRole(ctx, req.Role)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -56,6 +71,15 @@ func NewDeleteRoleUsecase(deps DeleteRoleDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"delete_role",
+					"role:"+req.Role,
+					before,
+					nil,
+				)
+
 				resp.Success = true
 				return nil
 			},
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	"net/http"
+	"slices"
 
 	"go.uber.org/fx"
 
@@ -16,6 +17,8 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
 
@@ -23,42 +26,76 @@ import (
 type DeleteTokenDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewDeleteTokenUsecase revokes one of the calling user's personal access tokens.
 //
 // A user may only delete their own tokens. It requires authentication but no
-// particular permission.
+// particular permission, though a token restricted to some scopes cannot be
+// used to revoke others. The change is recorded in the audit log.
 func NewDeleteTokenUsecase(deps DeleteTokenDeps) usecase.Interactor {
 	logger := logging.Logger()
 
 	u := usecase.NewInteractor(
-		func(
-			ctx context.Context,
-			req schemas.DeleteTokenRequest,
-			resp *schemas.DeleteTokenResponse,
-		) error {
-			user := auth.GetContextUser(ctx)
-
-			err := deps.AuthStorage.DeleteToken(ctx, user.Name, req.TokenUUID)
-			if err != nil {
-				logger.ErrorContext(
+		auth.RequireUnrestrictedApiDecorator(
+			func(
+				ctx context.Context,
+				req schemas.DeleteTokenRequest,
+				resp *schemas.DeleteTokenResponse,
+			) error {
+				user := auth.GetContextUser(ctx)
+
+				tokens, err := deps.AuthStorage.ListTokens(ctx, user.Name)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to list tokens",
+						slog.String("user", user.Name),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				var before *models.Token
+				index := slices.IndexFunc(tokens, func(token models.Token) bool {
+					return token.UUID == req.TokenUUID
+				})
+				if index >= 0 {
+					before = &tokens[index]
+				}
+
+				err = deps.AuthStorage.DeleteToken(ctx, user.Name, req.TokenUUID)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to delete token",
+						slog.String("user", user.Name),
+						slog.String("token-uuid", req.TokenUUID),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				recordAuditEvent(
 					ctx,
-					"Failed to delete token",
-					slog.String("user", user.Name),
-					slog.String("token-uuid", req.TokenUUID),
-					slog.String("error", err.Error()),
+					deps.AuditRecorder,
+					"delete_token",
+					"token:"+req.TokenUUID,
+					before,
+					nil,
 				)
 
-				resp.Success = false
-				return status.Wrap(err, status.Internal)
-			}
+				resp.Success = true
 
-			resp.Success = true
-
-			return nil
-		},
+				return nil
+			},
+		),
 	)
 
 	u.SetName("delete_token")
@@ -66,7 +103,7 @@ func NewDeleteTokenUsecase(deps DeleteTokenDeps) usecase.Interactor {
 	u.SetDescription("Delete Personal Access Token UUIDs for the current user")
 	u.SetTags("acls")
 
-	u.SetExpectedErrors(status.Internal)
+	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
 
 	return u
 }
@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -26,12 +27,13 @@ type DeleteTransformerDeps struct {
 
 	AuthStorage   storage.AuthStorage
 	ConfigStorage storage.ConfigStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewDeleteTransformerUsecase removes a transformer.
 //
 // Callers must have the write-transformers permission. Deleting an absent
-// transformer is treated as a success.
+// transformer is treated as a success. The change is recorded in the audit log.
 func NewDeleteTransformerUsecase(deps DeleteTransformerDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -44,7 +46,20 @@ func NewDeleteTransformerUsecase(deps DeleteTransformerDeps) usecase.Interactor
 				req schemas.DeleteTransformerRequest,
 				resp *schemas.DeleteTransformerResponse,
 			) error {
-				err := deps.ConfigStorage.DeleteTransformer(ctx, req.Transformer)
+				before, err := deps.ConfigStorage.ReadTransformer(ctx, req.Transformer)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read transformer",
+						slog.String("transformer", req.Transformer),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.ConfigStorage.DeleteTransformer(ctx, req.Transformer)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -57,6 +72,15 @@ func NewDeleteTransformerUsecase(deps DeleteTransformerDeps) usecase.Interactor
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"delete_transformer",
+					"transformer:"+req.Transformer,
+					before,
+					nil,
+				)
+
 				resp.Success = true
 
 				return nil
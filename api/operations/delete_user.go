@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,13 +25,14 @@ import (
 type DeleteUserDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewDeleteUserUsecase removes a user account.
 //
 // Callers must have the write-ACLs permission. Deleting an absent user is
-// treated as a success.
+// treated as a success. The change is recorded in the audit log.
 func NewDeleteUserUsecase(deps DeleteUserDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -43,7 +45,20 @@ func NewDeleteUserUsecase(deps DeleteUserDeps) usecase.Interactor {
 				req schemas.DeleteUserRequest,
 				resp *schemas.DeleteUserResponse,
 			) error {
-				err := deps.AuthStorage.DeleteUser(ctx, req.User)
+				before, err := deps.AuthStorage.FetchUser(ctx, req.User)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read user",
+						slog.String("user", req.User),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.AuthStorage.DeleteUser(ctx, req.User)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -56,6 +71,15 @@ func NewDeleteUserUsecase(deps DeleteUserDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"delete_user",
+					"user:"+req.User,
+					before,
+					nil,
+				)
+
 				resp.Success = true
 				return nil
 			},
@@ -0,0 +1,316 @@
+package operations
+
+import (
+	"context"
+	"log/slog"
+
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"slices"
+	"time"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+	"link-society.com/flowg/internal/utils/langs/filtering"
+)
+
+// exportFlushInterval is the number of records written between two flushes of
+// the response, so the client receives the export progressively.
+const exportFlushInterval = 1000
+
+// ExportLogsDeps lists the dependencies of [NewExportLogsUsecase].
+type ExportLogsDeps struct {
+	fx.In
+
+	AuthStorage storage.AuthStorage
+	LogStorage  storage.LogStorage
+}
+
+// NewExportLogsUsecase streams a stream's logs within a time range as a
+// downloadable newline-delimited JSON or CSV file.
+//
+// Records are written oldest first, as they are read from the storage, so an
+// export of any size never has to fit in memory. The exported fields can be
+// restricted and ordered; a CSV export without an explicit field list uses
+// every field known to the stream, sorted by name.
+//
+// Callers must have the read-streams permission, and the read-ACLs permission
+// to export the audit stream. A filter that fails to compile is reported as an invalid-argument error. An error occurring once the file
+// has started downloading can no longer be reported: it is logged and the
+// response is aborted, so that the client does not take a truncated export for
+// a complete one.
+func NewExportLogsUsecase(deps ExportLogsDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_READ_STREAMS,
+			func(
+				ctx context.Context,
+				req schemas.ExportLogsRequest,
+				resp *schemas.ExportLogsResponse,
+			) error {
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Stream); err != nil {
+					return err
+				}
+
+				var filter filtering.Filter
+
+				if req.Filter != nil && *req.Filter != "" {
+					var err error
+					filter, err = filtering.Compile(*req.Filter)
+					if err != nil {
+						logger.ErrorContext(
+							ctx,
+							"Failed to compile filter",
+							slog.String("stream", req.Stream),
+							slog.String("error", err.Error()),
+						)
+
+						return status.Wrap(err, status.InvalidArgument)
+					}
+				}
+
+				var (
+					exporter    logExportWriter
+					contentType string
+				)
+
+				body := &exportBodyWriter{Writer: resp.Writer}
+
+				switch req.Format {
+				case "csv":
+					fields := req.Fields
+					if len(fields) == 0 {
+						var err error
+						fields, err = deps.LogStorage.ListStreamFields(ctx, req.Stream)
+						if err != nil {
+							logger.ErrorContext(
+								ctx,
+								"Failed to list stream fields",
+								slog.String("stream", req.Stream),
+								slog.String("error", err.Error()),
+							)
+
+							return status.Wrap(err, status.Internal)
+						}
+						slices.Sort(fields)
+					}
+
+					exporter = newCsvExportWriter(body, fields)
+					contentType = "text/csv"
+
+				default:
+					exporter = newNdjsonExportWriter(body, req.Fields)
+					contentType = "application/x-ndjson"
+				}
+
+				w := resp.Writer.(http.ResponseWriter)
+				w.Header().Set("Content-Type", contentType)
+				w.Header().Set(
+					"Content-Disposition",
+					mime.FormatMediaType(
+						"attachment",
+						map[string]string{"filename": req.Stream + "." + req.Format},
+					),
+				)
+				w.Header().Set("Cache-Control", "no-cache")
+
+				written := 0
+				err := deps.LogStorage.WalkLogs(
+					ctx,
+					req.Stream,
+					req.From, req.To,
+					filter,
+					req.Indexing,
+					func(record *models.LogRecord) error {
+						if err := exporter.Write(record); err != nil {
+							return err
+						}
+
+						written++
+						if written%exportFlushInterval == 0 {
+							if err := exporter.Flush(); err != nil {
+								return err
+							}
+							resp.Writer.(http.Flusher).Flush()
+						}
+
+						return nil
+					},
+				)
+				if err == nil {
+					err = exporter.Flush()
+				}
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to export logs",
+						slog.String("stream", req.Stream),
+						slog.String("error", err.Error()),
+					)
+
+					if body.started {
+						panic(http.ErrAbortHandler)
+					}
+
+					return status.Wrap(err, status.Internal)
+				}
+
+				resp.Writer.(http.Flusher).Flush()
+
+				return nil
+			},
+		),
+	)
+
+	u.SetName("export_logs")
+	u.SetTitle("Export Logs")
+	u.SetDescription("Download the logs of a stream within a time range as NDJSON or CSV.")
+	u.SetTags("streams")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.InvalidArgument, status.Internal)
+
+	return u
+}
+
+// exportBodyWriter tracks whether the export has started writing the response
+// body, after which an error can no longer be sent in place of the file.
+type exportBodyWriter struct {
+	io.Writer
+	started bool
+}
+
+func (w *exportBodyWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.Writer.Write(p)
+}
+
+// logExportWriter encodes exported records in one of the supported formats.
+type logExportWriter interface {
+	// Write encodes one record.
+	Write(record *models.LogRecord) error
+	// Flush pushes any buffered output to the underlying writer.
+	Flush() error
+}
+
+// ndjsonExportWriter writes one JSON-encoded [models.LogRecord] per line,
+// keeping only the selected fields when a selection is given.
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+	fields  []string
+}
+
+func newNdjsonExportWriter(w io.Writer, fields []string) *ndjsonExportWriter {
+	return &ndjsonExportWriter{
+		encoder: json.NewEncoder(w),
+		fields:  fields,
+	}
+}
+
+func (e *ndjsonExportWriter) Write(record *models.LogRecord) error {
+	if len(e.fields) == 0 {
+		return e.encoder.Encode(record)
+	}
+
+	projected := models.LogRecord{
+		Timestamp: record.Timestamp,
+		Fields:    make(map[string]string, len(e.fields)),
+	}
+	for _, field := range e.fields {
+		if value, exists := record.Fields[field]; exists {
+			projected.Fields[field] = value
+		}
+	}
+
+	return e.encoder.Encode(&projected)
+}
+
+func (e *ndjsonExportWriter) Flush() error {
+	return nil
+}
+
+// csvExportWriter writes a header row followed by one row per record: the
+// RFC 3339 timestamp then the value of each selected field, empty when the
+// record does not carry it.
+type csvExportWriter struct {
+	writer *csv.Writer
+	fields []string
+	row    []string
+	err    error
+}
+
+func newCsvExportWriter(w io.Writer, fields []string) *csvExportWriter {
+	e := &csvExportWriter{
+		writer: csv.NewWriter(w),
+		fields: fields,
+		row:    make([]string, len(fields)+1),
+	}
+
+	e.err = e.writer.Write(append([]string{"timestamp"}, fields...))
+	return e
+}
+
+func (e *csvExportWriter) Write(record *models.LogRecord) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e.row[0] = record.Timestamp.Format(time.RFC3339Nano)
+	for i, field := range e.fields {
+		e.row[i+1] = record.Fields[field]
+	}
+
+	return e.writer.Write(e.row)
+}
+
+func (e *csvExportWriter) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// annotateExportLogs documents the export response as a file download in either
+// supported format.
+func annotateExportLogs(oc openapi.OperationContext) error {
+	contentUnits := oc.Response()
+	for i, cu := range contentUnits {
+		if cu.HTTPStatus == 200 {
+			cu.ContentType = "application/x-ndjson"
+			cu.Description = "Newline-delimited JSON or CSV file, depending on the requested format"
+			cu.Format = "File"
+		}
+
+		contentUnits[i] = cu
+	}
+
+	return nil
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewExportLogsUsecase,
+		http.MethodGet,
+		"/api/v1/streams/{stream}/export",
+		routing.Annotated(annotateExportLogs),
+	)
+}
@@ -0,0 +1,88 @@
+package operations
+
+import (
+	"context"
+	"log/slog"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// GetAlertDeps lists the dependencies of [NewGetAlertUsecase].
+type GetAlertDeps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+}
+
+// NewGetAlertUsecase returns the definition of a single alert rule.
+//
+// Callers must have the read-alerts permission. Requesting an unknown
+// alert rule yields a not-found error.
+func NewGetAlertUsecase(deps GetAlertDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_READ_ALERTS,
+			func(
+				ctx context.Context,
+				req schemas.GetAlertRequest,
+				resp *schemas.GetAlertResponse,
+			) error {
+				alert, err := deps.ConfigStorage.ReadAlert(ctx, req.Alert)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to get alert",
+						slog.String("alert", req.Alert),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+				if alert == nil {
+					resp.Success = false
+					return status.NotFound
+				}
+
+				resp.Success = true
+				resp.Alert = alert
+
+				return nil
+			},
+		),
+	)
+
+	u.SetName("get_alert")
+	u.SetTitle("Get Alert")
+	u.SetDescription("Get alert")
+	u.SetTags("alerts")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.NotFound, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewGetAlertUsecase,
+		http.MethodGet,
+		"/api/v1/alerts/{alert}",
+	)
+}
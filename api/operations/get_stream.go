@@ -31,7 +31,8 @@ type GetStreamDeps struct {
 // NewGetStreamUsecase returns the configuration of a stream, creating it with
 // defaults if it does not yet exist.
 //
-// Callers must have the read-streams permission.
+// Callers must have the read-streams permission, and the read-ACLs permission
+// for the audit stream.
 func NewGetStreamUsecase(deps GetStreamDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -44,6 +45,10 @@ func NewGetStreamUsecase(deps GetStreamDeps) usecase.Interactor {
 				req schemas.GetStreamRequest,
 				resp *schemas.GetStreamResponse,
 			) error {
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Stream); err != nil {
+					return err
+				}
+
 				config, err := deps.LogStorage.GetOrCreateStreamConfig(ctx, req.Stream)
 				if err != nil {
 					logger.ErrorContext(
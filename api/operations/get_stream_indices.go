@@ -32,7 +32,8 @@ type GetStreamIndicesDeps struct {
 // field of a stream.
 //
 // It backs query builders that offer the known values of an index. Callers must
-// have the read-streams permission.
+// have the read-streams permission, and the read-ACLs permission for the audit
+// stream.
 func NewGetStreamIndicesUsecase(deps GetStreamIndicesDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -45,6 +46,10 @@ func NewGetStreamIndicesUsecase(deps GetStreamIndicesDeps) usecase.Interactor {
 				req schemas.GetStreamIndicesRequest,
 				resp *schemas.GetStreamIndicesResponse,
 			) error {
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Stream); err != nil {
+					return err
+				}
+
 				indices, err := deps.LogStorage.Distinct(ctx, req.Stream)
 				if err != nil {
 					logger.ErrorContext(
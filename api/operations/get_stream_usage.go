@@ -30,7 +30,8 @@ type GetStreamUsageDeps struct {
 
 // NewGetStreamUsageUsecase reports the storage footprint of a stream.
 //
-// Callers must have the read-streams permission.
+// Callers must have the read-streams permission, and the read-ACLs permission
+// for the audit stream.
 func NewGetStreamUsageUsecase(deps GetStreamUsageDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -43,6 +44,10 @@ func NewGetStreamUsageUsecase(deps GetStreamUsageDeps) usecase.Interactor {
 				req schemas.GetStreamUsageRequest,
 				resp *schemas.GetStreamUsageResponse,
 			) error {
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Stream); err != nil {
+					return err
+				}
+
 				usage, err := deps.LogStorage.StreamUsage(ctx, req.Stream)
 				if err != nil {
 					logger.ErrorContext(
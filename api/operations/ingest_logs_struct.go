@@ -3,7 +3,9 @@ package operations
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"net/http"
 
@@ -20,6 +22,7 @@ import (
 	applog "link-society.com/flowg/internal/app/logging"
 	"link-society.com/flowg/internal/engines/pipelines"
 	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/timestamp"
 
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -29,15 +32,20 @@ type IngestLogsStructDeps struct {
 	fx.In
 
 	AuthStorage    storage.AuthStorage
+	ConfigStorage  storage.ConfigStorage
 	PipelineRunner pipelines.Runner
 }
 
 // NewIngestLogsStructUsecase pushes structured log records through a pipeline.
 //
 // It is the primary ingestion entry point for callers that already hold
-// key/value records. Callers must have the send-logs permission. Ingestion
-// stops at the first record that fails. The request is marked sensitive so the
-// payload stays out of FlowG's own logs.
+// key/value records. Callers must have the send-logs permission.
+//
+// Records may carry their own timestamp in the field named by the request,
+// subject to the system's timestamp policy. Timestamps are all parsed before
+// any record is ingested, so that a malformed one rejects the whole request.
+// Ingestion stops at the first record that fails. The request is marked
+// sensitive so the payload stays out of FlowG's own logs.
 func NewIngestLogsStructUsecase(deps IngestLogsStructDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -52,8 +60,46 @@ func NewIngestLogsStructUsecase(deps IngestLogsStructDeps) usecase.Interactor {
 			) error {
 				applog.MarkSensitive(ctx)
 
-				for _, recordData := range req.Records {
-					record := models.NewLogRecord(recordData)
+				records := make([]*models.LogRecord, len(req.Records))
+				for i, recordData := range req.Records {
+					records[i] = models.NewLogRecord(recordData)
+				}
+
+				if req.TimestampField != "" {
+					systemConfig, err := deps.ConfigStorage.ReadSystemConfig(ctx)
+					if err != nil {
+						logger.ErrorContext(
+							ctx,
+							"Failed to read system configuration",
+							slog.String("error", err.Error()),
+						)
+
+						resp.Success = false
+						return status.Wrap(err, status.Internal)
+					}
+
+					now := time.Now()
+
+					for i, record := range records {
+						value, exists := record.Fields[req.TimestampField]
+						if !exists {
+							continue
+						}
+
+						ts, err := timestamp.Parse(value, req.TimestampFormat)
+						if err != nil {
+							resp.Success = false
+							return status.Wrap(
+								fmt.Errorf("record %d: %w", i, err),
+								status.InvalidArgument,
+							)
+						}
+
+						record.Timestamp = timestamp.ApplyPolicy(systemConfig.TimestampPolicy, ts, now)
+					}
+				}
+
+				for _, record := range records {
 					err := deps.PipelineRunner.Run(
 						ctx,
 						req.Pipeline,
@@ -97,7 +143,7 @@ func NewIngestLogsStructUsecase(deps IngestLogsStructDeps) usecase.Interactor {
 	u.SetDescription("Run structured logs through a pipeline")
 	u.SetTags("pipelines")
 
-	u.SetExpectedErrors(status.PermissionDenied, status.NotFound, status.Internal)
+	u.SetExpectedErrors(status.PermissionDenied, status.InvalidArgument, status.NotFound, status.Internal)
 
 	return u
 }
@@ -0,0 +1,84 @@
+package operations
+
+import (
+	"context"
+	"log/slog"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// ListAlertEventsDeps lists the dependencies of [NewListAlertEventsUsecase].
+type ListAlertEventsDeps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+}
+
+// NewListAlertEventsUsecase returns the recent firings of an alert rule, newest
+// first, including the delivery error of those that could not be forwarded.
+//
+// Callers must have the read-alerts permission.
+func NewListAlertEventsUsecase(deps ListAlertEventsDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_READ_ALERTS,
+			func(
+				ctx context.Context,
+				req schemas.ListAlertEventsRequest,
+				resp *schemas.ListAlertEventsResponse,
+			) error {
+				events, err := deps.ConfigStorage.ListAlertEvents(ctx, req.Alert)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to list alert events",
+						slog.String("alert", req.Alert),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				resp.Success = true
+				resp.Events = events
+
+				return nil
+			},
+		),
+	)
+
+	u.SetName("list_alert_events")
+	u.SetTitle("List Alert Events")
+	u.SetDescription("List the recent firings of an alert")
+	u.SetTags("alerts")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewListAlertEventsUsecase,
+		http.MethodGet,
+		"/api/v1/alerts/{alert}/events",
+	)
+}
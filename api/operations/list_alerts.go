@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// ListAlertsDeps lists the dependencies of [NewListAlertsUsecase].
+type ListAlertsDeps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+}
+
+// NewListAlertsUsecase enumerates the names of all configured alert rules.
+//
+// Callers must have the read-alerts permission. If it is restricted to some
+// alerts only, the others are left out.
+func NewListAlertsUsecase(deps ListAlertsDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireListScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_READ_ALERTS,
+			func(
+				ctx context.Context,
+				req schemas.ListAlertsRequest,
+				resp *schemas.ListAlertsResponse,
+			) error {
+				alerts, err := deps.ConfigStorage.ListAlerts(ctx)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to list alerts",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				canRead, err := auth.ResourceFilter(
+					ctx,
+					deps.AuthStorage,
+					models.SCOPE_READ_ALERTS,
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to verify user permission",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				resp.Success = true
+				resp.Alerts = slices.DeleteFunc(alerts, func(name string) bool {
+					return !canRead(name)
+				})
+
+				return nil
+			},
+		),
+	)
+
+	u.SetName("list_alerts")
+	u.SetTitle("List Alerts")
+	u.SetDescription("List alerts")
+	u.SetTags("alerts")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewListAlertsUsecase,
+		http.MethodGet,
+		"/api/v1/alerts",
+	)
+}
@@ -0,0 +1,85 @@
+package operations
+
+import (
+	"context"
+	"log/slog"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// ListDeadLettersDeps lists the dependencies of [NewListDeadLettersUsecase].
+type ListDeadLettersDeps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+}
+
+// NewListDeadLettersUsecase returns the records a forwarder failed to deliver
+// once its retry policy was exhausted, oldest first, along with their last
+// delivery error.
+//
+// Callers must have the read-forwarders permission.
+func NewListDeadLettersUsecase(deps ListDeadLettersDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_READ_FORWARDERS,
+			func(
+				ctx context.Context,
+				req schemas.ListDeadLettersRequest,
+				resp *schemas.ListDeadLettersResponse,
+			) error {
+				letters, err := deps.ConfigStorage.ListDeadLetters(ctx, req.Forwarder, "", 0)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to list dead letters",
+						slog.String("forwarder", req.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				resp.Success = true
+				resp.DeadLetters = letters
+
+				return nil
+			},
+		),
+	)
+
+	u.SetName("list_dead_letters")
+	u.SetTitle("List Dead Letters")
+	u.SetDescription("List the records a forwarder failed to deliver")
+	u.SetTags("forwarders")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewListDeadLettersUsecase,
+		http.MethodGet,
+		"/api/v1/forwarders/{forwarder}/dead-letters",
+	)
+}
@@ -3,6 +3,7 @@ package operations
 import (
 	"context"
 	"log/slog"
+	"slices"
 
 	"net/http"
 
@@ -30,12 +31,13 @@ type ListForwardersDeps struct {
 
 // NewListForwardersUsecase enumerates the names of all configured forwarders.
 //
-// Callers must have the read-forwarders permission.
+// Callers must have the read-forwarders permission. If it is restricted to some
+// forwarders only, the others are left out.
 func NewListForwardersUsecase(deps ListForwardersDeps) usecase.Interactor {
 	logger := logging.Logger()
 
 	u := usecase.NewInteractor(
-		auth.RequireScopeApiDecorator(
+		auth.RequireListScopeApiDecorator(
 			deps.AuthStorage,
 			models.SCOPE_READ_FORWARDERS,
 			func(
@@ -55,8 +57,26 @@ func NewListForwardersUsecase(deps ListForwardersDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				canRead, err := auth.ResourceFilter(
+					ctx,
+					deps.AuthStorage,
+					models.SCOPE_READ_FORWARDERS,
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to verify user permission",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
 				resp.Success = true
-				resp.Forwarders = forwarders
+				resp.Forwarders = slices.DeleteFunc(forwarders, func(name string) bool {
+					return !canRead(name)
+				})
 
 				return nil
 			},
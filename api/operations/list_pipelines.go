@@ -3,6 +3,7 @@ package operations
 import (
 	"context"
 	"log/slog"
+	"slices"
 
 	"net/http"
 
@@ -30,12 +31,13 @@ type ListPipelinesDeps struct {
 
 // NewListPipelinesUsecase enumerates the names of all configured pipelines.
 //
-// Callers must have the read-pipelines permission.
+// Callers must have the read-pipelines permission. If it is restricted to some
+// pipelines only, the others are left out.
 func NewListPipelinesUsecase(deps ListPipelinesDeps) usecase.Interactor {
 	logger := logging.Logger()
 
 	u := usecase.NewInteractor(
-		auth.RequireScopeApiDecorator(
+		auth.RequireListScopeApiDecorator(
 			deps.AuthStorage,
 			models.SCOPE_READ_PIPELINES,
 			func(
@@ -55,8 +57,26 @@ func NewListPipelinesUsecase(deps ListPipelinesDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				canRead, err := auth.ResourceFilter(
+					ctx,
+					deps.AuthStorage,
+					models.SCOPE_READ_PIPELINES,
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to verify user permission",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
 				resp.Success = true
-				resp.Pipelines = pipelines
+				resp.Pipelines = slices.DeleteFunc(pipelines, func(name string) bool {
+					return !canRead(name)
+				})
 
 				return nil
 			},
@@ -32,7 +32,7 @@ type ListStreamFieldsDeps struct {
 // records.
 //
 // It backs query builders that suggest available fields. Callers must have the
-// read-streams permission.
+// read-streams permission, and the read-ACLs permission for the audit stream.
 func NewListStreamFieldsUsecase(deps ListStreamFieldsDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -45,6 +45,10 @@ func NewListStreamFieldsUsecase(deps ListStreamFieldsDeps) usecase.Interactor {
 				req schemas.ListStreamFieldsRequest,
 				resp *schemas.ListStreamFieldsResponse,
 			) error {
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Stream); err != nil {
+					return err
+				}
+
 				fields, err := deps.LogStorage.ListStreamFields(ctx, req.Stream)
 				if err != nil {
 					logger.ErrorContext(
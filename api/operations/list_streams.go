@@ -3,6 +3,7 @@ package operations
 import (
 	"context"
 	"log/slog"
+	"maps"
 
 	"net/http"
 
@@ -30,12 +31,14 @@ type ListStreamsDeps struct {
 
 // NewListStreamsUsecase enumerates all known streams with their configurations.
 //
-// Callers must have the read-streams permission.
+// Callers must have the read-streams permission. If it is restricted to some
+// streams only, the others are left out. The audit stream is left out as well
+// unless they also have the read-ACLs permission.
 func NewListStreamsUsecase(deps ListStreamsDeps) usecase.Interactor {
 	logger := logging.Logger()
 
 	u := usecase.NewInteractor(
-		auth.RequireScopeApiDecorator(
+		auth.RequireListScopeApiDecorator(
 			deps.AuthStorage,
 			models.SCOPE_READ_STREAMS,
 			func(
@@ -55,6 +58,42 @@ func NewListStreamsUsecase(deps ListStreamsDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				canRead, err := auth.ResourceFilter(
+					ctx,
+					deps.AuthStorage,
+					models.SCOPE_READ_STREAMS,
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to verify user permission",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				canReadAudit, err := canReadAuditStream(ctx, deps.AuthStorage)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to verify user permission",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				maps.DeleteFunc(streams, func(name string, _ models.StreamConfig) bool {
+					if name == models.AUDIT_STREAM && !canReadAudit {
+						return true
+					}
+
+					return !canRead(name)
+				})
+
 				resp.Success = true
 				resp.Streams = streams
 
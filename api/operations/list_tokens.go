@@ -26,11 +26,11 @@ type ListTokensDeps struct {
 	AuthStorage storage.AuthStorage
 }
 
-// NewListTokensUsecase enumerates the identifiers of the calling user's personal
-// access tokens.
+// NewListTokensUsecase enumerates the calling user's personal access tokens.
 //
-// Only the UUIDs are returned; the secret values are never recoverable after
-// creation. It requires authentication but no particular permission.
+// Only their descriptions are returned (UUID, name, scopes, expiry and last
+// use); the secret values are never recoverable after creation. It requires
+// authentication but no particular permission.
 func NewListTokensUsecase(deps ListTokensDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -42,7 +42,7 @@ func NewListTokensUsecase(deps ListTokensDeps) usecase.Interactor {
 		) error {
 			user := auth.GetContextUser(ctx)
 
-			tokenUUIDs, err := deps.AuthStorage.ListTokens(ctx, user.Name)
+			tokens, err := deps.AuthStorage.ListTokens(ctx, user.Name)
 			if err != nil {
 				logger.ErrorContext(
 					ctx,
@@ -56,7 +56,11 @@ func NewListTokensUsecase(deps ListTokensDeps) usecase.Interactor {
 			}
 
 			resp.Success = true
-			resp.TokenUUIDs = tokenUUIDs
+			resp.TokenUUIDs = make([]string, len(tokens))
+			for i, token := range tokens {
+				resp.TokenUUIDs[i] = token.UUID
+			}
+			resp.Tokens = tokens
 
 			return nil
 		},
@@ -64,7 +68,7 @@ func NewListTokensUsecase(deps ListTokensDeps) usecase.Interactor {
 
 	u.SetName("list_tokens")
 	u.SetTitle("List Tokens")
-	u.SetDescription("List Personal Access Token UUIDs for the current user")
+	u.SetDescription("List Personal Access Tokens for the current user")
 	u.SetTags("acls")
 
 	u.SetExpectedErrors(status.Internal)
@@ -3,6 +3,7 @@ package operations
 import (
 	"context"
 	"log/slog"
+	"slices"
 
 	"net/http"
 
@@ -30,12 +31,13 @@ type ListTransformersDeps struct {
 
 // NewListTransformersUsecase enumerates the names of all configured transformers.
 //
-// Callers must have the read-transformers permission.
+// Callers must have the read-transformers permission. If it is restricted
+// to some transformers only, the others are left out.
 func NewListTransformersUsecase(deps ListTransformersDeps) usecase.Interactor {
 	logger := logging.Logger()
 
 	u := usecase.NewInteractor(
-		auth.RequireScopeApiDecorator(
+		auth.RequireListScopeApiDecorator(
 			deps.AuthStorage,
 			models.SCOPE_READ_TRANSFORMERS,
 			func(
@@ -55,8 +57,26 @@ func NewListTransformersUsecase(deps ListTransformersDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				canRead, err := auth.ResourceFilter(
+					ctx,
+					deps.AuthStorage,
+					models.SCOPE_READ_TRANSFORMERS,
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to verify user permission",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
 				resp.Success = true
-				resp.Transformers = transformers
+				resp.Transformers = slices.DeleteFunc(transformers, func(name string) bool {
+					return !canRead(name)
+				})
 
 				return nil
 			},
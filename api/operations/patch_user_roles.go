@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,15 +25,16 @@ import (
 type PatchUserRolesDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewPatchUserRolesUsecase replaces the roles assigned to a user without touching
 // its password.
 //
-// It is the role-management counterpart to [NewSaveUserUsecase], used
-// when the caller does not know or want to reset the password. Callers must
-// have the write-ACLs permission.
+// It is the role-management counterpart to [NewSaveUserUsecase], used when the
+// caller does not know or want to reset the password. Callers must have the
+// write-ACLs permission. The change is recorded in the audit log.
 func NewPatchUserRolesUsecase(deps PatchUserRolesDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -50,7 +52,20 @@ func NewPatchUserRolesUsecase(deps PatchUserRolesDeps) usecase.Interactor {
 					Roles: req.Roles,
 				}
 
-				err := deps.AuthStorage.PatchUserRoles(ctx, user)
+				before, err := deps.AuthStorage.FetchUser(ctx, req.User)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read user",
+						slog.String("user", req.User),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.AuthStorage.PatchUserRoles(ctx, user)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -63,6 +78,15 @@ func NewPatchUserRolesUsecase(deps PatchUserRolesDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"patch_user_roles",
+					"user:"+req.User,
+					before,
+					&user,
+				)
+
 				resp.Success = true
 
 				return nil
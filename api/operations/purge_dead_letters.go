@@ -0,0 +1,94 @@
+package operations
+
+import (
+	"context"
+	"log/slog"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// PurgeDeadLettersDeps lists the dependencies of [NewPurgeDeadLettersUsecase].
+type PurgeDeadLettersDeps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+	AuditRecorder audit.Recorder
+}
+
+// NewPurgeDeadLettersUsecase discards every record in a forwarder's dead-letter
+// queue.
+//
+// Callers must have the write-forwarders permission. The change is recorded in
+// the audit log.
+func NewPurgeDeadLettersUsecase(deps PurgeDeadLettersDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_WRITE_FORWARDERS,
+			func(
+				ctx context.Context,
+				req schemas.PurgeDeadLettersRequest,
+				resp *schemas.PurgeDeadLettersResponse,
+			) error {
+				if err := deps.ConfigStorage.PurgeDeadLetters(ctx, req.Forwarder); err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to purge dead letters",
+						slog.String("forwarder", req.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"purge_dead_letters",
+					"forwarder:"+req.Forwarder,
+					nil,
+					nil,
+				)
+
+				resp.Success = true
+
+				return nil
+			},
+		),
+	)
+
+	u.SetName("purge_dead_letters")
+	u.SetTitle("Purge Dead Letters")
+	u.SetDescription("Discard the records a forwarder failed to deliver")
+	u.SetTags("forwarders")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewPurgeDeadLettersUsecase,
+		http.MethodDelete,
+		"/api/v1/forwarders/{forwarder}/dead-letters",
+	)
+}
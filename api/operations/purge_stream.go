@@ -2,6 +2,7 @@ package operations
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,14 +26,16 @@ import (
 type PurgeStreamDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
-	LogStorage  storage.LogStorage
+	AuthStorage   storage.AuthStorage
+	LogStorage    storage.LogStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewPurgeStreamUsecase removes a stream along with all of its logs and indexes.
 //
 // Callers must have the write-streams permission. This is a destructive
-// operation: the stored logs cannot be recovered afterwards.
+// operation: the stored logs cannot be recovered afterwards. The change is
+// recorded in the audit log, which therefore cannot be purged itself.
 func NewPurgeStreamUsecase(deps PurgeStreamDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -44,6 +48,14 @@ func NewPurgeStreamUsecase(deps PurgeStreamDeps) usecase.Interactor {
 				req schemas.PurgeStreamRequest,
 				resp *schemas.PurgeStreamResponse,
 			) error {
+				if req.Stream == models.AUDIT_STREAM {
+					resp.Success = false
+					return status.Wrap(
+						fmt.Errorf("stream %q is reserved", req.Stream),
+						status.FailedPrecondition,
+					)
+				}
+
 				err := deps.LogStorage.DeleteStream(ctx, req.Stream)
 				if err != nil {
 					logger.ErrorContext(
@@ -57,6 +69,15 @@ func NewPurgeStreamUsecase(deps PurgeStreamDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"purge_stream",
+					"stream:"+req.Stream,
+					nil,
+					nil,
+				)
+
 				logger.InfoContext(
 					ctx,
 					"Log stream purged",
@@ -74,7 +95,11 @@ func NewPurgeStreamUsecase(deps PurgeStreamDeps) usecase.Interactor {
 	u.SetDescription("Remove all logs (and indexes) related to a stream")
 	u.SetTags("streams")
 
-	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
+	u.SetExpectedErrors(
+		status.PermissionDenied,
+		status.FailedPrecondition,
+		status.Internal,
+	)
 
 	return u
 }
@@ -2,6 +2,7 @@ package operations
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"net/http"
@@ -32,8 +33,13 @@ type QueryStreamDeps struct {
 // NewQueryStreamUsecase retrieves the logs of a stream within a time range,
 // optionally narrowed by indexes and a filter expression.
 //
-// Callers must have the read-streams permission. A filter that fails to compile
-// is reported as an invalid-argument error.
+// Results are returned newest first and can be paginated: the response carries
+// a cursor to pass back to fetch the next page whenever the limit was reached
+// before the end of the time range.
+//
+// Callers must have the read-streams permission, and the read-ACLs permission
+// to query the audit stream. A filter that fails to compile, or a cursor that
+// was not issued by a previous query, is reported as an invalid-argument error.
 func NewQueryStreamUsecase(deps QueryStreamDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -46,6 +52,10 @@ func NewQueryStreamUsecase(deps QueryStreamDeps) usecase.Interactor {
 				req schemas.QueryStreamRequest,
 				resp *schemas.QueryStreamResponse,
 			) error {
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Stream); err != nil {
+					return err
+				}
+
 				var filter filtering.Filter
 
 				if req.Filter != nil {
@@ -67,8 +77,23 @@ func NewQueryStreamUsecase(deps QueryStreamDeps) usecase.Interactor {
 					filter = nil
 				}
 
-				records, err := deps.LogStorage.FetchLogs(ctx, req.Stream, req.From, req.To, filter, req.Indexing)
+				records, nextCursor, err := deps.LogStorage.FetchLogs(
+					ctx,
+					req.Stream,
+					req.From, req.To,
+					filter,
+					req.Indexing,
+					req.Cursor,
+					req.Limit,
+				)
 				if err != nil {
+					var cursorErr *storage.InvalidCursorError
+					if errors.As(err, &cursorErr) {
+						resp.Success = false
+						resp.Records = nil
+						return status.Wrap(err, status.InvalidArgument)
+					}
+
 					logger.ErrorContext(
 						ctx,
 						"Failed to query logs",
@@ -80,6 +105,9 @@ func NewQueryStreamUsecase(deps QueryStreamDeps) usecase.Interactor {
 
 				resp.Success = true
 				resp.Records = records
+				if nextCursor != "" {
+					resp.NextCursor = &nextCursor
+				}
 				return nil
 			},
 		),
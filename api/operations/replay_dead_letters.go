@@ -0,0 +1,197 @@
+package operations
+
+import (
+	"context"
+	"log/slog"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/engines/forwarders"
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// defaultReplayLimit is the number of dead letters replayed by a call that does
+// not set a limit.
+const defaultReplayLimit = 100
+
+// ReplayDeadLettersDeps lists the dependencies of [NewReplayDeadLettersUsecase].
+type ReplayDeadLettersDeps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+	AuditRecorder audit.Recorder
+}
+
+// NewReplayDeadLettersUsecase delivers again, oldest first, a page of the
+// records in a forwarder's dead-letter queue, using the forwarder's current
+// configuration and retry policy. Each call replays at most the requested limit
+// and returns a cursor to pass back to replay the next page whenever records
+// may remain after it.
+//
+// Records delivered successfully are removed from the queue; the others are
+// kept, with their original error, for a later replay. Callers must have the
+// write-forwarders permission. The replay is recorded in the audit log.
+func NewReplayDeadLettersUsecase(deps ReplayDeadLettersDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_WRITE_FORWARDERS,
+			func(
+				ctx context.Context,
+				req schemas.ReplayDeadLettersRequest,
+				resp *schemas.ReplayDeadLettersResponse,
+			) error {
+				forwarder, err := deps.ConfigStorage.ReadForwarder(ctx, req.Forwarder)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to get forwarder",
+						slog.String("forwarder", req.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+				if forwarder == nil {
+					resp.Success = false
+					return status.NotFound
+				}
+
+				limit := req.Limit
+				if limit == 0 {
+					limit = defaultReplayLimit
+				}
+
+				letters, err := deps.ConfigStorage.ListDeadLetters(ctx, req.Forwarder, req.Cursor, limit)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to list dead letters",
+						slog.String("forwarder", req.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				runtime, err := forwarders.NewRuntime(forwarder)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to create forwarder runtime",
+						slog.String("forwarder", req.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				if err := runtime.Init(ctx); err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to initialize forwarder",
+						slog.String("forwarder", req.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				defer func() {
+					if err := runtime.Close(ctx); err != nil {
+						logger.WarnContext(
+							ctx,
+							"Failed to shutdown forwarder",
+							slog.String("forwarder", req.Forwarder),
+							slog.String("error", err.Error()),
+						)
+					}
+				}()
+
+				for _, letter := range letters {
+					if err := runtime.Call(ctx, &letter.Record); err != nil {
+						logger.WarnContext(
+							ctx,
+							"Failed to replay dead letter",
+							slog.String("forwarder", req.Forwarder),
+							slog.String("dead_letter", letter.ID),
+							slog.String("error", err.Error()),
+						)
+
+						resp.Failed++
+						continue
+					}
+
+					if err := deps.ConfigStorage.DeleteDeadLetter(ctx, req.Forwarder, letter.ID); err != nil {
+						logger.ErrorContext(
+							ctx,
+							"Failed to delete replayed dead letter",
+							slog.String("forwarder", req.Forwarder),
+							slog.String("dead_letter", letter.ID),
+							slog.String("error", err.Error()),
+						)
+
+						resp.Success = false
+						return status.Wrap(err, status.Internal)
+					}
+
+					resp.Replayed++
+				}
+
+				if len(letters) == limit {
+					cursor := letters[len(letters)-1].ID
+					resp.NextCursor = &cursor
+				}
+
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"replay_dead_letters",
+					"forwarder:"+req.Forwarder,
+					nil,
+					nil,
+				)
+
+				resp.Success = true
+
+				return nil
+			},
+		),
+	)
+
+	u.SetName("replay_dead_letters")
+	u.SetTitle("Replay Dead Letters")
+	u.SetDescription("Deliver again the records a forwarder failed to deliver")
+	u.SetTags("forwarders")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.NotFound, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewReplayDeadLettersUsecase,
+		http.MethodPost,
+		"/api/v1/forwarders/{forwarder}/dead-letters/replay",
+	)
+}
@@ -17,6 +17,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	"link-society.com/flowg/internal/storage/generic/kv"
 	storage "link-society.com/flowg/internal/storage/interfaces"
@@ -26,14 +27,15 @@ import (
 type RestoreAuthDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewRestoreAuthUsecase loads a previously exported authentication database
 // snapshot, replacing the current contents.
 //
-// It is the import counterpart to [NewBackupAuthUsecase]. Callers must
-// have the write-ACLs permission.
+// It is the import counterpart to [NewBackupAuthUsecase]. Callers must have the
+// write-ACLs permission. The change is recorded in the audit log.
 func NewRestoreAuthUsecase(deps RestoreAuthDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -63,6 +65,15 @@ func NewRestoreAuthUsecase(deps RestoreAuthDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"restore_auth",
+					"backup:auth",
+					nil,
+					nil,
+				)
+
 				resp.Success = true
 
 				return nil
@@ -17,6 +17,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	"link-society.com/flowg/internal/storage/generic/kv"
 	storage "link-society.com/flowg/internal/storage/interfaces"
@@ -28,13 +29,15 @@ type RestoreConfigDeps struct {
 
 	AuthStorage   storage.AuthStorage
 	ConfigStorage storage.ConfigStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewRestoreConfigUsecase loads a previously exported configuration database
 // snapshot, replacing the current pipelines, transformers and forwarders.
 //
-// It is the import counterpart to [NewBackupConfigUsecase]. Callers
-// must have write access to pipelines, transformers and forwarders.
+// It is the import counterpart to [NewBackupConfigUsecase]. Callers must have
+// write access to pipelines, transformers and forwarders. The change is
+// recorded in the audit log.
 func NewRestoreConfigUsecase(deps RestoreConfigDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -68,6 +71,15 @@ func NewRestoreConfigUsecase(deps RestoreConfigDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"restore_config",
+					"backup:config",
+					nil,
+					nil,
+				)
+
 				resp.Success = true
 
 				return nil
@@ -17,6 +17,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	"link-society.com/flowg/internal/storage/generic/kv"
 	storage "link-society.com/flowg/internal/storage/interfaces"
@@ -26,15 +27,20 @@ import (
 type RestoreLogsDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
-	LogStorage  storage.LogStorage
+	AuthStorage   storage.AuthStorage
+	LogStorage    storage.LogStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewRestoreLogsUsecase loads a previously exported log database snapshot,
 // replacing the current contents.
 //
-// It is the import counterpart to [NewBackupLogsUsecase]. Callers must
-// have the write-streams permission.
+// It is the import counterpart to [NewBackupLogsUsecase]. Callers must have the
+// write-streams permission. The change is recorded in the audit log.
+//
+// The snapshot is merged into the database, so the records of the reserved
+// [models.AUDIT_STREAM] are kept. Its configuration is kept as well, so that a
+// snapshot cannot shorten the retention of the audit log.
 func NewRestoreLogsUsecase(deps RestoreLogsDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -49,7 +55,18 @@ func NewRestoreLogsUsecase(deps RestoreLogsDeps) usecase.Interactor {
 			) error {
 				defer req.Backup.Close()
 
-				err := deps.LogStorage.Load(ctx, req.Backup)
+				streamConfigs, err := deps.LogStorage.ListStreamConfigs(ctx)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to list stream configurations",
+						slog.String("error", err.Error()),
+					)
+
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.LogStorage.Load(ctx, req.Backup)
 				if err != nil {
 					if errors.Is(err, kv.ErrNotSupported) {
 						return status.Wrap(err, status.Unimplemented)
@@ -64,6 +81,28 @@ func NewRestoreLogsUsecase(deps RestoreLogsDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				// A missing configuration is the zero value, without retention.
+				auditConfig := streamConfigs[models.AUDIT_STREAM]
+				err = deps.LogStorage.ConfigureStream(ctx, models.AUDIT_STREAM, auditConfig)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to keep the configuration of the audit stream",
+						slog.String("error", err.Error()),
+					)
+
+					return status.Wrap(err, status.Internal)
+				}
+
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"restore_logs",
+					"backup:logs",
+					nil,
+					nil,
+				)
+
 				return nil
 			},
 		),
@@ -0,0 +1,215 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/logging"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/api/schemas"
+
+	"link-society.com/flowg/internal/engines/alerts"
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+	"link-society.com/flowg/internal/utils/langs/filtering"
+)
+
+// SaveAlertDeps lists the dependencies of [NewSaveAlertUsecase].
+type SaveAlertDeps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+	AlertManager  alerts.Manager
+	AuditRecorder audit.Recorder
+}
+
+// NewSaveAlertUsecase creates or overwrites an alert rule.
+//
+// Callers must have the write-alerts permission, and be allowed to read the
+// stream the rule counts and the forwarder it fires through, otherwise the
+// rule would send what it learns of a stream they cannot read. A filter that
+// fails to compile or a forwarder that does not exist is reported as an
+// invalid-argument error. Persisting a rule reloads the alert manager so that
+// it is evaluated from then on, with a fresh count. The change is recorded in
+// the audit log.
+func NewSaveAlertUsecase(deps SaveAlertDeps) usecase.Interactor {
+	logger := logging.Logger()
+
+	u := usecase.NewInteractor(
+		auth.RequireScopeApiDecorator(
+			deps.AuthStorage,
+			models.SCOPE_WRITE_ALERTS,
+			func(
+				ctx context.Context,
+				req schemas.SaveAlertRequest,
+				resp *schemas.SaveAlertResponse,
+			) error {
+				if req.Rule.Filter != "" {
+					if _, err := filtering.Compile(req.Rule.Filter); err != nil {
+						logger.ErrorContext(
+							ctx,
+							"Failed to compile alert filter",
+							slog.String("alert", req.Alert),
+							slog.String("error", err.Error()),
+						)
+
+						resp.Success = false
+						return status.Wrap(err, status.InvalidArgument)
+					}
+				}
+
+				canReadStream, err := auth.ResourceFilter(
+					ctx,
+					deps.AuthStorage,
+					models.SCOPE_READ_STREAMS,
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to verify user permission",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				if !canReadStream(req.Rule.Stream) {
+					resp.Success = false
+					return status.Wrap(
+						fmt.Errorf("not allowed to read stream %q", req.Rule.Stream),
+						status.PermissionDenied,
+					)
+				}
+
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Rule.Stream); err != nil {
+					resp.Success = false
+					return err
+				}
+
+				canReadForwarder, err := auth.ResourceFilter(
+					ctx,
+					deps.AuthStorage,
+					models.SCOPE_READ_FORWARDERS,
+				)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to verify user permission",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				if !canReadForwarder(req.Rule.Forwarder) {
+					resp.Success = false
+					return status.Wrap(
+						fmt.Errorf("not allowed to use forwarder %q", req.Rule.Forwarder),
+						status.PermissionDenied,
+					)
+				}
+
+				forwarder, err := deps.ConfigStorage.ReadForwarder(ctx, req.Rule.Forwarder)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read alert forwarder",
+						slog.String("alert", req.Alert),
+						slog.String("forwarder", req.Rule.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+				if forwarder == nil {
+					resp.Success = false
+					return status.Wrap(
+						fmt.Errorf("forwarder %q not found", req.Rule.Forwarder),
+						status.InvalidArgument,
+					)
+				}
+
+				before, err := deps.ConfigStorage.ReadAlert(ctx, req.Alert)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read alert",
+						slog.String("alert", req.Alert),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.ConfigStorage.WriteAlert(ctx, req.Alert, &req.Rule)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to save alert",
+						slog.String("alert", req.Alert),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"save_alert",
+					"alert:"+req.Alert,
+					before,
+					&req.Rule,
+				)
+
+				if err := deps.AlertManager.Reload(ctx); err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to reload alerts after save",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				resp.Success = true
+
+				return nil
+			},
+		),
+	)
+
+	u.SetName("save_alert")
+	u.SetTitle("Save Alert")
+	u.SetDescription("Save alert")
+	u.SetTags("alerts")
+
+	u.SetExpectedErrors(status.PermissionDenied, status.InvalidArgument, status.Internal)
+
+	return u
+}
+
+func init() {
+	routing.RegisterOperation(
+		NewSaveAlertUsecase,
+		http.MethodPut,
+		"/api/v1/alerts/{alert}",
+	)
+}
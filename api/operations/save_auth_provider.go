@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,12 +25,14 @@ import (
 type SaveAuthProviderDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewSaveAuthProviderUsecase creates or overwrites an auth provider.
 //
-// Callers must have the write-auth-providers permission.
+// Callers must have the write-auth-providers permission. The change is recorded
+// in the audit log.
 func NewSaveAuthProviderUsecase(deps SaveAuthProviderDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -43,7 +46,20 @@ func NewSaveAuthProviderUsecase(deps SaveAuthProviderDeps) usecase.Interactor {
 				resp *schemas.SaveAuthProviderResponse,
 			) error {
 				req.Config.Name = req.AuthProvider
-				err := deps.AuthStorage.SaveAuthProvider(ctx, req.Config)
+				before, err := deps.AuthStorage.FetchAuthProvider(ctx, req.AuthProvider)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read auth provider",
+						slog.String("auth_provider", req.AuthProvider),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.AuthStorage.SaveAuthProvider(ctx, req.Config)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -56,6 +72,15 @@ func NewSaveAuthProviderUsecase(deps SaveAuthProviderDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"save_auth_provider",
+					"auth_provider:"+req.AuthProvider,
+					before,
+					&req.Config,
+				)
+
 				resp.Success = true
 
 				return nil
@@ -16,6 +16,8 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/alerts"
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/engines/pipelines"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
@@ -28,13 +30,16 @@ type SaveForwarderDeps struct {
 	AuthStorage    storage.AuthStorage
 	ConfigStorage  storage.ConfigStorage
 	PipelineRunner pipelines.Runner
+	AlertManager   alerts.Manager
+	AuditRecorder  audit.Recorder
 }
 
 // NewSaveForwarderUsecase creates or overwrites a forwarder.
 //
 // Callers must have the write-forwarders permission. Persisting a forwarder
-// invalidates cached pipeline builds so that subsequent runs use the new
-// definition.
+// invalidates cached pipeline builds and reloads the alert rules so that
+// subsequent runs and alerts use the new definition. The change is recorded in
+// the audit log.
 func NewSaveForwarderUsecase(deps SaveForwarderDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -47,7 +52,20 @@ func NewSaveForwarderUsecase(deps SaveForwarderDeps) usecase.Interactor {
 				req schemas.SaveForwarderRequest,
 				resp *schemas.SaveForwarderResponse,
 			) error {
-				err := deps.ConfigStorage.WriteForwarder(ctx, req.Forwarder, &req.Config)
+				before, err := deps.ConfigStorage.ReadForwarder(ctx, req.Forwarder)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read forwarder",
+						slog.String("forwarder", req.Forwarder),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.ConfigStorage.WriteForwarder(ctx, req.Forwarder, &req.Config)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -60,6 +78,15 @@ func NewSaveForwarderUsecase(deps SaveForwarderDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"save_forwarder",
+					"forwarder:"+req.Forwarder,
+					before,
+					&req.Config,
+				)
+
 				if err := deps.PipelineRunner.InvalidateAllCachedBuilds(ctx); err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -71,6 +98,17 @@ func NewSaveForwarderUsecase(deps SaveForwarderDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				if err := deps.AlertManager.Reload(ctx); err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to reload alerts after save",
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
 				resp.Success = true
 
 				return nil
@@ -2,6 +2,7 @@ package operations
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/engines/pipelines"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
@@ -28,12 +30,15 @@ type SavePipelineDeps struct {
 	AuthStorage    storage.AuthStorage
 	ConfigStorage  storage.ConfigStorage
 	PipelineRunner pipelines.Runner
+	AuditRecorder  audit.Recorder
 }
 
 // NewSavePipelineUsecase creates or overwrites a pipeline.
 //
-// Callers must have the write-pipelines permission. Persisting a pipeline
-// invalidates its cached build so that subsequent runs use the new flow graph.
+// Callers must have the write-pipelines permission. Flow graphs whose pipeline
+// nodes lead back to the pipeline being saved are rejected. Persisting a
+// pipeline invalidates its cached build so that subsequent runs use the new
+// flow graph. The change is recorded in the audit log.
 func NewSavePipelineUsecase(deps SavePipelineDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -46,6 +51,38 @@ func NewSavePipelineUsecase(deps SavePipelineDeps) usecase.Interactor {
 				req schemas.SavePipelineRequest,
 				resp *schemas.SavePipelineResponse,
 			) error {
+				err := pipelines.CheckPipelineCycles(ctx, deps.ConfigStorage, req.Pipeline, &req.Flow)
+				if err != nil {
+					var cycleErr *pipelines.PipelineCycleError
+					if errors.As(err, &cycleErr) {
+						resp.Success = false
+						return status.Wrap(err, status.InvalidArgument)
+					}
+
+					logger.ErrorContext(
+						ctx,
+						"Failed to check pipeline for cycles",
+						slog.String("pipeline", req.Pipeline),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				before, err := deps.ConfigStorage.ReadPipeline(ctx, req.Pipeline)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read pipeline",
+						slog.String("pipeline", req.Pipeline),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
 				if err := deps.ConfigStorage.WritePipeline(ctx, req.Pipeline, &req.Flow); err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -58,6 +95,15 @@ func NewSavePipelineUsecase(deps SavePipelineDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"save_pipeline",
+					"pipeline:"+req.Pipeline,
+					before,
+					&req.Flow,
+				)
+
 				if err := deps.PipelineRunner.InvalidateCachedBuild(ctx, req.Pipeline); err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -82,7 +128,7 @@ func NewSavePipelineUsecase(deps SavePipelineDeps) usecase.Interactor {
 	u.SetDescription("Save pipeline")
 	u.SetTags("pipelines")
 
-	u.SetExpectedErrors(status.PermissionDenied, status.Internal)
+	u.SetExpectedErrors(status.PermissionDenied, status.InvalidArgument, status.Internal)
 
 	return u
 }
@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,13 +25,15 @@ import (
 type SaveRoleDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewSaveRoleUsecase creates or overwrites a role with a given set of permissions.
 //
-// Callers must have the write-ACLs permission. An unknown permission name in
-// the request is reported as an invalid-argument error.
+// Callers must have the write-ACLs permission. An unknown permission name, or a
+// malformed resource scope, in the request is reported as an invalid-argument
+// error. The change is recorded in the audit log.
 func NewSaveRoleUsecase(deps SaveRoleDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -62,12 +65,45 @@ func NewSaveRoleUsecase(deps SaveRoleDeps) usecase.Interactor {
 					scopes[i] = scope
 				}
 
+				resourceScopes := make([]models.ResourceScope, len(req.ResourceScopes))
+
+				for i, resourceScopeName := range req.ResourceScopes {
+					resourceScope, err := models.ParseResourceScope(resourceScopeName)
+					if err != nil {
+						logger.ErrorContext(
+							ctx,
+							"Failed to parse resource scope",
+							slog.String("resource_scope", resourceScopeName),
+							slog.String("error", err.Error()),
+						)
+
+						resp.Success = false
+						return status.Wrap(err, status.InvalidArgument)
+					}
+
+					resourceScopes[i] = resourceScope
+				}
+
 				role := models.Role{
-					Name:   req.Role,
-					Scopes: scopes,
+					Name:           req.Role,
+					Scopes:         scopes,
+					ResourceScopes: resourceScopes,
 				}
 
-				err := deps.AuthStorage.SaveRole(ctx, role)
+				before, err := deps.AuthStorage.FetchRole(ctx, req.Role)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read role",
+						slog.String("role", req.Role),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.AuthStorage.SaveRole(ctx, role)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -80,6 +116,15 @@ func NewSaveRoleUsecase(deps SaveRoleDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"save_role",
+					"role:"+req.Role,
+					before,
+					&role,
+				)
+
 				resp.Success = true
 
 				return nil
@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/engines/pipelines"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
@@ -28,13 +29,14 @@ type SaveTransformerDeps struct {
 	AuthStorage    storage.AuthStorage
 	ConfigStorage  storage.ConfigStorage
 	PipelineRunner pipelines.Runner
+	AuditRecorder  audit.Recorder
 }
 
 // NewSaveTransformerUsecase creates or overwrites a transformer.
 //
 // Callers must have the write-transformers permission. Persisting a transformer
 // invalidates cached pipeline builds so that subsequent runs pick up the new
-// source.
+// source. The change is recorded in the audit log.
 func NewSaveTransformerUsecase(deps SaveTransformerDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -47,6 +49,19 @@ func NewSaveTransformerUsecase(deps SaveTransformerDeps) usecase.Interactor {
 				req schemas.SaveTransformerRequest,
 				resp *schemas.SaveTransformerResponse,
 			) error {
+				before, err := deps.ConfigStorage.ReadTransformer(ctx, req.Transformer)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read transformer",
+						slog.String("transformer", req.Transformer),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
 				if err := deps.ConfigStorage.WriteTransformer(ctx, req.Transformer, req.Script); err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -59,6 +74,15 @@ func NewSaveTransformerUsecase(deps SaveTransformerDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"save_transformer",
+					"transformer:"+req.Transformer,
+					before,
+					req.Script,
+				)
+
 				if err := deps.PipelineRunner.InvalidateAllCachedBuilds(ctx); err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -24,13 +25,15 @@ import (
 type SaveUserDeps struct {
 	fx.In
 
-	AuthStorage storage.AuthStorage
+	AuthStorage   storage.AuthStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewSaveUserUsecase creates or overwrites a user account, including its password.
 //
 // Callers must have the write-ACLs permission. To change roles without
-// resetting the password, use [NewPatchUserRolesUsecase] instead.
+// resetting the password, use [NewPatchUserRolesUsecase] instead. The change is
+// recorded in the audit log.
 func NewSaveUserUsecase(deps SaveUserDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -48,7 +51,20 @@ func NewSaveUserUsecase(deps SaveUserDeps) usecase.Interactor {
 					Roles: req.Roles,
 				}
 
-				err := deps.AuthStorage.SaveUser(ctx, user, req.Password)
+				before, err := deps.AuthStorage.FetchUser(ctx, req.User)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read user",
+						slog.String("user", req.User),
+						slog.String("error", err.Error()),
+					)
+
+					resp.Success = false
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.AuthStorage.SaveUser(ctx, user, req.Password)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -61,6 +77,15 @@ func NewSaveUserUsecase(deps SaveUserDeps) usecase.Interactor {
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"save_user",
+					"user:"+req.User,
+					before,
+					&user,
+				)
+
 				resp.Success = true
 
 				return nil
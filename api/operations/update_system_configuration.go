@@ -16,6 +16,7 @@ import (
 	"link-society.com/flowg/api/routing"
 	"link-society.com/flowg/api/schemas"
 
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/models"
 	storage "link-society.com/flowg/internal/storage/interfaces"
 )
@@ -26,11 +27,13 @@ type UpdateSystemConfigurationDeps struct {
 
 	AuthStorage   storage.AuthStorage
 	ConfigStorage storage.ConfigStorage
+	AuditRecorder audit.Recorder
 }
 
 // NewUpdateSystemConfigurationUsecase replaces the global system configuration.
 //
-// Callers must have the write-system-configuration permission.
+// Callers must have the write-system-configuration permission. The change is
+// recorded in the audit log.
 func NewUpdateSystemConfigurationUsecase(deps UpdateSystemConfigurationDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -43,7 +46,18 @@ func NewUpdateSystemConfigurationUsecase(deps UpdateSystemConfigurationDeps) use
 				req schemas.UpdateSystemConfigurationRequest,
 				resp *schemas.UpdateSystemConfigurationResponse,
 			) error {
-				err := deps.ConfigStorage.WriteSystemConfig(ctx, &req)
+				before, err := deps.ConfigStorage.ReadSystemConfig(ctx)
+				if err != nil {
+					logger.ErrorContext(
+						ctx,
+						"Failed to read system configuration",
+						slog.String("error", err.Error()),
+					)
+
+					return status.Wrap(err, status.Internal)
+				}
+
+				err = deps.ConfigStorage.WriteSystemConfig(ctx, &req)
 				if err != nil {
 					logger.ErrorContext(
 						ctx,
@@ -54,6 +68,15 @@ func NewUpdateSystemConfigurationUsecase(deps UpdateSystemConfigurationDeps) use
 					return status.Wrap(err, status.Internal)
 				}
 
+				recordAuditEvent(
+					ctx,
+					deps.AuditRecorder,
+					"update_system_configuration",
+					"system_configuration",
+					before,
+					&req,
+				)
+
 				resp.Success = true
 
 				return nil
@@ -38,8 +38,8 @@ type WatchLogsDeps struct {
 // Server-Sent Events.
 //
 // It subscribes to new records and forwards those matching the optional filter
-// until the client disconnects. Callers must have the read-streams permission.
-// A filter that fails to compile is reported as an invalid-argument error.
+// until the client disconnects. Callers must have the read-streams permission,
+// and the read-ACLs permission to watch the audit stream. A filter that fails to compile is reported as an invalid-argument error.
 func NewWatchLogsUsecase(deps WatchLogsDeps) usecase.Interactor {
 	logger := logging.Logger()
 
@@ -52,6 +52,10 @@ func NewWatchLogsUsecase(deps WatchLogsDeps) usecase.Interactor {
 				req schemas.WatchLogsRequest,
 				resp *schemas.WatchLogsResponse,
 			) error {
+				if err := requireStreamAccess(ctx, deps.AuthStorage, req.Stream); err != nil {
+					return err
+				}
+
 				var filter filtering.Filter
 
 				if req.Filter != nil && *req.Filter != "" {
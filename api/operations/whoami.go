@@ -54,6 +54,23 @@ func NewWhoamiUsecase(deps WhoamiDeps) usecase.Interactor {
 				return status.Wrap(err, status.Internal)
 			}
 
+			resourceScopes, err := deps.AuthStorage.ListUserResourceScopes(ctx, resp.User.Name)
+			if err != nil {
+				logger.ErrorContext(
+					ctx,
+					"Failed to fetch user resource scopes",
+					slog.String("channel", "api"),
+					slog.String("error", err.Error()),
+				)
+				return status.Wrap(err, status.Internal)
+			}
+
+			// A permission restricted to some resources still unlocks the
+			// matching UI affordances, the resources being filtered by the API.
+			for _, resourceScope := range resourceScopes {
+				scopes = append(scopes, resourceScope.Scope())
+			}
+
 			resp.Success = true
 			resp.Permissions = models.PermissionsFromScopes(scopes)
 			return nil
@@ -0,0 +1,39 @@
+package schemas
+
+import (
+	"time"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// AggregateLogsRequest describes statistics to compute over a stream's logs
+// within a time range.
+type AggregateLogsRequest struct {
+	// Stream is the name of the stream to aggregate.
+	Stream string `path:"stream" minLength:"1"`
+	// From is the inclusive lower bound of the time range.
+	From time.Time `query:"from" format:"date-time" required:"true"`
+	// To is the inclusive upper bound of the time range.
+	To time.Time `query:"to" format:"date-time" required:"true"`
+	// Filter is an optional filtering expression to match records against.
+	Filter *string `query:"filter"`
+	// Indexing narrows the aggregation to specific values of indexed fields.
+	Indexing map[string][]string `query:"indexing" collectionFormat:"json"`
+	// Aggregations lists the statistics to compute, each either "count" or
+	// "<function>(<field>)" with function one of count_distinct, min, max and
+	// avg; defaults to "count".
+	Aggregations []string `query:"aggregations" collectionFormat:"csv"`
+	// GroupBy lists the fields whose values split the records into groups.
+	GroupBy []string `query:"group_by" collectionFormat:"csv"`
+	// Interval, when set, is the width of the histogram's time buckets, as a
+	// duration such as "5m" or "1h".
+	Interval *string `query:"interval"`
+}
+
+// AggregateLogsResponse carries the computed statistics.
+type AggregateLogsResponse struct {
+	// Success reports whether the aggregation completed.
+	Success bool `json:"success"`
+	// Buckets holds the statistics of each group and time interval.
+	Buckets []models.AggregationBucket `json:"buckets"`
+}
@@ -1,7 +1,20 @@
 package schemas
 
-// CreateTokenRequest is empty: the token is issued for the calling user.
-type CreateTokenRequest struct{}
+import (
+	"time"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// CreateTokenRequest describes the token to issue for the calling user.
+type CreateTokenRequest struct {
+	// Name identifies the token for its owner, e.g. the agent using it.
+	Name string `json:"name" description:"Name of the token"`
+	// Scopes restricts the token to a subset of the scopes of its owner.
+	Scopes []models.Scope `json:"scopes" description:"Scopes the token is restricted to, empty for every scope of its owner"`
+	// ExpiresAt bounds the lifetime of the token.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time" description:"Expiry of the token, never if unset"`
+}
 
 // CreateTokenResponse carries the newly issued personal access token.
 type CreateTokenResponse struct {
@@ -11,4 +24,6 @@ type CreateTokenResponse struct {
 	Token string `json:"token"`
 	// TokenUUID identifies the token for later listing or deletion.
 	TokenUUID string `json:"token_uuid"`
+	// TokenInfo describes the token, as listed later on.
+	TokenInfo *models.Token `json:"token_info"`
 }
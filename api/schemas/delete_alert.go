@@ -0,0 +1,13 @@
+package schemas
+
+// DeleteAlertRequest identifies the alert rule to remove.
+type DeleteAlertRequest struct {
+	// Alert is the name of the alert rule to delete.
+	Alert string `path:"alert" minLength:"1"`
+}
+
+// DeleteAlertResponse reports the outcome of the deletion.
+type DeleteAlertResponse struct {
+	// Success reports whether the alert rule was removed.
+	Success bool `json:"success"`
+}
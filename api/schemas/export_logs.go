@@ -0,0 +1,38 @@
+package schemas
+
+import (
+	"io"
+	"time"
+)
+
+// ExportLogsRequest describes a bulk export of a stream's logs over a time
+// range.
+type ExportLogsRequest struct {
+	// Stream is the name of the stream to export.
+	Stream string `path:"stream" minLength:"1"`
+	// From is the inclusive lower bound of the time range.
+	From time.Time `query:"from" format:"date-time" required:"true"`
+	// To is the inclusive upper bound of the time range.
+	To time.Time `query:"to" format:"date-time" required:"true"`
+	// Filter is an optional filtering expression to match records against.
+	Filter *string `query:"filter"`
+	// Indexing narrows the export to specific values of indexed fields.
+	Indexing map[string][]string `query:"indexing" collectionFormat:"json"`
+	// Format selects the output encoding: newline-delimited JSON or CSV.
+	Format string `query:"format" enum:"ndjson,csv" default:"ndjson"`
+	// Fields restricts the exported fields, in order; when empty, every field is
+	// exported.
+	Fields []string `query:"fields" collectionFormat:"csv"`
+}
+
+// ExportLogsResponse streams the exported records to the client.
+//
+// It embeds the writer so the records are written out as they are read rather
+// than buffered in memory.
+type ExportLogsResponse struct {
+	Writer io.Writer
+}
+
+func (resp *ExportLogsResponse) SetWriter(w io.Writer) {
+	resp.Writer = w
+}
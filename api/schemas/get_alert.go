@@ -0,0 +1,17 @@
+package schemas
+
+import "link-society.com/flowg/internal/models"
+
+// GetAlertRequest identifies the alert rule to retrieve.
+type GetAlertRequest struct {
+	// Alert is the name of the alert rule to read.
+	Alert string `path:"alert" minLength:"1"`
+}
+
+// GetAlertResponse carries the definition of the requested alert rule.
+type GetAlertResponse struct {
+	// Success reports whether the alert rule was found and returned.
+	Success bool `json:"success"`
+	// Alert is the alert rule's definition.
+	Alert *models.AlertRule `json:"alert"`
+}
@@ -6,6 +6,13 @@ type IngestLogsStructRequest struct {
 	Pipeline string `path:"pipeline" minLength:"1"`
 	// Records are the structured log records to ingest.
 	Records []map[string]string `json:"records" required:"true"`
+	// TimestampField names the field holding each record's timestamp. Records
+	// are stamped with the time of ingestion when unset, or when a record does
+	// not have the field.
+	TimestampField string `json:"timestamp_field,omitempty"`
+	// TimestampFormat is the format of TimestampField: "auto" (the default),
+	// "rfc3339", "unix", "unix_ms", "unix_ns", or a Go time layout.
+	TimestampFormat string `json:"timestamp_format,omitempty"`
 }
 
 // IngestLogsStructResponse reports how many records were processed.
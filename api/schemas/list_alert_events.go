@@ -0,0 +1,17 @@
+package schemas
+
+import "link-society.com/flowg/internal/models"
+
+// ListAlertEventsRequest identifies the alert rule whose history to retrieve.
+type ListAlertEventsRequest struct {
+	// Alert is the name of the alert rule.
+	Alert string `path:"alert" minLength:"1"`
+}
+
+// ListAlertEventsResponse carries the recorded firings of the alert rule.
+type ListAlertEventsResponse struct {
+	// Success reports whether the history was read.
+	Success bool `json:"success"`
+	// Events holds the alert rule's firings, newest first.
+	Events []models.AlertEvent `json:"events"`
+}
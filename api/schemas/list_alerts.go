@@ -0,0 +1,12 @@
+package schemas
+
+// ListAlertsRequest is empty: listing alerts takes no parameters.
+type ListAlertsRequest struct{}
+
+// ListAlertsResponse carries the names of the available alert rules.
+type ListAlertsResponse struct {
+	// Success reports whether the listing completed.
+	Success bool `json:"success"`
+	// Alerts holds the name of every configured alert rule.
+	Alerts []string `json:"alerts"`
+}
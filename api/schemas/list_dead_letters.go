@@ -0,0 +1,20 @@
+package schemas
+
+import "link-society.com/flowg/internal/models"
+
+// ListDeadLettersRequest identifies the forwarder whose dead-letter queue to
+// retrieve.
+type ListDeadLettersRequest struct {
+	// Forwarder is the name of the forwarder.
+	Forwarder string `path:"forwarder" minLength:"1"`
+}
+
+// ListDeadLettersResponse carries the records in the forwarder's dead-letter
+// queue.
+type ListDeadLettersResponse struct {
+	// Success reports whether the queue was read.
+	Success bool `json:"success"`
+	// DeadLetters holds the records the forwarder failed to deliver, oldest
+	// first.
+	DeadLetters []models.DeadLetter `json:"dead_letters"`
+}
@@ -0,0 +1,14 @@
+package schemas
+
+// PurgeDeadLettersRequest identifies the forwarder whose dead-letter queue to
+// empty.
+type PurgeDeadLettersRequest struct {
+	// Forwarder is the name of the forwarder.
+	Forwarder string `path:"forwarder" minLength:"1"`
+}
+
+// PurgeDeadLettersResponse reports the outcome of the purge.
+type PurgeDeadLettersResponse struct {
+	// Success reports whether the queue was emptied.
+	Success bool `json:"success"`
+}
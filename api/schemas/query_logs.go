@@ -18,6 +18,11 @@ type QueryStreamRequest struct {
 	Filter *string `query:"filter"`
 	// Indexing narrows the search to specific values of indexed fields.
 	Indexing map[string][]string `query:"indexing" collectionFormat:"json"`
+	// Limit caps the number of records returned; zero means no limit.
+	Limit int `query:"limit" minimum:"0"`
+	// Cursor resumes the query after the last record of a previous page, as
+	// given by its NextCursor.
+	Cursor string `query:"cursor"`
 }
 
 // QueryStreamResponse carries the records matching the query.
@@ -26,4 +31,6 @@ type QueryStreamResponse struct {
 	Success bool `json:"success"`
 	// Records holds the matching log records.
 	Records []models.LogRecord `json:"records"`
+	// NextCursor, when set, fetches the next (older) page of records.
+	NextCursor *string `json:"next_cursor,omitempty"`
 }
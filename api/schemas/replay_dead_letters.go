@@ -0,0 +1,26 @@
+package schemas
+
+// ReplayDeadLettersRequest identifies the forwarder whose dead-letter queue to
+// replay.
+type ReplayDeadLettersRequest struct {
+	// Forwarder is the name of the forwarder.
+	Forwarder string `path:"forwarder" minLength:"1"`
+	// Limit caps the number of records replayed by this call; zero means 100.
+	Limit int `query:"limit" minimum:"0" maximum:"1000"`
+	// Cursor resumes the replay after the last record of a previous call, as
+	// given by its NextCursor.
+	Cursor string `query:"cursor"`
+}
+
+// ReplayDeadLettersResponse reports how many queued records were delivered.
+type ReplayDeadLettersResponse struct {
+	// Success reports whether the queue was replayed, even if some of its
+	// records failed again.
+	Success bool `json:"success"`
+	// Replayed is the number of records delivered and removed from the queue.
+	Replayed int `json:"replayed"`
+	// Failed is the number of records that failed again and remain queued.
+	Failed int `json:"failed"`
+	// NextCursor, when set, replays the next records of the queue.
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,17 @@
+package schemas
+
+import "link-society.com/flowg/internal/models"
+
+// SaveAlertRequest carries the alert rule name and its new definition.
+type SaveAlertRequest struct {
+	// Alert is the name of the alert rule to create or overwrite.
+	Alert string `path:"alert" minLength:"1"`
+	// Rule is the alert rule definition to store under that name.
+	Rule models.AlertRule `json:"alert" required:"true"`
+}
+
+// SaveAlertResponse reports the outcome of the save.
+type SaveAlertResponse struct {
+	// Success reports whether the alert rule was persisted.
+	Success bool `json:"success"`
+}
@@ -6,6 +6,9 @@ type SaveRoleRequest struct {
 	Role string `path:"role" minLength:"1"`
 	// Scopes are the names of the permissions to grant the role.
 	Scopes []string `json:"scopes" required:"true"`
+	// ResourceScopes are the permissions to grant the role on the resources
+	// matching a pattern only, as "<scope>:[<param>=]<pattern>".
+	ResourceScopes []string `json:"resource_scopes,omitempty"`
 }
 
 // SaveRoleResponse reports the outcome of the save.
@@ -0,0 +1,247 @@
+package sso
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"net"
+	"net/http"
+	"net/url"
+
+	"go.uber.org/fx"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/routing"
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/models"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// Deps lists the dependencies of [NewHandler]: the backends it reads the auth
+// providers from, and provisions the users signing in with them into, the
+// recorder of the changes made to those users, and the options of the HTTP
+// service it is served by.
+type Deps struct {
+	fx.In
+
+	AuthStorage   storage.AuthStorage
+	ConfigStorage storage.ConfigStorage
+	AuditRecorder audit.Recorder
+	Options       Options `optional:"true"`
+}
+
+// Options configures how the sign-in flows reach FlowG back. ExternalURL is
+// the URL the browser reaches FlowG at, mount path included (for example
+// "https://flowg.example.com"), which the callback URLs handed to the auth
+// providers are built from. Without it, they are built from the scheme and
+// host the request was received with: forwarded headers, which any client can
+// set, are never trusted.
+type Options struct {
+	ExternalURL string
+}
+
+// httpClient is used to reach the identity providers.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func init() {
+	routing.RegisterMiddleware(
+		NewHandler,
+		"/api/v1/auth/",
+	)
+}
+
+// NewHandler serves the browser sign-in flows of the auth providers: the login
+// endpoint sends the user to the provider, which sends them back to the
+// callback endpoint (OpenID Connect) or to the assertion consumer service
+// (SAML). On success, the user is redirected to the web UI with a session
+// token, the same one the password login issues.
+func NewHandler(deps Deps) http.Handler {
+	logger := slog.Default().With(slog.String("channel", "api.sso"))
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(
+		"GET /api/v1/auth/{provider}/login",
+		newLoginHandler(deps, logger),
+	)
+
+	mux.HandleFunc(
+		"GET /api/v1/auth/{provider}/callback",
+		newOidcCallbackHandler(deps, logger),
+	)
+
+	mux.HandleFunc(
+		"GET /api/v1/auth/{provider}/metadata",
+		newSamlMetadataHandler(deps, logger),
+	)
+
+	mux.HandleFunc(
+		"POST /api/v1/auth/{provider}/acs",
+		newSamlAcsHandler(deps, logger),
+	)
+
+	return mux
+}
+
+// newLoginHandler starts the sign-in flow of the auth provider, according to
+// its type.
+func newLoginHandler(deps Deps, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := newFailer(w, r, logger)
+
+		provider, err := fetchAuthProvider(deps, r)
+		if err != nil {
+			fail("Unknown auth provider", err)
+			return
+		}
+
+		switch {
+		case provider.Config.Oidc != nil:
+			startOidcSignIn(w, r, deps, provider, fail)
+
+		case provider.Config.Saml != nil:
+			startSamlSignIn(w, r, deps, provider, fail)
+
+		default:
+			fail("Unknown auth provider", errors.New("unsupported auth provider type"))
+		}
+	}
+}
+
+// fetchAuthProvider fetches the auth provider a request is for.
+func fetchAuthProvider(deps Deps, r *http.Request) (*models.AuthProvider, error) {
+	provider, err := deps.AuthStorage.FetchAuthProvider(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		return nil, err
+	}
+
+	if provider == nil {
+		return nil, errors.New("auth provider not found")
+	}
+
+	return provider, nil
+}
+
+// newFailer returns the function ending a sign-in flow on error: it logs the
+// error, and sends the user back to the web UI with the given message.
+func newFailer(w http.ResponseWriter, r *http.Request, logger *slog.Logger) func(string, error) {
+	return func(msg string, err error) {
+		logger.ErrorContext(
+			r.Context(),
+			msg,
+			slog.String("provider", r.PathValue("provider")),
+			slog.String("error", err.Error()),
+		)
+		redirectToWeb(w, r, "error", msg)
+	}
+}
+
+// completeSignIn provisions the user the auth provider vouched for, and hands
+// a session token to the web UI. The subject is the stable identifier of the
+// user at the provider, which the FlowG user is linked to, while the username
+// only names the FlowG user created on the first sign-in.
+func completeSignIn(
+	w http.ResponseWriter,
+	r *http.Request,
+	deps Deps,
+	logger *slog.Logger,
+	subject string,
+	username string,
+	groups []string,
+) {
+	fail := newFailer(w, r, logger)
+
+	if subject == "" {
+		fail("Failed to verify the identity", errors.New("no subject"))
+		return
+	}
+
+	if username == "" {
+		fail("Failed to verify the identity", errors.New("no username"))
+		return
+	}
+
+	ctx := auth.ContextWithSourceIP(r.Context(), sourceIP(r))
+
+	username, err := provisionUser(
+		ctx,
+		deps,
+		r.PathValue("provider"),
+		subject,
+		username,
+		groups,
+	)
+	if errors.Is(err, errUserNotLinked) {
+		fail("A user with the same name already exists", err)
+		return
+	}
+	if err != nil {
+		fail("Failed to provision the user", err)
+		return
+	}
+
+	token, err := auth.NewJWT(username)
+	if err != nil {
+		fail("Failed to create the session", err)
+		return
+	}
+
+	logger.InfoContext(
+		r.Context(),
+		"User signed in",
+		slog.String("provider", r.PathValue("provider")),
+		slog.String("username", username),
+		slog.String("subject", subject),
+	)
+
+	redirectToWeb(w, r, "token", token)
+}
+
+// sourceIP returns the IP address a request was received from.
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// mountPath returns the path FlowG is mounted under, which is stripped from
+// the URL of the request before it reaches the API, but not from its original
+// request URI.
+func mountPath(r *http.Request) string {
+	path := r.URL.Path
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		path = u.Path
+	}
+
+	mount, _, _ := strings.Cut(path, "/api/v1/auth/")
+	return mount
+}
+
+// externalURL returns the absolute URL of a path below the mount path, as
+// reached by the browser: below the configured external URL, or else on the
+// scheme and host the request was received with.
+func externalURL(deps Deps, r *http.Request, path string) string {
+	if deps.Options.ExternalURL != "" {
+		return strings.TrimSuffix(deps.Options.ExternalURL, "/") + path
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host + mountPath(r) + path
+}
+
+// redirectToWeb sends the user back to the login page of the web UI, with
+// either the session token or the reason of the failure in the fragment, so
+// it never reaches server logs.
+func redirectToWeb(w http.ResponseWriter, r *http.Request, key string, value string) {
+	fragment := url.Values{key: {value}}.Encode()
+	http.Redirect(w, r, mountPath(r)+"/web/login#"+fragment, http.StatusFound)
+}
@@ -0,0 +1,110 @@
+package sso
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"net/http"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/oidc"
+)
+
+// newOidcRelyingParty discovers the endpoints of an OpenID Connect provider.
+func newOidcRelyingParty(r *http.Request, deps Deps, provider *models.AuthProvider) (*oidc.RelyingParty, error) {
+	callbackURL := externalURL(deps, r, "/api/v1/auth/"+provider.Name+"/callback")
+	return oidc.NewRelyingParty(r.Context(), httpClient, provider.Config.Oidc, callbackURL)
+}
+
+// startOidcSignIn starts the authorization code flow, with PKCE: it sends the
+// user to the provider, remembering the flow's state in a cookie.
+func startOidcSignIn(
+	w http.ResponseWriter,
+	r *http.Request,
+	deps Deps,
+	provider *models.AuthProvider,
+	fail func(string, error),
+) {
+	rp, err := newOidcRelyingParty(r, deps, provider)
+	if err != nil {
+		fail("Failed to reach the auth provider", err)
+		return
+	}
+
+	var values [3]string
+	for i := range values {
+		values[i], err = oidc.NewVerifier()
+		if err != nil {
+			fail("Failed to start the sign-in", err)
+			return
+		}
+	}
+
+	s := session{
+		Provider: provider.Name,
+		State:    values[0],
+		Nonce:    values[1],
+		Verifier: values[2],
+	}
+
+	if err := setSession(w, r, deps, s, http.SameSiteLaxMode); err != nil {
+		fail("Failed to start the sign-in", err)
+		return
+	}
+
+	http.Redirect(w, r, rp.AuthCodeURL(s.State, s.Nonce, s.Verifier), http.StatusFound)
+}
+
+// newOidcCallbackHandler completes the authorization code flow: it redeems the
+// code for an ID token, provisions the user it was issued for, and hands a
+// session token to the web UI.
+func newOidcCallbackHandler(deps Deps, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := newFailer(w, r, logger)
+
+		s, err := popSession(w, r, r.PathValue("provider"))
+		if err != nil {
+			fail("Sign-in session expired, please try again", err)
+			return
+		}
+
+		query := r.URL.Query()
+
+		if errCode := query.Get("error"); errCode != "" {
+			fail(
+				"The auth provider denied the sign-in",
+				errors.New(strings.TrimSpace(errCode+" "+query.Get("error_description"))),
+			)
+			return
+		}
+
+		if query.Get("state") != s.State {
+			fail("Sign-in session expired, please try again", errors.New("state mismatch"))
+			return
+		}
+
+		provider, err := fetchAuthProvider(deps, r)
+		if err == nil && provider.Config.Oidc == nil {
+			err = errors.New("not an OpenID Connect provider")
+		}
+		if err != nil {
+			fail("Unknown auth provider", err)
+			return
+		}
+
+		rp, err := newOidcRelyingParty(r, deps, provider)
+		if err != nil {
+			fail("Failed to reach the auth provider", err)
+			return
+		}
+
+		identity, err := rp.Exchange(r.Context(), query.Get("code"), s.Verifier, s.Nonce)
+		if err != nil {
+			fail("Failed to verify the identity", err)
+			return
+		}
+
+		completeSignIn(w, r, deps, logger, identity.Subject, identity.Username, identity.Groups)
+	}
+}
@@ -0,0 +1,394 @@
+package sso_test
+
+import (
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/sso"
+	"link-society.com/flowg/internal/models"
+
+	auditMocks "link-society.com/flowg/internal/engines/audit/mocks"
+	storageMocks "link-society.com/flowg/internal/storage/mocks"
+)
+
+// mockIdP is a minimal OpenID Provider, issuing ID tokens for the configured
+// user to whoever completes the authorization code flow with PKCE.
+type mockIdP struct {
+	*httptest.Server
+
+	key      *rsa.PrivateKey
+	username string
+	groups   []string
+
+	// requests maps the issued authorization codes to their authorization
+	// request.
+	requests map[string]url.Values
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	idp := &mockIdP{key: key, requests: map[string]url.Values{}}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	mux.HandleFunc("GET /authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("client_id") != "flowg" || query.Get("code_challenge_method") != "S256" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		code := "code-" + query.Get("state")
+		idp.requests[code] = query
+
+		redirect := query.Get("redirect_uri") + "?" + url.Values{
+			"code":  {code},
+			"state": {query.Get("state")},
+		}.Encode()
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "flowg" || clientSecret != "secret" {
+			http.Error(w, `{"error": "invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+
+		authRequest, exists := idp.requests[r.PostFormValue("code")]
+		if !exists || authRequest.Get("redirect_uri") != r.PostFormValue("redirect_uri") {
+			http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		challenge := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(challenge[:]) != authRequest.Get("code_challenge") {
+			http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":                idp.URL,
+			"aud":                "flowg",
+			"sub":                "id-" + idp.username,
+			"iat":                time.Now().Unix(),
+			"exp":                time.Now().Add(time.Minute).Unix(),
+			"nonce":              authRequest.Get("nonce"),
+			"preferred_username": idp.username,
+			"groups":             idp.groups,
+		})
+		token.Header["kid"] = "test"
+
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, `{"error": "server_error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+
+	return idp
+}
+
+// signIn runs the browser side of the flow, and returns the fragment of the
+// web UI page it ends on.
+func signIn(t *testing.T, flowg *httptest.Server, provider string) url.Values {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("failed to create cookie jar: %v", err)
+	}
+
+	client := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if strings.HasPrefix(req.URL.Path, "/web/") {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Get(flowg.URL + "/api/v1/auth/" + provider + "/login")
+	if err != nil {
+		t.Fatalf("failed to sign in: %v", err)
+	}
+	defer resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound || location.Path != "/web/login" {
+		t.Fatalf("expected a redirection to the web UI, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	fragment, err := url.ParseQuery(location.Fragment)
+	if err != nil {
+		t.Fatalf("invalid fragment %q: %v", location.Fragment, err)
+	}
+
+	return fragment
+}
+
+// oidcRedirectURI starts a sign-in, and returns the redirect URI FlowG hands
+// to the provider.
+func oidcRedirectURI(t *testing.T, req *http.Request) string {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to sign in: %v", err)
+	}
+	defer resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected a redirection to the provider, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	return location.Query().Get("redirect_uri")
+}
+
+func TestOidcSignIn(t *testing.T) {
+	idp := newMockIdP(t)
+
+	mockAuthStorage := storageMocks.NewMockAuthStorage().(*storageMocks.MockAuthStorage)
+	mockConfigStorage := storageMocks.NewMockConfigStorage().(*storageMocks.MockConfigStorage)
+	mockAuditRecorder := auditMocks.NewMockRecorder().(*auditMocks.MockRecorder)
+
+	mockAuthStorage.On("FetchAuthProvider", mock.Anything, "corp").
+		Return(
+			&models.AuthProvider{
+				Name:        "corp",
+				DisplayName: "Corp",
+				Config: models.AuthProviderConfig{
+					Oidc: &models.AuthProviderOidc{
+						Type:         "oidc",
+						Issuer:       idp.URL,
+						ClientID:     "flowg",
+						ClientSecret: "secret",
+					},
+				},
+			},
+			nil,
+		)
+	mockAuthStorage.On("ListRoles", mock.Anything).
+		Return([]models.Role{{Name: "admin"}, {Name: "editor"}, {Name: "viewer"}}, nil)
+
+	mockConfigStorage.On("ReadSystemConfig", mock.Anything).
+		Return(&models.SystemConfiguration{DefaultRoles: []string{"viewer"}}, nil)
+
+	flowg := httptest.NewServer(sso.NewHandler(sso.Deps{
+		AuthStorage:   mockAuthStorage,
+		ConfigStorage: mockConfigStorage,
+		AuditRecorder: mockAuditRecorder,
+	}))
+	defer flowg.Close()
+
+	t.Run("provisions a user with the roles named by its groups", func(t *testing.T) {
+		idp.username = "alice"
+		idp.groups = []string{"engineering", "editor"}
+
+		mockAuthStorage.On("FetchLinkedUser", mock.Anything, "corp", "id-alice").
+			Return("", nil).
+			Once()
+		mockAuthStorage.On("CreateLinkedUser", mock.Anything, "corp", "id-alice", models.User{Name: "alice", Roles: []string{"editor"}}, mock.Anything).
+			Return(true, nil).
+			Once()
+		mockAuditRecorder.On("Record", mock.Anything, mock.MatchedBy(func(event models.AuditEvent) bool {
+			return event.Operation == "sso_create_user" &&
+				event.Actor == "alice" &&
+				event.Target == "user:alice" &&
+				event.SourceIP == "127.0.0.1"
+		})).
+			Return(nil).
+			Once()
+
+		fragment := signIn(t, flowg, "corp")
+
+		username, err := auth.VerifyJWT(fragment.Get("token"))
+		if err != nil {
+			t.Fatalf("expected a session token, got %v: %v", fragment, err)
+		}
+		if username != "alice" {
+			t.Fatalf("expected a session token for alice, got %q", username)
+		}
+	})
+
+	t.Run("falls back to the default roles", func(t *testing.T) {
+		idp.username = "bob"
+		idp.groups = nil
+
+		mockAuthStorage.On("FetchLinkedUser", mock.Anything, "corp", "id-bob").
+			Return("", nil).
+			Once()
+		mockAuthStorage.On("CreateLinkedUser", mock.Anything, "corp", "id-bob", models.User{Name: "bob", Roles: []string{"viewer"}}, mock.Anything).
+			Return(true, nil).
+			Once()
+		mockAuditRecorder.On("Record", mock.Anything, mock.MatchedBy(func(event models.AuditEvent) bool {
+			return event.Operation == "sso_create_user" &&
+				event.Actor == "bob" &&
+				event.Target == "user:bob" &&
+				event.SourceIP == "127.0.0.1"
+		})).
+			Return(nil).
+			Once()
+
+		fragment := signIn(t, flowg, "corp")
+		if _, err := auth.VerifyJWT(fragment.Get("token")); err != nil {
+			t.Fatalf("expected a session token, got %v: %v", fragment, err)
+		}
+	})
+
+	t.Run("syncs the roles of a linked user", func(t *testing.T) {
+		idp.username = "carol"
+		idp.groups = []string{"admin"}
+
+		mockAuthStorage.On("FetchLinkedUser", mock.Anything, "corp", "id-carol").
+			Return("carol", nil).
+			Once()
+		mockAuthStorage.On("FetchUser", mock.Anything, "carol").
+			Return(&models.User{Name: "carol", Roles: []string{"viewer"}}, nil).
+			Once()
+		mockAuthStorage.On("PatchUserRoles", mock.Anything, models.User{Name: "carol", Roles: []string{"admin"}}).
+			Return(nil).
+			Once()
+		mockAuditRecorder.On("Record", mock.Anything, mock.MatchedBy(func(event models.AuditEvent) bool {
+			return event.Operation == "sso_patch_user_roles" &&
+				event.Actor == "carol" &&
+				event.Target == "user:carol" &&
+				event.SourceIP == "127.0.0.1"
+		})).
+			Return(nil).
+			Once()
+
+		fragment := signIn(t, flowg, "corp")
+		if _, err := auth.VerifyJWT(fragment.Get("token")); err != nil {
+			t.Fatalf("expected a session token, got %v: %v", fragment, err)
+		}
+	})
+
+	t.Run("refuses to sign in as an existing user it is not linked to", func(t *testing.T) {
+		idp.username = "root"
+		idp.groups = nil
+
+		mockAuthStorage.On("FetchLinkedUser", mock.Anything, "corp", "id-root").
+			Return("", nil).
+			Once()
+		mockAuthStorage.On("CreateLinkedUser", mock.Anything, "corp", "id-root", models.User{Name: "root", Roles: []string{"viewer"}}, mock.Anything).
+			Return(false, nil).
+			Once()
+
+		fragment := signIn(t, flowg, "corp")
+		if fragment.Has("token") || fragment.Get("error") == "" {
+			t.Fatalf("expected the sign-in to fail, got %v", fragment)
+		}
+	})
+
+	t.Run("ignores forwarded headers in the redirect URI", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, flowg.URL+"/api/v1/auth/corp/login", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-Host", "attacker.example.com")
+
+		redirectURI := oidcRedirectURI(t, req)
+		if redirectURI != flowg.URL+"/api/v1/auth/corp/callback" {
+			t.Fatalf("expected the redirect URI to be built from the request, got %q", redirectURI)
+		}
+	})
+
+	t.Run("builds the redirect URI from the external URL", func(t *testing.T) {
+		external := httptest.NewServer(sso.NewHandler(sso.Deps{
+			AuthStorage:   mockAuthStorage,
+			ConfigStorage: mockConfigStorage,
+			AuditRecorder: mockAuditRecorder,
+			Options:       sso.Options{ExternalURL: "https://flowg.example.com/logs"},
+		}))
+		defer external.Close()
+
+		req, err := http.NewRequest(http.MethodGet, external.URL+"/api/v1/auth/corp/login", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		redirectURI := oidcRedirectURI(t, req)
+		if redirectURI != "https://flowg.example.com/logs/api/v1/auth/corp/callback" {
+			t.Fatalf("expected the redirect URI to be built from the external URL, got %q", redirectURI)
+		}
+	})
+
+	t.Run("rejects a callback without session", func(t *testing.T) {
+		client := &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		resp, err := client.Get(flowg.URL + "/api/v1/auth/corp/callback?code=forged&state=forged")
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		location := resp.Header.Get("Location")
+		if !strings.HasPrefix(location, "/web/login#error=") {
+			t.Fatalf("expected the callback to fail, got %q", location)
+		}
+	})
+
+	mockAuthStorage.AssertExpectations(t)
+	mockConfigStorage.AssertExpectations(t)
+	mockAuditRecorder.AssertExpectations(t)
+}
@@ -0,0 +1,326 @@
+package sso
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+
+	"net/http"
+	"net/url"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/oidc"
+)
+
+// DEFAULT_SAML_GROUPS_ATTRIBUTE is the assertion attribute listing the groups
+// of the user, unless the provider configures another one.
+const DEFAULT_SAML_GROUPS_ATTRIBUTE = "groups"
+
+// fetchSamlProvider fetches the SAML provider a request is for.
+func fetchSamlProvider(deps Deps, r *http.Request) (*models.AuthProvider, error) {
+	provider, err := fetchAuthProvider(deps, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if provider.Config.Saml == nil {
+		return nil, errors.New("not a SAML provider")
+	}
+
+	return provider, nil
+}
+
+// newSamlServiceProvider describes FlowG as the service provider of a SAML
+// provider. Its entity ID is the URL of its metadata.
+func newSamlServiceProvider(r *http.Request, deps Deps, provider *models.AuthProvider) (*saml.ServiceProvider, error) {
+	basePath := "/api/v1/auth/" + provider.Name
+
+	metadataURL, err := url.Parse(externalURL(deps, r, basePath+"/metadata"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata URL: %w", err)
+	}
+
+	acsURL, err := url.Parse(externalURL(deps, r, basePath+"/acs"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid assertion consumer service URL: %w", err)
+	}
+
+	return &saml.ServiceProvider{
+		EntityID:          metadataURL.String(),
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		AuthnNameIDFormat: saml.UnspecifiedNameIDFormat,
+		HTTPClient:        httpClient,
+	}, nil
+}
+
+// loadSamlIdp fetches the metadata of the identity provider, and the
+// certificate its assertions must be signed with, if pinned. Unless it is, the
+// certificates of the metadata are trusted, so it must be fetched over HTTPS.
+func loadSamlIdp(r *http.Request, sp *saml.ServiceProvider, config *models.AuthProviderSaml) error {
+	metadataURL, err := url.Parse(config.IdpMetadataURL)
+	if err != nil {
+		return fmt.Errorf("invalid IdP metadata URL: %w", err)
+	}
+
+	if config.IdpCertificate == "" && metadataURL.Scheme != "https" {
+		return errors.New("IdP metadata URL must use HTTPS unless the IdP certificate is pinned")
+	}
+
+	sp.IDPMetadata, err = samlsp.FetchMetadata(r.Context(), httpClient, *metadataURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch IdP metadata: %w", err)
+	}
+
+	if config.IdpCertificate != "" {
+		block, _ := pem.Decode([]byte(config.IdpCertificate))
+		if block == nil || block.Type != "CERTIFICATE" {
+			return errors.New("invalid IdP certificate: no PEM encoded certificate")
+		}
+
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("invalid IdP certificate: %w", err)
+		}
+
+		certificate := base64.StdEncoding.EncodeToString(block.Bytes)
+		sp.IDPCertificate = &certificate
+	}
+
+	return nil
+}
+
+// newSamlMetadataHandler serves the metadata of FlowG as the service provider
+// of a SAML provider, to register it with the identity provider.
+func newSamlMetadataHandler(deps Deps, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		provider, err := fetchSamlProvider(deps, r)
+		if err != nil {
+			http.Error(w, "Unknown auth provider", http.StatusNotFound)
+			return
+		}
+
+		sp, err := newSamlServiceProvider(r, deps, provider)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to describe the service provider",
+				slog.String("provider", provider.Name),
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		metadata := sp.Metadata()
+
+		// Only the HTTP-POST binding is supported: resolving artifacts would
+		// require FlowG to sign its requests.
+		for i := range metadata.SPSSODescriptors {
+			descriptor := &metadata.SPSSODescriptors[i]
+			services := descriptor.AssertionConsumerServices[:0]
+			for _, service := range descriptor.AssertionConsumerServices {
+				if service.Binding == saml.HTTPPostBinding {
+					services = append(services, service)
+				}
+			}
+			descriptor.AssertionConsumerServices = services
+		}
+
+		body, err := xml.MarshalIndent(metadata, "", "  ")
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"Failed to marshal the service provider metadata",
+				slog.String("provider", provider.Name),
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/samlmetadata+xml")
+		w.Write(body)
+	}
+}
+
+// startSamlSignIn sends the user to the identity provider with an
+// authentication request, remembering its ID in a cookie.
+func startSamlSignIn(
+	w http.ResponseWriter,
+	r *http.Request,
+	deps Deps,
+	provider *models.AuthProvider,
+	fail func(string, error),
+) {
+	sp, err := newSamlServiceProvider(r, deps, provider)
+	if err != nil {
+		fail("Failed to start the sign-in", err)
+		return
+	}
+
+	if err := loadSamlIdp(r, sp, provider.Config.Saml); err != nil {
+		fail("Failed to reach the auth provider", err)
+		return
+	}
+
+	ssoURL := sp.GetSSOBindingLocation(saml.HTTPRedirectBinding)
+	if ssoURL == "" {
+		fail(
+			"Failed to reach the auth provider",
+			errors.New("no HTTP-Redirect binding in IdP metadata"),
+		)
+		return
+	}
+
+	req, err := sp.MakeAuthenticationRequest(ssoURL, saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		fail("Failed to start the sign-in", err)
+		return
+	}
+
+	relayState, err := oidc.NewVerifier()
+	if err != nil {
+		fail("Failed to start the sign-in", err)
+		return
+	}
+
+	s := session{
+		Provider:  provider.Name,
+		State:     relayState,
+		RequestID: req.ID,
+	}
+
+	// The identity provider sends the user back with a cross-site POST, which
+	// only carries cookies allowed for all cross-site requests. Browsers only
+	// accept those over HTTPS, otherwise the lax default of most browsers
+	// still lets the cookie through shortly after it was set.
+	sameSite := http.SameSiteDefaultMode
+	if isSecure(deps, r) {
+		sameSite = http.SameSiteNoneMode
+	}
+
+	if err := setSession(w, r, deps, s, sameSite); err != nil {
+		fail("Failed to start the sign-in", err)
+		return
+	}
+
+	redirectURL, err := req.Redirect(s.State, sp)
+	if err != nil {
+		fail("Failed to start the sign-in", err)
+		return
+	}
+
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+// newSamlAcsHandler is the assertion consumer service: it validates the
+// signed assertion the identity provider posts back, provisions the user it
+// was issued for, and hands a session token to the web UI.
+func newSamlAcsHandler(deps Deps, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := newFailer(w, r, logger)
+
+		if err := r.ParseForm(); err != nil {
+			fail("Failed to verify the identity", err)
+			return
+		}
+
+		s, err := popSession(w, r, r.PathValue("provider"))
+		if err != nil {
+			fail("Sign-in session expired, please try again", err)
+			return
+		}
+
+		if r.PostForm.Get("RelayState") != s.State {
+			fail("Sign-in session expired, please try again", errors.New("relay state mismatch"))
+			return
+		}
+
+		provider, err := fetchSamlProvider(deps, r)
+		if err != nil {
+			fail("Unknown auth provider", err)
+			return
+		}
+
+		sp, err := newSamlServiceProvider(r, deps, provider)
+		if err != nil {
+			fail("Failed to verify the identity", err)
+			return
+		}
+
+		if err := loadSamlIdp(r, sp, provider.Config.Saml); err != nil {
+			fail("Failed to reach the auth provider", err)
+			return
+		}
+
+		assertion, err := sp.ParseResponse(r, []string{s.RequestID})
+		if err != nil {
+			var invalidResponseErr *saml.InvalidResponseError
+			if errors.As(err, &invalidResponseErr) && invalidResponseErr.PrivateErr != nil {
+				err = invalidResponseErr.PrivateErr
+			}
+
+			fail("Failed to verify the identity", err)
+			return
+		}
+
+		subject := ""
+		if assertion.Subject != nil && assertion.Subject.NameID != nil {
+			// A transient NameID changes on every sign-in, so it can not be
+			// linked to a user.
+			if assertion.Subject.NameID.Format == string(saml.TransientNameIDFormat) {
+				fail("Failed to verify the identity", errors.New("transient NameID"))
+				return
+			}
+
+			subject = assertion.Subject.NameID.Value
+		}
+
+		username := subject
+		if attribute := provider.Config.Saml.UsernameAttribute; attribute != "" {
+			username = ""
+			if values := samlAttributeValues(assertion, attribute); len(values) > 0 {
+				username = values[0]
+			}
+		}
+
+		groupsAttribute := provider.Config.Saml.GroupsAttribute
+		if groupsAttribute == "" {
+			groupsAttribute = DEFAULT_SAML_GROUPS_ATTRIBUTE
+		}
+
+		groups := samlAttributeValues(assertion, groupsAttribute)
+
+		completeSignIn(w, r, deps, logger, subject, username, groups)
+	}
+}
+
+// samlAttributeValues returns the values of an assertion attribute, matched by
+// name or friendly name.
+func samlAttributeValues(assertion *saml.Assertion, name string) []string {
+	values := []string{}
+
+	for _, statement := range assertion.AttributeStatements {
+		for _, attribute := range statement.Attributes {
+			if attribute.Name != name && attribute.FriendlyName != name {
+				continue
+			}
+
+			for _, value := range attribute.Values {
+				if value.Value != "" {
+					values = append(values, value.Value)
+				}
+			}
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,376 @@
+package sso_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"html"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/logger"
+	"github.com/crewjam/saml/samlsp"
+	"github.com/stretchr/testify/mock"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/api/sso"
+	"link-society.com/flowg/internal/models"
+
+	auditMocks "link-society.com/flowg/internal/engines/audit/mocks"
+	storageMocks "link-society.com/flowg/internal/storage/mocks"
+)
+
+// mockSamlIdP is a minimal SAML identity provider, asserting the identity of
+// the configured user to whichever service provider asks.
+type mockSamlIdP struct {
+	*httptest.Server
+
+	idp          *saml.IdentityProvider
+	username     string
+	groups       []string
+	nameIDFormat string
+}
+
+func newSamlKeyPair(t *testing.T) (*rsa.PrivateKey, *x509.Certificate) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return key, cert
+}
+
+func newMockSamlIdP(t *testing.T) *mockSamlIdP {
+	key, cert := newSamlKeyPair(t)
+
+	m := &mockSamlIdP{nameIDFormat: string(saml.PersistentNameIDFormat)}
+	m.Server = httptest.NewUnstartedServer(nil)
+
+	metadataURL, _ := url.Parse("http://" + m.Listener.Addr().String() + "/metadata")
+	ssoURL, _ := url.Parse("http://" + m.Listener.Addr().String() + "/sso")
+
+	m.idp = &saml.IdentityProvider{
+		Key:                     key,
+		Signer:                  key,
+		Logger:                  logger.DefaultLogger,
+		Certificate:             cert,
+		MetadataURL:             *metadataURL,
+		SSOURL:                  *ssoURL,
+		ServiceProviderProvider: m,
+		SessionProvider:         m,
+	}
+
+	m.Config.Handler = m.idp.Handler()
+	m.Start()
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+func (m *mockSamlIdP) GetSession(w http.ResponseWriter, r *http.Request, req *saml.IdpAuthnRequest) *saml.Session {
+	return &saml.Session{
+		ID:           "session-" + m.username,
+		NameID:       "id-" + m.username,
+		NameIDFormat: m.nameIDFormat,
+		UserName:     m.username,
+		Groups:       m.groups,
+	}
+}
+
+// GetServiceProvider fetches the metadata of the service provider, whose
+// entity ID is the URL of its metadata.
+func (m *mockSamlIdP) GetServiceProvider(r *http.Request, serviceProviderID string) (*saml.EntityDescriptor, error) {
+	resp, err := http.Get(serviceProviderID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return samlsp.ParseMetadata(body)
+}
+
+var samlFormInput = regexp.MustCompile(`name="(SAMLResponse|RelayState)" value="([^"]*)"`)
+
+// samlSignIn runs the browser side of the flow, posting the response of the
+// identity provider to the assertion consumer service, and returns the
+// fragment of the web UI page it ends on.
+func samlSignIn(t *testing.T, flowg *httptest.Server, provider string) url.Values {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("failed to create cookie jar: %v", err)
+	}
+
+	client := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if strings.HasPrefix(req.URL.Path, "/web/") {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Get(flowg.URL + "/api/v1/auth/" + provider + "/login")
+	if err != nil {
+		t.Fatalf("failed to sign in: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the identity provider form, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	form := url.Values{}
+	for _, match := range samlFormInput.FindAllStringSubmatch(string(body), -1) {
+		form.Set(match[1], html.UnescapeString(match[2]))
+	}
+
+	resp, err = client.PostForm(flowg.URL+"/api/v1/auth/"+provider+"/acs", form)
+	if err != nil {
+		t.Fatalf("failed to post the assertion: %v", err)
+	}
+	defer resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound || location.Path != "/web/login" {
+		t.Fatalf("expected a redirection to the web UI, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	fragment, err := url.ParseQuery(location.Fragment)
+	if err != nil {
+		t.Fatalf("invalid fragment %q: %v", location.Fragment, err)
+	}
+
+	return fragment
+}
+
+func TestSamlSignIn(t *testing.T) {
+	idp := newMockSamlIdP(t)
+	_, otherCert := newSamlKeyPair(t)
+
+	mockAuthStorage := storageMocks.NewMockAuthStorage().(*storageMocks.MockAuthStorage)
+	mockConfigStorage := storageMocks.NewMockConfigStorage().(*storageMocks.MockConfigStorage)
+	mockAuditRecorder := auditMocks.NewMockRecorder().(*auditMocks.MockRecorder)
+
+	mockAuthStorage.On("FetchAuthProvider", mock.Anything, "corp").
+		Return(
+			&models.AuthProvider{
+				Name:        "corp",
+				DisplayName: "Corp",
+				Config: models.AuthProviderConfig{
+					Saml: &models.AuthProviderSaml{
+						Type:           "saml",
+						IdpMetadataURL: idp.URL + "/metadata",
+						IdpCertificate: string(pem.EncodeToMemory(&pem.Block{
+							Type:  "CERTIFICATE",
+							Bytes: idp.idp.Certificate.Raw,
+						})),
+						UsernameAttribute: "uid",
+						GroupsAttribute:   "eduPersonAffiliation",
+					},
+				},
+			},
+			nil,
+		)
+	mockAuthStorage.On("FetchAuthProvider", mock.Anything, "pinned").
+		Return(
+			&models.AuthProvider{
+				Name:        "pinned",
+				DisplayName: "Pinned",
+				Config: models.AuthProviderConfig{
+					Saml: &models.AuthProviderSaml{
+						Type:           "saml",
+						IdpMetadataURL: idp.URL + "/metadata",
+						IdpCertificate: string(pem.EncodeToMemory(&pem.Block{
+							Type:  "CERTIFICATE",
+							Bytes: otherCert.Raw,
+						})),
+					},
+				},
+			},
+			nil,
+		)
+	mockAuthStorage.On("FetchAuthProvider", mock.Anything, "unpinned").
+		Return(
+			&models.AuthProvider{
+				Name:        "unpinned",
+				DisplayName: "Unpinned",
+				Config: models.AuthProviderConfig{
+					Saml: &models.AuthProviderSaml{
+						Type:           "saml",
+						IdpMetadataURL: idp.URL + "/metadata",
+					},
+				},
+			},
+			nil,
+		)
+	mockAuthStorage.On("ListRoles", mock.Anything).
+		Return([]models.Role{{Name: "admin"}, {Name: "editor"}, {Name: "viewer"}}, nil)
+
+	flowg := httptest.NewServer(sso.NewHandler(sso.Deps{
+		AuthStorage:   mockAuthStorage,
+		ConfigStorage: mockConfigStorage,
+		AuditRecorder: mockAuditRecorder,
+	}))
+	defer flowg.Close()
+
+	t.Run("serves the service provider metadata", func(t *testing.T) {
+		resp, err := http.Get(flowg.URL + "/api/v1/auth/corp/metadata")
+		if err != nil {
+			t.Fatalf("failed to fetch metadata: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read metadata: %v", err)
+		}
+
+		metadata, err := samlsp.ParseMetadata(body)
+		if err != nil {
+			t.Fatalf("invalid metadata: %v", err)
+		}
+
+		if metadata.EntityID != flowg.URL+"/api/v1/auth/corp/metadata" {
+			t.Fatalf("unexpected entity ID %q", metadata.EntityID)
+		}
+
+		services := metadata.SPSSODescriptors[0].AssertionConsumerServices
+		if len(services) != 1 || services[0].Location != flowg.URL+"/api/v1/auth/corp/acs" {
+			t.Fatalf("unexpected assertion consumer services %v", services)
+		}
+	})
+
+	t.Run("provisions a user with the roles named by its groups", func(t *testing.T) {
+		idp.username = "alice"
+		idp.groups = []string{"engineering", "editor"}
+
+		mockAuthStorage.On("FetchLinkedUser", mock.Anything, "corp", "id-alice").
+			Return("", nil).
+			Once()
+		mockAuthStorage.On("CreateLinkedUser", mock.Anything, "corp", "id-alice", models.User{Name: "alice", Roles: []string{"editor"}}, mock.Anything).
+			Return(true, nil).
+			Once()
+		mockAuditRecorder.On("Record", mock.Anything, mock.MatchedBy(func(event models.AuditEvent) bool {
+			return event.Operation == "sso_create_user" &&
+				event.Actor == "alice" &&
+				event.Target == "user:alice" &&
+				event.SourceIP == "127.0.0.1"
+		})).
+			Return(nil).
+			Once()
+
+		fragment := samlSignIn(t, flowg, "corp")
+
+		username, err := auth.VerifyJWT(fragment.Get("token"))
+		if err != nil {
+			t.Fatalf("expected a session token, got %v: %v", fragment, err)
+		}
+		if username != "alice" {
+			t.Fatalf("expected a session token for alice, got %q", username)
+		}
+	})
+
+	t.Run("rejects assertions not signed with the pinned certificate", func(t *testing.T) {
+		idp.username = "mallory"
+		idp.groups = []string{"admin"}
+
+		fragment := samlSignIn(t, flowg, "pinned")
+		if fragment.Get("token") != "" || fragment.Get("error") == "" {
+			t.Fatalf("expected the sign-in to fail, got %v", fragment)
+		}
+	})
+
+	t.Run("rejects metadata fetched over HTTP without pinned certificate", func(t *testing.T) {
+		client := &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		resp, err := client.Get(flowg.URL + "/api/v1/auth/unpinned/login")
+		if err != nil {
+			t.Fatalf("failed to sign in: %v", err)
+		}
+		defer resp.Body.Close()
+
+		location := resp.Header.Get("Location")
+		if !strings.HasPrefix(location, "/web/login#error=") {
+			t.Fatalf("expected the sign-in to fail, got %d %q", resp.StatusCode, location)
+		}
+	})
+
+	t.Run("rejects transient NameIDs", func(t *testing.T) {
+		idp.username = "alice"
+		idp.groups = []string{"editor"}
+		idp.nameIDFormat = string(saml.TransientNameIDFormat)
+		defer func() { idp.nameIDFormat = string(saml.PersistentNameIDFormat) }()
+
+		fragment := samlSignIn(t, flowg, "corp")
+		if fragment.Get("token") != "" || fragment.Get("error") == "" {
+			t.Fatalf("expected the sign-in to fail, got %v", fragment)
+		}
+	})
+
+	t.Run("rejects an assertion without session", func(t *testing.T) {
+		client := &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		resp, err := client.PostForm(
+			flowg.URL+"/api/v1/auth/corp/acs",
+			url.Values{"SAMLResponse": {"forged"}, "RelayState": {"forged"}},
+		)
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		location := resp.Header.Get("Location")
+		if !strings.HasPrefix(location, "/web/login#error=") {
+			t.Fatalf("expected the assertion to be rejected, got %q", location)
+		}
+	})
+
+	mockAuthStorage.AssertExpectations(t)
+	mockConfigStorage.AssertExpectations(t)
+	mockAuditRecorder.AssertExpectations(t)
+}
@@ -0,0 +1,112 @@
+package sso
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"link-society.com/flowg/api/auth"
+)
+
+// SESSION_COOKIE is the cookie carrying the state of a sign-in flow, from the
+// login endpoint to the callback endpoint or assertion consumer service.
+const SESSION_COOKIE = "flowg_sso_session"
+
+// SESSION_TTL bounds the time the user has to sign in with the provider.
+const SESSION_TTL = 10 * time.Minute
+
+// session is the state of a sign-in flow, checked back on the callback. The
+// nonce and verifier are only used by OpenID Connect, and the request ID by
+// SAML.
+type session struct {
+	Provider  string `json:"provider"`
+	State     string `json:"state"`
+	Nonce     string `json:"nonce,omitempty"`
+	Verifier  string `json:"verifier,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+
+	jwt.RegisteredClaims
+}
+
+// sessionKey derives the key signing the session cookies from the one signing
+// the session tokens, so that neither can be passed off as the other.
+func sessionKey() []byte {
+	mac := hmac.New(sha256.New, auth.JWT_SIGNING_KEY)
+	mac.Write([]byte("flowg-sso-session"))
+	return mac.Sum(nil)
+}
+
+// setSession stores the state of a sign-in flow in a signed, short-lived
+// cookie, scoped to the sign-in endpoints. The SameSite mode must let the
+// cookie through the way the provider sends the user back.
+func setSession(w http.ResponseWriter, r *http.Request, deps Deps, s session, sameSite http.SameSite) error {
+	s.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(SESSION_TTL)),
+	}
+
+	value, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &s).SignedString(sessionKey())
+	if err != nil {
+		return fmt.Errorf("failed to sign session: %w", err)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SESSION_COOKIE,
+		Value:    value,
+		Path:     mountPath(r) + "/api/v1/auth/",
+		MaxAge:   int(SESSION_TTL.Seconds()),
+		HttpOnly: true,
+		Secure:   isSecure(deps, r),
+		SameSite: sameSite,
+	})
+
+	return nil
+}
+
+// popSession reads the state of the sign-in flow of the given provider, and
+// clears its cookie so it cannot be replayed.
+func popSession(w http.ResponseWriter, r *http.Request, provider string) (*session, error) {
+	cookie, err := r.Cookie(SESSION_COOKIE)
+	if err != nil {
+		return nil, fmt.Errorf("missing session: %w", err)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SESSION_COOKIE,
+		Path:     mountPath(r) + "/api/v1/auth/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	var s session
+	_, err = jwt.ParseWithClaims(
+		cookie.Value,
+		&s,
+		func(t *jwt.Token) (any, error) {
+			return sessionKey(), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session: %w", err)
+	}
+
+	if s.Provider != provider {
+		return nil, fmt.Errorf("session was started for provider %q", s.Provider)
+	}
+
+	return &s, nil
+}
+
+// isSecure tells whether the browser reaches FlowG over HTTPS: directly, or
+// through a reverse proxy if the configured external URL says so.
+func isSecure(deps Deps, r *http.Request) bool {
+	return r.TLS != nil || strings.HasPrefix(deps.Options.ExternalURL, "https://")
+}
@@ -0,0 +1,151 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/secret"
+)
+
+// errUserNotLinked is returned by [provisionUser] when the name the auth
+// provider gives to a new identity is already used by another FlowG user.
+var errUserNotLinked = errors.New("user exists and is not linked to this identity")
+
+// provisionUser matches the identity signing in with an auth provider, keyed
+// on the provider and the subject it identifies the user with, to the FlowG
+// user created for it, and returns the name of that user.
+//
+// The first sign-in of an identity creates a user named after it, linked to
+// the identity. An existing user is never linked to an identity, even if the
+// names match: the sign-in is refused with [errUserNotLinked] instead, so that
+// an identity can only ever sign in as a user it created.
+//
+// The groups of the user that name FlowG roles become its roles, on every
+// sign-in. Without any of them, the user is given the system configuration's
+// default roles. Provisioned users get a random password, so they can only
+// sign in through the provider. Creating the user, and changing its roles, is
+// recorded in the audit log.
+func provisionUser(
+	ctx context.Context,
+	deps Deps,
+	provider string,
+	subject string,
+	username string,
+	groups []string,
+) (string, error) {
+	roles, err := deps.AuthStorage.ListRoles(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	matchedRoles := []string{}
+	for _, group := range groups {
+		isRole := slices.ContainsFunc(roles, func(role models.Role) bool {
+			return role.Name == group
+		})
+
+		if isRole && !slices.Contains(matchedRoles, group) {
+			matchedRoles = append(matchedRoles, group)
+		}
+	}
+
+	if len(matchedRoles) == 0 {
+		systemConfig, err := deps.ConfigStorage.ReadSystemConfig(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to read system configuration: %w", err)
+		}
+
+		matchedRoles = systemConfig.DefaultRoles
+	}
+
+	linkedUsername, err := deps.AuthStorage.FetchLinkedUser(ctx, provider, subject)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch user linked to %q: %w", subject, err)
+	}
+
+	if linkedUsername == "" {
+		password, err := secret.NewSecret("sso", 32)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+
+		user := models.User{Name: username, Roles: matchedRoles}
+
+		created, err := deps.AuthStorage.CreateLinkedUser(ctx, provider, subject, user, password)
+		if err != nil {
+			return "", fmt.Errorf("failed to create user %q: %w", username, err)
+		}
+
+		if !created {
+			return "", fmt.Errorf("%w: %q", errUserNotLinked, username)
+		}
+
+		recordUserChange(ctx, deps, "sso_create_user", nil, &user)
+
+		return username, nil
+	}
+
+	user, err := deps.AuthStorage.FetchUser(ctx, linkedUsername)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch user %q: %w", linkedUsername, err)
+	}
+
+	if user == nil {
+		return "", fmt.Errorf("user %q linked to %q not found", linkedUsername, subject)
+	}
+
+	currentRoles := slices.Sorted(slices.Values(user.Roles))
+	if slices.Equal(currentRoles, slices.Sorted(slices.Values(matchedRoles))) {
+		return user.Name, nil
+	}
+
+	before := *user
+	user.Roles = matchedRoles
+	if err := deps.AuthStorage.PatchUserRoles(ctx, *user); err != nil {
+		return "", fmt.Errorf("failed to update roles of user %q: %w", user.Name, err)
+	}
+
+	recordUserChange(ctx, deps, "sso_patch_user_roles", &before, user)
+
+	return user.Name, nil
+}
+
+// recordUserChange records in the audit log a change made to the user signing
+// in, on their behalf, from the IP address bound to ctx.
+//
+// The change being made already, failing to record it is logged rather than
+// failing the sign-in.
+func recordUserChange(
+	ctx context.Context,
+	deps Deps,
+	operation string,
+	before *models.User,
+	after *models.User,
+) {
+	event := models.AuditEvent{
+		Timestamp: time.Now(),
+		Actor:     after.Name,
+		Operation: operation,
+		Target:    "user:" + after.Name,
+		SourceIP:  auth.GetContextSourceIP(ctx),
+		Before:    before,
+		After:     after,
+	}
+
+	if err := deps.AuditRecorder.Record(ctx, event); err != nil {
+		slog.ErrorContext(
+			ctx,
+			"Failed to record audit event",
+			slog.String("channel", "api.sso"),
+			slog.String("operation", operation),
+			slog.String("target", event.Target),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"bytes"
 	"encoding/json"
@@ -28,11 +29,16 @@ func NewAclRoleGrantCommand() *cobra.Command {
 		Use:   "grant",
 		Short: "Grant a permission to a role",
 		Run: func(cmd *cobra.Command, args []string) {
-			newScope, err := models.ParseScope(opts.permission)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "ERROR: Invalid permission: %v\n", err)
-				ExitCode = 1
-				return
+			// A permission is either a plain scope, or a scope restricted to the
+			// resources matching a pattern.
+			_, err := models.ParseScope(opts.permission)
+			isResourceScope := err != nil
+			if isResourceScope {
+				if _, err := models.ParseResourceScope(opts.permission); err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: Invalid permission: %v\n", err)
+					ExitCode = 1
+					return
+				}
 			}
 
 			client := cmd.Context().Value(ApiClient).(*utils.Client)
@@ -66,24 +72,30 @@ func NewAclRoleGrantCommand() *cobra.Command {
 				return
 			}
 
-			found := false
-			for _, scope := range data.Role.Scopes {
-				if scope == newScope {
-					found = true
-					break
-				}
+			scopes := make([]string, len(data.Role.Scopes))
+			for i, scope := range data.Role.Scopes {
+				scopes[i] = string(scope)
 			}
-			if !found {
-				data.Role.Scopes = append(data.Role.Scopes, newScope)
-				scopes := make([]string, len(data.Role.Scopes))
-				for i, scope := range data.Role.Scopes {
-					scopes[i] = string(scope)
-				}
+
+			resourceScopes := make([]string, len(data.Role.ResourceScopes))
+			for i, resourceScope := range data.Role.ResourceScopes {
+				resourceScopes[i] = string(resourceScope)
+			}
+
+			permissions := &scopes
+			if isResourceScope {
+				permissions = &resourceScopes
+			}
+
+			if !slices.Contains(*permissions, opts.permission) {
+				*permissions = append(*permissions, opts.permission)
 
 				body := struct {
-					Scopes []string `json:"scopes"`
+					Scopes         []string `json:"scopes"`
+					ResourceScopes []string `json:"resource_scopes"`
 				}{
-					Scopes: scopes,
+					Scopes:         scopes,
+					ResourceScopes: resourceScopes,
 				}
 
 				payload, err := json.Marshal(body)
@@ -129,7 +141,7 @@ func NewAclRoleGrantCommand() *cobra.Command {
 		&opts.permission,
 		"permission",
 		"",
-		"Permission to grant to the role",
+		"Permission to grant to the role, optionally restricted to some resources (e.g. read_streams:payments-*)",
 	)
 	cmd.MarkFlagRequired("permission")
 
@@ -59,6 +59,9 @@ func NewAclRoleListCommand() *cobra.Command {
 				for i, scope := range role.Scopes {
 					scopes[i] = string(scope)
 				}
+				for _, resourceScope := range role.ResourceScopes {
+					scopes = append(scopes, string(resourceScope))
+				}
 				fmt.Fprintf(w, "%s\t%s\n", role.Name, strings.Join(scopes, ","))
 			}
 
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"bytes"
 	"encoding/json"
@@ -28,11 +29,16 @@ func NewAclRoleRevokeCommand() *cobra.Command {
 		Use:   "revoke",
 		Short: "Revoke a permission from a role",
 		Run: func(cmd *cobra.Command, args []string) {
-			oldScope, err := models.ParseScope(opts.permission)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "ERROR: Invalid permission: %v\n", err)
-				ExitCode = 1
-				return
+			// A permission is either a plain scope, or a scope restricted to the
+			// resources matching a pattern.
+			_, err := models.ParseScope(opts.permission)
+			isResourceScope := err != nil
+			if isResourceScope {
+				if _, err := models.ParseResourceScope(opts.permission); err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: Invalid permission: %v\n", err)
+					ExitCode = 1
+					return
+				}
 			}
 
 			client := cmd.Context().Value(ApiClient).(*utils.Client)
@@ -66,28 +72,32 @@ func NewAclRoleRevokeCommand() *cobra.Command {
 				return
 			}
 
-			found := false
+			scopes := make([]string, len(data.Role.Scopes))
 			for i, scope := range data.Role.Scopes {
-				if scope == oldScope {
-					data.Role.Scopes = append(
-						data.Role.Scopes[:i],
-						data.Role.Scopes[i+1:]...,
-					)
-					found = true
-					break
-				}
+				scopes[i] = string(scope)
 			}
 
-			if found {
-				scopes := make([]string, len(data.Role.Scopes))
-				for i, scope := range data.Role.Scopes {
-					scopes[i] = string(scope)
-				}
+			resourceScopes := make([]string, len(data.Role.ResourceScopes))
+			for i, resourceScope := range data.Role.ResourceScopes {
+				resourceScopes[i] = string(resourceScope)
+			}
+
+			permissions := &scopes
+			if isResourceScope {
+				permissions = &resourceScopes
+			}
+
+			if slices.Contains(*permissions, opts.permission) {
+				*permissions = slices.DeleteFunc(*permissions, func(permission string) bool {
+					return permission == opts.permission
+				})
 
 				body := struct {
-					Scopes []string `json:"scopes"`
+					Scopes         []string `json:"scopes"`
+					ResourceScopes []string `json:"resource_scopes"`
 				}{
-					Scopes: scopes,
+					Scopes:         scopes,
+					ResourceScopes: resourceScopes,
 				}
 
 				payload, err := json.Marshal(body)
@@ -133,7 +143,7 @@ func NewAclRoleRevokeCommand() *cobra.Command {
 		&opts.permission,
 		"permission",
 		"",
-		"Permission to revoke from the role",
+		"Permission to revoke from the role, optionally restricted to some resources (e.g. read_streams:payments-*)",
 	)
 	cmd.MarkFlagRequired("permission")
 
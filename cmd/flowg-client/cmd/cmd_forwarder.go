@@ -14,6 +14,7 @@ func NewForwarderCommand() *cobra.Command {
 		NewForwarderExportCommand(),
 		NewForwarderImportCommand(),
 		NewForwarderDeleteCommand(),
+		NewForwarderDeadLetterCommand(),
 	)
 
 	return cmd
@@ -0,0 +1,19 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// NewForwarderDeadLetterCommand builds the "dlq" command group, which gathers the dead-letter queue subcommands.
+func NewForwarderDeadLetterCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dlq",
+		Short: "Access a forwarder's dead-letter queue",
+	}
+
+	cmd.AddCommand(
+		NewForwarderDeadLetterListCommand(),
+		NewForwarderDeadLetterReplayCommand(),
+		NewForwarderDeadLetterPurgeCommand(),
+	)
+
+	return cmd
+}
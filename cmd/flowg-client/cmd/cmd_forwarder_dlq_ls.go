@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"encoding/json"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"link-society.com/flowg/api/schemas"
+	"link-society.com/flowg/cmd/flowg-client/utils"
+)
+
+// NewForwarderDeadLetterListCommand builds the "ls" command, which prints the records of a forwarder's dead-letter queue, one JSON object per line.
+func NewForwarderDeadLetterListCommand() *cobra.Command {
+	type options struct {
+		name string
+	}
+
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List the records a forwarder failed to deliver",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := cmd.Context().Value(ApiClient).(*utils.Client)
+			url := fmt.Sprintf("/api/v1/forwarders/%s/dead-letters", opts.name)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not prepare request: %v\n", err)
+				ExitCode = 1
+				return
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not send request: %v\n", err)
+				ExitCode = 1
+				return
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				fmt.Fprintf(os.Stderr, "ERROR: Received non-200 response: %s\n", resp.Status)
+				ExitCode = 1
+				return
+			}
+
+			var data schemas.ListDeadLettersResponse
+			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not decode response: %v\n", err)
+				ExitCode = 1
+				return
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			for _, letter := range data.DeadLetters {
+				if err := encoder.Encode(letter); err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: Could not print dead letter: %v\n", err)
+					ExitCode = 1
+					return
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.name,
+		"name",
+		"default",
+		"Name of the forwarder",
+	)
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"link-society.com/flowg/cmd/flowg-client/utils"
+)
+
+// NewForwarderDeadLetterPurgeCommand builds the "purge" command, which empties a forwarder's dead-letter queue.
+func NewForwarderDeadLetterPurgeCommand() *cobra.Command {
+	type options struct {
+		name string
+	}
+
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Discard the records a forwarder failed to deliver",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := cmd.Context().Value(ApiClient).(*utils.Client)
+			url := fmt.Sprintf("/api/v1/forwarders/%s/dead-letters", opts.name)
+			req, err := http.NewRequest(http.MethodDelete, url, nil)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not prepare request: %v\n", err)
+				ExitCode = 1
+				return
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not send request: %v\n", err)
+				ExitCode = 1
+				return
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				fmt.Fprintf(os.Stderr, "ERROR: Received non-200 response: %s\n", resp.Status)
+				ExitCode = 1
+				return
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.name,
+		"name",
+		"default",
+		"Name of the forwarder",
+	)
+
+	return cmd
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"encoding/json"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"link-society.com/flowg/api/schemas"
+	"link-society.com/flowg/cmd/flowg-client/utils"
+)
+
+// NewForwarderDeadLetterReplayCommand builds the "replay" command, which delivers again the records of a forwarder's dead-letter queue.
+func NewForwarderDeadLetterReplayCommand() *cobra.Command {
+	type options struct {
+		name     string
+		pageSize int
+	}
+
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Deliver again the records a forwarder failed to deliver",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := cmd.Context().Value(ApiClient).(*utils.Client)
+			url := fmt.Sprintf("/api/v1/forwarders/%s/dead-letters/replay", opts.name)
+
+			var (
+				cursor   string
+				replayed int
+				failed   int
+			)
+
+			// The queue is replayed one page per request, so that no request has
+			// to deliver the whole queue.
+			for {
+				req, err := http.NewRequest(http.MethodPost, url, nil)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: Could not prepare request: %v\n", err)
+					ExitCode = 1
+					return
+				}
+
+				queryset := req.URL.Query()
+
+				if opts.pageSize > 0 {
+					queryset.Set("limit", strconv.Itoa(opts.pageSize))
+				}
+
+				if cursor != "" {
+					queryset.Set("cursor", cursor)
+				}
+
+				req.URL.RawQuery = queryset.Encode()
+
+				data, ok := replayDeadLetterPage(client, req)
+				if !ok {
+					ExitCode = 1
+					return
+				}
+
+				replayed += data.Replayed
+				failed += data.Failed
+
+				if data.NextCursor == nil {
+					break
+				}
+
+				cursor = *data.NextCursor
+			}
+
+			fmt.Printf("Replayed: %d\n", replayed)
+			fmt.Printf("Failed: %d\n", failed)
+
+			if failed > 0 {
+				ExitCode = 1
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.name,
+		"name",
+		"default",
+		"Name of the forwarder",
+	)
+
+	cmd.Flags().IntVar(
+		&opts.pageSize,
+		"page-size",
+		100,
+		"Number of records to replay per request",
+	)
+
+	return cmd
+}
+
+// replayDeadLetterPage sends one page request of the "replay" command and
+// decodes the response, reporting failures on stderr.
+func replayDeadLetterPage(client *utils.Client, req *http.Request) (schemas.ReplayDeadLettersResponse, bool) {
+	var data schemas.ReplayDeadLettersResponse
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Could not send request: %v\n", err)
+		return data, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "ERROR: Received non-200 response: %s\n", resp.Status)
+		return data, false
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Could not decode response: %v\n", err)
+		return data, false
+	}
+
+	return data, true
+}
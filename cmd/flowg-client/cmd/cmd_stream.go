@@ -15,6 +15,7 @@ func NewStreamCommand() *cobra.Command {
 		NewStreamListCommand(),
 		NewStreamWatchCommand(),
 		NewStreamHistoryCommand(),
+		NewStreamExportCommand(),
 		NewStreamTailCommand(),
 		NewStreamSetCommand(),
 		NewStreamIndexCommand(),
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"link-society.com/flowg/cmd/flowg-client/utils"
+)
+
+// NewStreamExportCommand builds the "export" command, which downloads logs from a time window to a file.
+func NewStreamExportCommand() *cobra.Command {
+	type options struct {
+		name     string
+		filter   string
+		from     string
+		to       string
+		format   string
+		fields   []string
+		output   string
+		indexing utils.IndexMap
+	}
+
+	opts := &options{
+		indexing: make(utils.IndexMap),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export logs from a time window to a file",
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.format != "ndjson" && opts.format != "csv" {
+				fmt.Fprintf(os.Stderr, "ERROR: Unsupported format %q, expected ndjson or csv\n", opts.format)
+				ExitCode = 1
+				return
+			}
+
+			url := fmt.Sprintf("/api/v1/streams/%s/export", opts.name)
+			client := cmd.Context().Value(ApiClient).(*utils.Client)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not prepare request: %v\n", err)
+				ExitCode = 1
+				return
+			}
+
+			queryset := req.URL.Query()
+
+			if opts.filter != "" {
+				queryset.Set("filter", opts.filter)
+			}
+
+			if len(opts.indexing) > 0 {
+				payload, err := json.Marshal(opts.indexing)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: Could not encode indexing parameters: %v\n", err)
+					ExitCode = 1
+					return
+				}
+
+				queryset.Set("indexing", string(payload))
+			}
+
+			if len(opts.fields) > 0 {
+				queryset.Set("fields", strings.Join(opts.fields, ","))
+			}
+
+			queryset.Set("from", opts.from)
+			queryset.Set("to", opts.to)
+			queryset.Set("format", opts.format)
+
+			req.URL.RawQuery = queryset.Encode()
+
+			resp, err := client.Do(req)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not send request: %v\n", err)
+				ExitCode = 1
+				return
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				fmt.Fprintf(os.Stderr, "ERROR: Unexpected status code: %d\n", resp.StatusCode)
+				io.Copy(os.Stderr, resp.Body)
+				ExitCode = 1
+				return
+			}
+
+			output := opts.output
+			if output == "" {
+				output = fmt.Sprintf("%s.%s", opts.name, opts.format)
+			}
+
+			outFile, err := os.Create(output)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not create output file: %v\n", err)
+				ExitCode = 1
+				return
+			}
+			defer outFile.Close()
+
+			if _, err := io.Copy(outFile, resp.Body); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not write output file: %v\n", err)
+				ExitCode = 1
+				return
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&opts.name,
+		"name",
+		"default",
+		"Name of the stream",
+	)
+
+	cmd.Flags().StringVar(
+		&opts.filter,
+		"filter",
+		"",
+		"Filter logs",
+	)
+
+	now := time.Now()
+
+	cmd.Flags().StringVar(
+		&opts.from,
+		"from",
+		now.Add(-15*time.Minute).Format(time.RFC3339),
+		"Export logs from a specific time",
+	)
+
+	cmd.Flags().StringVar(
+		&opts.to,
+		"to",
+		now.Format(time.RFC3339),
+		"Export logs until a specific time",
+	)
+
+	cmd.Flags().Var(
+		&opts.indexing,
+		"index",
+		"Indexing key-value pairs to filter logs (can be specified multiple times)",
+	)
+
+	cmd.Flags().StringVar(
+		&opts.format,
+		"format",
+		"ndjson",
+		"Output format (ndjson or csv)",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&opts.fields,
+		"field",
+		nil,
+		"Field to export, in order (can be specified multiple times, defaults to all fields)",
+	)
+
+	cmd.Flags().StringVar(
+		&opts.output,
+		"output",
+		"",
+		"Path of the output file (defaults to <stream>.<format>)",
+	)
+
+	return cmd
+}
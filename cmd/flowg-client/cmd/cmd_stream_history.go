@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"encoding/json"
@@ -23,6 +24,7 @@ func NewStreamHistoryCommand() *cobra.Command {
 		from     string
 		to       string
 		indexing utils.IndexMap
+		pageSize int
 	}
 
 	opts := &options{
@@ -35,66 +37,69 @@ func NewStreamHistoryCommand() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			url := fmt.Sprintf("/api/v1/streams/%s/logs", opts.name)
 			client := cmd.Context().Value(ApiClient).(*utils.Client)
-			req, err := http.NewRequest(http.MethodGet, url, nil)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "ERROR: Could not prepare request: %v\n", err)
-				ExitCode = 1
-				return
-			}
-
-			queryset := req.URL.Query()
+			printer := utils.NewPrinter()
 
-			if opts.filter != "" {
-				queryset.Set("filter", opts.filter)
-			}
+			cursor := ""
 
-			if len(opts.indexing) > 0 {
-				payload, err := json.Marshal(opts.indexing)
+			// Pages are fetched and printed one at a time, so neither the server
+			// nor the client ever holds the whole time window in memory.
+			for {
+				req, err := http.NewRequest(http.MethodGet, url, nil)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "ERROR: Could not encode indexing parameters: %v\n", err)
+					fmt.Fprintf(os.Stderr, "ERROR: Could not prepare request: %v\n", err)
 					ExitCode = 1
 					return
 				}
 
-				queryset.Set("indexing", string(payload))
-			}
+				queryset := req.URL.Query()
 
-			queryset.Set("from", opts.from)
-			queryset.Set("to", opts.to)
+				if opts.filter != "" {
+					queryset.Set("filter", opts.filter)
+				}
 
-			req.URL.RawQuery = queryset.Encode()
+				if len(opts.indexing) > 0 {
+					payload, err := json.Marshal(opts.indexing)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "ERROR: Could not encode indexing parameters: %v\n", err)
+						ExitCode = 1
+						return
+					}
 
-			resp, err := client.Do(req)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "ERROR: Could not send request: %v\n", err)
-				ExitCode = 1
-				return
-			}
-			defer resp.Body.Close()
+					queryset.Set("indexing", string(payload))
+				}
 
-			if resp.StatusCode != http.StatusOK {
-				fmt.Fprintf(os.Stderr, "ERROR: Unexpected status code: %d\n", resp.StatusCode)
-				io.Copy(os.Stderr, resp.Body)
-				ExitCode = 1
-				return
-			}
+				queryset.Set("from", opts.from)
+				queryset.Set("to", opts.to)
 
-			printer := utils.NewPrinter()
+				if opts.pageSize > 0 {
+					queryset.Set("limit", strconv.Itoa(opts.pageSize))
+				}
 
-			var data schemas.QueryStreamResponse
+				if cursor != "" {
+					queryset.Set("cursor", cursor)
+				}
 
-			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				fmt.Fprintf(os.Stderr, "ERROR: Could not decode response: %v\n", err)
-				ExitCode = 1
-				return
-			}
+				req.URL.RawQuery = queryset.Encode()
 
-			for _, log := range data.Records {
-				if err := printer.Print(log); err != nil {
-					fmt.Fprintf(os.Stderr, "ERROR: Could not print log: %v\n", err)
+				data, ok := fetchHistoryPage(client, req)
+				if !ok {
 					ExitCode = 1
 					return
 				}
+
+				for _, log := range data.Records {
+					if err := printer.Print(log); err != nil {
+						fmt.Fprintf(os.Stderr, "ERROR: Could not print log: %v\n", err)
+						ExitCode = 1
+						return
+					}
+				}
+
+				if data.NextCursor == nil {
+					return
+				}
+
+				cursor = *data.NextCursor
 			}
 		},
 	}
@@ -135,5 +140,38 @@ func NewStreamHistoryCommand() *cobra.Command {
 		"Indexing key-value pairs to filter logs (can be specified multiple times)",
 	)
 
+	cmd.Flags().IntVar(
+		&opts.pageSize,
+		"page-size",
+		1000,
+		"Number of logs to fetch per request (0 to fetch everything at once)",
+	)
+
 	return cmd
 }
+
+// fetchHistoryPage sends one page request of the "history" command and decodes
+// the response, reporting failures on stderr.
+func fetchHistoryPage(client *utils.Client, req *http.Request) (schemas.QueryStreamResponse, bool) {
+	var data schemas.QueryStreamResponse
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Could not send request: %v\n", err)
+		return data, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "ERROR: Unexpected status code: %d\n", resp.StatusCode)
+		io.Copy(os.Stderr, resp.Body)
+		return data, false
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Could not decode response: %v\n", err)
+		return data, false
+	}
+
+	return data, true
+}
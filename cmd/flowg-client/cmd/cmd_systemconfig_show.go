@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"encoding/json"
 	"net/http"
@@ -59,6 +61,54 @@ func NewSystemConfigShowCommand() *cobra.Command {
 					fmt.Printf("    - %s\n", origin)
 				}
 			}
+
+			if len(data.Configuration.SyslogAllowedSubjects) == 0 {
+				fmt.Println("  Syslog Allowed Subjects: ALL")
+			} else {
+				fmt.Println("  Syslog Allowed Subjects:")
+				for _, subject := range data.Configuration.SyslogAllowedSubjects {
+					fmt.Printf("    - %s\n", subject)
+				}
+			}
+
+			if len(data.Configuration.SyslogRoutes) == 0 {
+				fmt.Println("  Syslog Routes: NONE")
+			} else {
+				fmt.Println("  Syslog Routes:")
+				for _, route := range data.Configuration.SyslogRoutes {
+					fmt.Printf("    - Pipeline: %s\n", route.Pipeline)
+					if len(route.Listeners) > 0 {
+						fmt.Printf("      Listeners: %s\n", strings.Join(route.Listeners, ", "))
+					}
+					if len(route.Origins) > 0 {
+						fmt.Printf("      Origins: %s\n", strings.Join(route.Origins, ", "))
+					}
+					if len(route.AppNames) > 0 {
+						fmt.Printf("      Apps: %s\n", strings.Join(route.AppNames, ", "))
+					}
+				}
+			}
+
+			fmt.Println("  Timestamp Policy:")
+
+			policy := data.Configuration.TimestampPolicy
+			if policy.MaxPast == 0 {
+				fmt.Println("    Max Past: UNLIMITED")
+			} else {
+				fmt.Printf("    Max Past: %s\n", time.Duration(policy.MaxPast)*time.Second)
+			}
+
+			if policy.MaxFuture == 0 {
+				fmt.Println("    Max Future: UNLIMITED")
+			} else {
+				fmt.Printf("    Max Future: %s\n", time.Duration(policy.MaxFuture)*time.Second)
+			}
+
+			if data.Configuration.AuditForwarder == "" {
+				fmt.Println("  Audit Forwarder: NONE")
+			} else {
+				fmt.Printf("  Audit Forwarder: %s\n", data.Configuration.AuditForwarder)
+			}
 		},
 	}
 }
@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"bytes"
 	"encoding/json"
@@ -12,14 +14,22 @@ import (
 
 	"link-society.com/flowg/api/schemas"
 	"link-society.com/flowg/cmd/flowg-client/utils"
+	"link-society.com/flowg/internal/models"
 )
 
 // NewSystemConfigUpdateCommand builds the "update" command, which updates the system configuration.
 func NewSystemConfigUpdateCommand() *cobra.Command {
 	type options struct {
-		SyslogAllowedOrigins []string
-		SyslogAllowAll       bool
-		DefaultRoles         []string
+		SyslogAllowedOrigins   []string
+		SyslogAllowAll         bool
+		SyslogAllowedSubjects  []string
+		SyslogAllowAllSubjects bool
+		SyslogRoutes           []string
+		SyslogClearRoutes      bool
+		DefaultRoles           []string
+		TimestampMaxPast       time.Duration
+		TimestampMaxFuture     time.Duration
+		AuditForwarder         string
 	}
 
 	opts := &options{}
@@ -28,6 +38,18 @@ func NewSystemConfigUpdateCommand() *cobra.Command {
 		Use:   "update",
 		Short: "Update system configuration",
 		Run: func(cmd *cobra.Command, args []string) {
+			syslogRoutes := make([]models.SyslogRoute, len(opts.SyslogRoutes))
+			for i, spec := range opts.SyslogRoutes {
+				route, err := parseSyslogRoute(spec)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: Invalid syslog route %q: %v\n", spec, err)
+					ExitCode = 1
+					return
+				}
+
+				syslogRoutes[i] = route
+			}
+
 			client := cmd.Context().Value(ApiClient).(*utils.Client)
 			url := "/api/v1/system-configuration"
 			req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -66,10 +88,38 @@ func NewSystemConfigUpdateCommand() *cobra.Command {
 				data.Configuration.SyslogAllowedOrigins = []string{}
 			}
 
+			if cmd.Flags().Changed("syslog-allowed-subject") {
+				data.Configuration.SyslogAllowedSubjects = opts.SyslogAllowedSubjects
+			}
+
+			if cmd.Flags().Changed("syslog-allow-all-subjects") && opts.SyslogAllowAllSubjects {
+				data.Configuration.SyslogAllowedSubjects = []string{}
+			}
+
+			if cmd.Flags().Changed("syslog-route") {
+				data.Configuration.SyslogRoutes = syslogRoutes
+			}
+
+			if cmd.Flags().Changed("syslog-clear-routes") && opts.SyslogClearRoutes {
+				data.Configuration.SyslogRoutes = []models.SyslogRoute{}
+			}
+
 			if cmd.Flags().Changed("default-role") {
 				data.Configuration.DefaultRoles = opts.DefaultRoles
 			}
 
+			if cmd.Flags().Changed("timestamp-max-past") {
+				data.Configuration.TimestampPolicy.MaxPast = int64(opts.TimestampMaxPast.Seconds())
+			}
+
+			if cmd.Flags().Changed("timestamp-max-future") {
+				data.Configuration.TimestampPolicy.MaxFuture = int64(opts.TimestampMaxFuture.Seconds())
+			}
+
+			if cmd.Flags().Changed("audit-forwarder") {
+				data.Configuration.AuditForwarder = opts.AuditForwarder
+			}
+
 			payload, err := json.Marshal(data.Configuration)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "ERROR: Could not encode request body: %v\n", err)
@@ -114,6 +164,34 @@ func NewSystemConfigUpdateCommand() *cobra.Command {
 		"Allow all origins for syslog (overrides --syslog-allowed-origin)",
 	)
 
+	cmd.Flags().StringArrayVar(
+		&opts.SyslogAllowedSubjects,
+		"syslog-allowed-subject",
+		[]string{},
+		"List of allowed client certificate subjects for syslog, as patterns matching the CN or a SAN (empty list means all subjects are allowed)",
+	)
+
+	cmd.Flags().BoolVar(
+		&opts.SyslogAllowAllSubjects,
+		"syslog-allow-all-subjects",
+		false,
+		"Allow all client certificate subjects for syslog (overrides --syslog-allowed-subject)",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&opts.SyslogRoutes,
+		"syslog-route",
+		[]string{},
+		"Syslog route, as comma-separated pipeline=NAME, listener=NAME, origin=IP|CIDR and app=NAME criteria (replaces all routes)",
+	)
+
+	cmd.Flags().BoolVar(
+		&opts.SyslogClearRoutes,
+		"syslog-clear-routes",
+		false,
+		"Remove all syslog routes (overrides --syslog-route)",
+	)
+
 	cmd.Flags().StringArrayVar(
 		&opts.DefaultRoles,
 		"default-role",
@@ -121,5 +199,59 @@ func NewSystemConfigUpdateCommand() *cobra.Command {
 		"List of default roles assigned to new users",
 	)
 
+	cmd.Flags().DurationVar(
+		&opts.TimestampMaxPast,
+		"timestamp-max-past",
+		0,
+		"Maximum age of a client-supplied timestamp (0 means no limit)",
+	)
+
+	cmd.Flags().DurationVar(
+		&opts.TimestampMaxFuture,
+		"timestamp-max-future",
+		0,
+		"Maximum delay into the future of a client-supplied timestamp (0 means no limit)",
+	)
+
+	cmd.Flags().StringVar(
+		&opts.AuditForwarder,
+		"audit-forwarder",
+		"",
+		"Forwarder to send audit events through (empty means audit events are only stored)",
+	)
+
 	return cmd
 }
+
+// parseSyslogRoute decodes a syslog route given on the command line, such as
+// "pipeline=web,origin=10.0.0.0/8,app=nginx". The listener, origin and app
+// criteria may be repeated.
+func parseSyslogRoute(spec string) (models.SyslogRoute, error) {
+	route := models.SyslogRoute{}
+
+	for _, criterion := range strings.Split(spec, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(criterion), "=")
+		if !found || value == "" {
+			return route, fmt.Errorf("expected key=value, got %q", criterion)
+		}
+
+		switch key {
+		case "pipeline":
+			route.Pipeline = value
+		case "listener":
+			route.Listeners = append(route.Listeners, value)
+		case "origin":
+			route.Origins = append(route.Origins, value)
+		case "app":
+			route.AppNames = append(route.AppNames, value)
+		default:
+			return route, fmt.Errorf("unknown criterion %q", key)
+		}
+	}
+
+	if route.Pipeline == "" {
+		return route, fmt.Errorf("missing pipeline")
+	}
+
+	return route, nil
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"time"
 
 	"encoding/json"
 	"net/http"
@@ -11,17 +13,47 @@ import (
 
 	"link-society.com/flowg/api/schemas"
 	"link-society.com/flowg/cmd/flowg-client/utils"
+	"link-society.com/flowg/internal/models"
 )
 
 // NewTokenCreateCommand builds the "create" command, which creates Personal Access Tokens.
 func NewTokenCreateCommand() *cobra.Command {
-	return &cobra.Command{
+	type options struct {
+		name      string
+		scopes    []string
+		expiresIn time.Duration
+	}
+
+	opts := &options{}
+
+	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create Personal Access Tokens",
 		Run: func(cmd *cobra.Command, args []string) {
+			body := schemas.CreateTokenRequest{
+				Name:   opts.name,
+				Scopes: make([]models.Scope, len(opts.scopes)),
+			}
+
+			for i, scope := range opts.scopes {
+				body.Scopes[i] = models.Scope(scope)
+			}
+
+			if opts.expiresIn > 0 {
+				expiresAt := time.Now().Add(opts.expiresIn).UTC()
+				body.ExpiresAt = &expiresAt
+			}
+
+			payload, err := json.Marshal(body)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not marshal request body: %v\n", err)
+				ExitCode = 1
+				return
+			}
+
 			client := cmd.Context().Value(ApiClient).(*utils.Client)
 			url := "/api/v1/token"
-			req, err := http.NewRequest(http.MethodPost, url, nil)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "ERROR: Could not prepare request: %v\n", err)
 				ExitCode = 1
@@ -53,4 +85,28 @@ func NewTokenCreateCommand() *cobra.Command {
 			fmt.Println("Token:", data.Token)
 		},
 	}
+
+	cmd.Flags().StringVar(
+		&opts.name,
+		"name",
+		"",
+		"Name of the new token",
+	)
+	cmd.MarkFlagRequired("name")
+
+	cmd.Flags().StringArrayVar(
+		&opts.scopes,
+		"scope",
+		[]string{},
+		"Scope to restrict the token to (repeatable, all scopes of the user if unset)",
+	)
+
+	cmd.Flags().DurationVar(
+		&opts.expiresIn,
+		"expires-in",
+		0,
+		"Lifetime of the token (e.g. 720h, never expires if unset)",
+	)
+
+	return cmd
 }
@@ -3,6 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"encoding/json"
 	"net/http"
@@ -49,10 +52,41 @@ func NewTokenListCommand() *cobra.Command {
 				return
 			}
 
-			fmt.Println("TOKEN UUIDS")
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+			fmt.Fprintf(w, "UUID\tNAME\tSCOPES\tEXPIRES\tLAST USED\n")
 
-			for _, name := range data.TokenUUIDs {
-				fmt.Println(name)
+			formatTime := func(t *time.Time) string {
+				if t == nil {
+					return "never"
+				}
+				return t.Local().Format(time.RFC3339)
+			}
+
+			for _, token := range data.Tokens {
+				scopes := "*"
+				if token.IsRestricted() {
+					names := make([]string, len(token.Scopes))
+					for i, scope := range token.Scopes {
+						names[i] = string(scope)
+					}
+					scopes = strings.Join(names, ",")
+				}
+
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\t%s\n",
+					token.UUID,
+					token.Name,
+					scopes,
+					formatTime(token.ExpiresAt),
+					formatTime(token.LastUsedAt),
+				)
+			}
+
+			if err := w.Flush(); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: Could not flush output: %v\n", err)
+				ExitCode = 1
+				return
 			}
 		},
 	}
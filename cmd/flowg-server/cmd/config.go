@@ -4,15 +4,23 @@ import (
 	"errors"
 
 	"fmt"
+	"os"
+	"path"
+	"slices"
 	"strings"
 
 	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/url"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 
 	"link-society.com/flowg/internal/app/server"
+	"link-society.com/flowg/internal/services/syslog"
 )
 
 // Represents the configuration file structure
@@ -38,13 +46,17 @@ type LoggingConfig struct {
 type ServicesConfig struct {
 	Http       *HttpConfig       `hcl:"http,block"`
 	Management *ManagementConfig `hcl:"management,block"`
-	Syslog     *SyslogConfig     `hcl:"syslog,block"`
+	Syslog     []*SyslogConfig   `hcl:"syslog,block"`
+	Otlp       *OtlpConfig       `hcl:"otlp,block"`
 }
 
 // Configuration for the "HTTP" service, which provides the REST API and web UI.
+// The external URL is the one browsers reach it at, through any reverse proxy,
+// which the sign-in flows of the auth providers are sent back to.
 type HttpConfig struct {
 	BindAddress string         `hcl:"bind,optional"`
 	MountPath   string         `hcl:"mount,optional"`
+	ExternalURL string         `hcl:"external_url,optional"`
 	Tls         *HttpTlsConfig `hcl:"tls,block"`
 }
 
@@ -61,21 +73,48 @@ type HttpTlsConfig struct {
 	Key  string `hcl:"key"`
 }
 
-// Configuration for the "Syslog" service, which provides a syslog server for
-// receiving log messages from other services. It supports both TCP and UDP
-// protocols, and can optionally use TLS for secure communication.
+// Configuration for a listener of the "Syslog" service, which provides syslog
+// servers for receiving log messages from other services. Each listener is
+// named by the block's label, supports either the TCP or UDP protocol, accepts
+// messages in a given format (auto-detected by default), and can optionally use
+// TLS for secure communication. Messages are only accepted from
+// AllowedOrigins and, over TLS, from clients whose certificate has a subject
+// matching AllowedSubjects, if set. Messages matched by no syslog route are sent
+// to Pipeline, or to every pipeline if it is empty.
+//
+// Attributes a listener leaves unset are inherited from the default listener,
+// configured from environment variables.
+//
+// The initial allowed origins of every listener are merged to seed the system
+// configuration, which applies to all listeners.
 type SyslogConfig struct {
+	Name                  string           `hcl:"name,label"`
 	Bind                  string           `hcl:"bind,optional"`
 	Protocol              string           `hcl:"protocol,optional"`
+	Format                string           `hcl:"format,optional"`
+	Pipeline              string           `hcl:"pipeline,optional"`
+	AllowedOrigins        []string         `hcl:"allowed_origins,optional"`
+	AllowedSubjects       []string         `hcl:"allowed_subjects,optional"`
 	InitialAllowedOrigins []string         `hcl:"initial_allowed_origins,optional"`
 	Tls                   *SyslogTlsConfig `hcl:"tls,block"`
 }
 
-// Configuration for TLS settings for the Syslog service.
+// Configuration for TLS settings for a Syslog listener. Client certificates are
+// verified against ClientCA, a PEM bundle, or the system's roots if it is
+// empty.
 type SyslogTlsConfig struct {
 	Cert        string `hcl:"cert"`
 	Key         string `hcl:"key"`
 	AuthEnabled bool   `hcl:"auth,optional"`
+	ClientCA    string `hcl:"client_ca,optional"`
+}
+
+// Configuration for the "OTLP" service, which provides an OpenTelemetry
+// collector endpoint receiving logs over gRPC. The service is only started when
+// this block is present.
+type OtlpConfig struct {
+	Bind string         `hcl:"bind,optional"`
+	Tls  *HttpTlsConfig `hcl:"tls,block"`
 }
 
 // StorageConfig groups together settings related to storage.
@@ -165,6 +204,21 @@ func DefaultConfig() *RootConfig {
 			Cert:        defaultSyslogTlsCert,
 			Key:         defaultSyslogTlsCertKey,
 			AuthEnabled: defaultSyslogTlsAuthEnabled,
+			ClientCA:    defaultSyslogTlsClientCA,
+		}
+	}
+
+	var defaultOtlpConfig *OtlpConfig
+	if defaultOtlpEnabled {
+		defaultOtlpConfig = &OtlpConfig{
+			Bind: defaultOtlpBindAddr,
+		}
+
+		if defaultOtlpTlsEnabled {
+			defaultOtlpConfig.Tls = &HttpTlsConfig{
+				Cert: defaultOtlpTlsCert,
+				Key:  defaultOtlpTlsCertKey,
+			}
 		}
 	}
 
@@ -194,18 +248,25 @@ func DefaultConfig() *RootConfig {
 			Http: &HttpConfig{
 				BindAddress: defaultHttpBindAddress,
 				MountPath:   defaultHttpMountPath,
+				ExternalURL: defaultHttpExternalURL,
 				Tls:         defaultHttpTlsConfig,
 			},
 			Management: &ManagementConfig{
 				BindAddress: defaultMgmtBindAddress,
 				Tls:         defaultMgmtTlsConfig,
 			},
-			Syslog: &SyslogConfig{
-				Bind:                  defaultSyslogBindAddr,
-				Protocol:              defaultSyslogProtocol,
-				Tls:                   defaultSyslogTlsConfig,
-				InitialAllowedOrigins: defaultSyslogInitialAllowedOrigins,
+			Syslog: []*SyslogConfig{
+				{
+					Name:                  defaultSyslogName,
+					Bind:                  defaultSyslogBindAddr,
+					Protocol:              defaultSyslogProtocol,
+					Format:                defaultSyslogFormat,
+					Pipeline:              defaultSyslogPipeline,
+					Tls:                   defaultSyslogTlsConfig,
+					InitialAllowedOrigins: defaultSyslogInitialAllowedOrigins,
+				},
 			},
+			Otlp: defaultOtlpConfig,
 		},
 		Storage: &StorageConfig{
 			Backend: storageBackendConfig,
@@ -247,17 +308,160 @@ func DefaultStorageBackendFoundationDbConfig() *StorageBackendFoundationDbConfig
 func (c *RootConfig) Validate() error {
 	c.Storage.Backend.Validate()
 
-	if c.Services.Syslog.Protocol != "tcp" && c.Services.Syslog.Protocol != "udp" {
-		return fmt.Errorf("invalid syslog protocol: %s", c.Services.Syslog.Protocol)
+	if err := c.Services.Http.Validate(); err != nil {
+		return fmt.Errorf("invalid HTTP service: %w", err)
+	}
+
+	names := make([]string, 0, len(c.Services.Syslog))
+	addresses := make([]string, 0, len(c.Services.Syslog))
+
+	for _, listener := range c.Services.Syslog {
+		if slices.Contains(names, listener.Name) {
+			return fmt.Errorf("duplicate syslog listener: %s", listener.Name)
+		}
+		names = append(names, listener.Name)
+
+		address := listener.Protocol + "://" + listener.Bind
+		if slices.Contains(addresses, address) {
+			return fmt.Errorf("syslog listener %q: duplicate bind address: %s", listener.Name, address)
+		}
+		addresses = append(addresses, address)
+
+		if err := listener.Validate(); err != nil {
+			return fmt.Errorf("invalid syslog listener %q: %w", listener.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Validates the configuration of the HTTP service, ensuring that its external
+// URL, if any, is an absolute HTTP or HTTPS URL.
+func (c *HttpConfig) Validate() error {
+	if c.ExternalURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(c.ExternalURL)
+	if err != nil {
+		return fmt.Errorf("invalid external URL: %w", err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid external URL %q: must be an absolute HTTP or HTTPS URL", c.ExternalURL)
+	}
+
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid external URL %q: must not have a query or fragment", c.ExternalURL)
 	}
 
-	if c.Services.Syslog.Tls != nil && c.Services.Syslog.Protocol == "udp" {
+	return nil
+}
+
+// Validates the configuration of a Syslog listener, ensuring that it binds to
+// an address with a supported protocol and format, that its allowed origins are IP
+// addresses or CIDR ranges, and that its allowed subjects are valid patterns
+// checked over TLS.
+func (c *SyslogConfig) Validate() error {
+	if c.Bind == "" {
+		return fmt.Errorf("missing bind address")
+	}
+
+	if c.Protocol != "tcp" && c.Protocol != "udp" {
+		return fmt.Errorf("invalid syslog protocol: %s", c.Protocol)
+	}
+
+	if c.Tls != nil && c.Protocol == "udp" {
 		return fmt.Errorf("TLS is not supported for Syslog UDP protocol")
 	}
 
+	if !syslog.IsValidFormat(c.Format) {
+		return fmt.Errorf("invalid syslog format: %s", c.Format)
+	}
+
+	for _, origin := range c.AllowedOrigins {
+		if strings.Contains(origin, "/") {
+			if _, _, err := net.ParseCIDR(origin); err != nil {
+				return fmt.Errorf("invalid allowed origin: %s", origin)
+			}
+		} else if net.ParseIP(origin) == nil {
+			return fmt.Errorf("invalid allowed origin: %s", origin)
+		}
+	}
+
+	if len(c.AllowedSubjects) > 0 && c.Tls == nil {
+		return fmt.Errorf("allowed subjects require TLS")
+	}
+
+	for _, subject := range c.AllowedSubjects {
+		if _, err := path.Match(subject, ""); err != nil || subject == "" {
+			return fmt.Errorf("invalid allowed subject: %s", subject)
+		}
+	}
+
 	return nil
 }
 
+// Fills the attributes a Syslog listener leaves unset from the default
+// listener. The default TLS settings are only inherited by TCP listeners, since
+// TLS is not supported over UDP.
+func (c *SyslogConfig) inheritDefaults(defaults *SyslogConfig) {
+	if c.Bind == "" {
+		c.Bind = defaults.Bind
+	}
+
+	if c.Protocol == "" {
+		c.Protocol = defaults.Protocol
+	}
+
+	if c.Format == "" {
+		c.Format = defaults.Format
+	}
+
+	if c.Pipeline == "" {
+		c.Pipeline = defaults.Pipeline
+	}
+
+	if c.InitialAllowedOrigins == nil {
+		c.InitialAllowedOrigins = defaults.InitialAllowedOrigins
+	}
+
+	if c.Tls == nil && c.Protocol == "tcp" {
+		c.Tls = defaults.Tls
+	}
+}
+
+// Reports the syslog blocks declared without a name, as in configuration files
+// written before listeners were named, with a hint on how to name them.
+func checkSyslogLabels(body hcl.Body) hcl.Diagnostics {
+	syntaxBody, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	var diags hcl.Diagnostics
+
+	for _, services := range syntaxBody.Blocks {
+		if services.Type != "services" {
+			continue
+		}
+
+		for _, block := range services.Body.Blocks {
+			if block.Type == "syslog" && len(block.Labels) == 0 {
+				rng := block.DefRange()
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unnamed syslog listener",
+					Detail:   `Syslog blocks must be named after the listener they define, for example: syslog "default" { ... }.`,
+					Subject:  &rng,
+				})
+			}
+		}
+	}
+
+	return diags
+}
+
 // Determines the storage backend type from the configuration and decodes the
 // remaining body into the appropriate backend configuration struct. Returns an
 // error if the backend is unsupported or if decoding fails.
@@ -332,11 +536,41 @@ func (cfg *RootConfig) Load(path string) error {
 		return diags
 	}
 
+	diags = diags.Extend(checkSyslogLabels(file.Body))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	// Syslog listeners declared in the file replace the default one, inherited
+	// from environment variables, instead of being decoded on top of it. The
+	// attributes they leave unset are inherited from it afterwards.
+	var defaultSyslogListeners []*SyslogConfig
+	if cfg.Services != nil {
+		defaultSyslogListeners = cfg.Services.Syslog
+		cfg.Services.Syslog = nil
+	}
+
 	diags = diags.Extend(gohcl.DecodeBody(file.Body, nil, cfg))
 	if diags.HasErrors() {
 		return diags
 	}
 
+	if len(cfg.Services.Syslog) == 0 {
+		cfg.Services.Syslog = defaultSyslogListeners
+	} else {
+		defaultSyslogListener := &SyslogConfig{
+			Protocol: "udp",
+			Format:   syslog.FORMAT_AUTO,
+		}
+		if len(defaultSyslogListeners) > 0 {
+			defaultSyslogListener = defaultSyslogListeners[0]
+		}
+
+		for _, listener := range cfg.Services.Syslog {
+			listener.inheritDefaults(defaultSyslogListener)
+		}
+	}
+
 	if cfg.Storage == nil {
 		return ErrNoStorageBackend
 	}
@@ -359,9 +593,9 @@ func (cfg *RootConfig) Load(path string) error {
 // certificates for the services that have TLS enabled.
 func (cfg *RootConfig) AsServerOptions() (server.Options, error) {
 	var (
-		httpTlsConfig   *tls.Config
-		mgmtTlsConfig   *tls.Config
-		syslogTlsConfig *tls.Config
+		httpTlsConfig *tls.Config
+		mgmtTlsConfig *tls.Config
+		otlpTlsConfig *tls.Config
 	)
 
 	if cfg.Services.Http.Tls != nil {
@@ -388,21 +622,51 @@ func (cfg *RootConfig) AsServerOptions() (server.Options, error) {
 		}
 	}
 
-	if cfg.Services.Syslog.Tls != nil {
-		cert, err := tls.LoadX509KeyPair(cfg.Services.Syslog.Tls.Cert, cfg.Services.Syslog.Tls.Key)
+	syslogListeners := make([]syslog.ServerOptions, len(cfg.Services.Syslog))
+	syslogInitialAllowedOrigins := []string{}
+
+	for i, listener := range cfg.Services.Syslog {
+		syslogTlsConfig, err := listener.Tls.Load()
 		if err != nil {
-			return server.Options{}, fmt.Errorf("failed to load Syslog TLS certificate: %w", err)
+			return server.Options{}, fmt.Errorf("syslog listener %q: %w", listener.Name, err)
 		}
 
-		clientAuth := tls.VerifyClientCertIfGiven
-		if cfg.Services.Syslog.Tls.AuthEnabled {
-			clientAuth = tls.RequireAndVerifyClientCert
+		syslogListeners[i] = syslog.ServerOptions{
+			Name:            listener.Name,
+			TcpMode:         listener.Protocol == "tcp",
+			BindAddress:     listener.Bind,
+			Format:          listener.Format,
+			TlsConfig:       syslogTlsConfig,
+			AllowedOrigins:  listener.AllowedOrigins,
+			AllowedSubjects: listener.AllowedSubjects,
+			Pipeline:        listener.Pipeline,
 		}
 
-		syslogTlsConfig = &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			ClientAuth:   clientAuth,
-			MinVersion:   tls.VersionTLS13,
+		for _, origin := range listener.InitialAllowedOrigins {
+			if !slices.Contains(syslogInitialAllowedOrigins, origin) {
+				syslogInitialAllowedOrigins = append(syslogInitialAllowedOrigins, origin)
+			}
+		}
+	}
+
+	otlpEnabled := cfg.Services.Otlp != nil
+	otlpBindAddress := defaultOtlpBindAddr
+
+	if otlpEnabled {
+		if cfg.Services.Otlp.Bind != "" {
+			otlpBindAddress = cfg.Services.Otlp.Bind
+		}
+
+		if cfg.Services.Otlp.Tls != nil {
+			cert, err := tls.LoadX509KeyPair(cfg.Services.Otlp.Tls.Cert, cfg.Services.Otlp.Tls.Key)
+			if err != nil {
+				return server.Options{}, fmt.Errorf("failed to load OTLP TLS certificate: %w", err)
+			}
+
+			otlpTlsConfig = &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				MinVersion:   tls.VersionTLS13,
+			}
 		}
 	}
 
@@ -426,15 +690,18 @@ func (cfg *RootConfig) AsServerOptions() (server.Options, error) {
 	opts := server.Options{
 		HttpBindAddress: cfg.Services.Http.BindAddress,
 		HttpMountPath:   strings.TrimSuffix(cfg.Services.Http.MountPath, "/"),
+		HttpExternalURL: strings.TrimSuffix(cfg.Services.Http.ExternalURL, "/"),
 		HttpTlsConfig:   httpTlsConfig,
 
 		MgmtBindAddress: cfg.Services.Management.BindAddress,
 		MgmtTlsConfig:   mgmtTlsConfig,
 
-		SyslogTcpMode:               cfg.Services.Syslog.Protocol == "tcp",
-		SyslogBindAddress:           cfg.Services.Syslog.Bind,
-		SyslogTlsConfig:             syslogTlsConfig,
-		SyslogInitialAllowedOrigins: cfg.Services.Syslog.InitialAllowedOrigins,
+		SyslogListeners:             syslogListeners,
+		SyslogInitialAllowedOrigins: syslogInitialAllowedOrigins,
+
+		OtlpEnabled:     otlpEnabled,
+		OtlpBindAddress: otlpBindAddress,
+		OtlpTlsConfig:   otlpTlsConfig,
 
 		Storage: storageOptions,
 
@@ -447,3 +714,41 @@ func (cfg *RootConfig) AsServerOptions() (server.Options, error) {
 
 	return opts, nil
 }
+
+// Loads the certificate and client CA bundle of a Syslog listener into a TLS
+// configuration. Returns nil if TLS is not enabled.
+func (c *SyslogTlsConfig) Load() (*tls.Config, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Syslog TLS certificate: %w", err)
+	}
+
+	clientAuth := tls.VerifyClientCertIfGiven
+	if c.AuthEnabled {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   clientAuth,
+		MinVersion:   tls.VersionTLS13,
+	}
+
+	if c.ClientCA != "" {
+		pem, err := os.ReadFile(c.ClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Syslog TLS client CA: %w", err)
+		}
+
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in Syslog TLS client CA: %s", c.ClientCA)
+		}
+	}
+
+	return tlsConfig, nil
+}
@@ -15,6 +15,7 @@ var (
 
 	defaultHttpBindAddress = getEnvString("FLOWG_HTTP_BIND_ADDRESS", ":5080")
 	defaultHttpMountPath   = getEnvString("FLOWG_HTTP_MOUNT_PATH", "/")
+	defaultHttpExternalURL = getEnvString("FLOWG_HTTP_EXTERNAL_URL", "")
 	defaultHttpTlsEnabled  = getEnvBool("FLOWG_HTTP_TLS_ENABLED", false)
 	defaultHttpTlsCert     = getEnvString("FLOWG_HTTP_TLS_CERT", "")
 	defaultHttpTlsCertKey  = getEnvString("FLOWG_HTTP_TLS_KEY", "")
@@ -24,15 +25,25 @@ var (
 	defaultMgmtTlsCert     = getEnvString("FLOWG_MGMT_TLS_CERT", "")
 	defaultMgmtTlsCertKey  = getEnvString("FLOWG_MGMT_TLS_KEY", "")
 
+	defaultSyslogName     = getEnvString("FLOWG_SYSLOG_NAME", "default")
 	defaultSyslogProtocol = getEnvString("FLOWG_SYSLOG_PROTOCOL", "udp")
 	defaultSyslogBindAddr = getEnvString("FLOWG_SYSLOG_BIND_ADDRESS", ":5514")
+	defaultSyslogFormat   = getEnvString("FLOWG_SYSLOG_FORMAT", "auto")
+	defaultSyslogPipeline = getEnvString("FLOWG_SYSLOG_PIPELINE", "")
 
 	defaultSyslogTlsEnabled            = getEnvBool("FLOWG_SYSLOG_TLS_ENABLED", false)
 	defaultSyslogTlsCert               = getEnvString("FLOWG_SYSLOG_TLS_CERT", "")
 	defaultSyslogTlsCertKey            = getEnvString("FLOWG_SYSLOG_TLS_KEY", "")
 	defaultSyslogTlsAuthEnabled        = getEnvBool("FLOWG_SYSLOG_TLS_AUTH", false)
+	defaultSyslogTlsClientCA           = getEnvString("FLOWG_SYSLOG_TLS_CLIENT_CA", "")
 	defaultSyslogInitialAllowedOrigins = getEnvListString("FLOWG_SYSLOG_INITIAL_ALLOWED_ORIGINS", []string{})
 
+	defaultOtlpEnabled    = getEnvBool("FLOWG_OTLP_ENABLED", false)
+	defaultOtlpBindAddr   = getEnvString("FLOWG_OTLP_BIND_ADDRESS", ":4317")
+	defaultOtlpTlsEnabled = getEnvBool("FLOWG_OTLP_TLS_ENABLED", false)
+	defaultOtlpTlsCert    = getEnvString("FLOWG_OTLP_TLS_CERT", "")
+	defaultOtlpTlsCertKey = getEnvString("FLOWG_OTLP_TLS_KEY", "")
+
 	defaultStorageBackend             = getEnvString("FLOWG_STORAGE_BACKEND", "badgerdb")
 	defaultBadgerAuthDir              = getEnvString("FLOWG_BADGER_AUTH_DIR", "./data/auth")
 	defaultBadgerConfigDir            = getEnvString("FLOWG_BADGER_CONFIG_DIR", "./data/config")
@@ -25,7 +25,7 @@ services {
     }
   }
 
-  syslog {
+  syslog "default" {
     bind = ":5514"
     protocol = "tcp"
     initial_allowed_origins = []
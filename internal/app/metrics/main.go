@@ -1,10 +1,16 @@
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 var (
-	streamLogCounter   *prometheus.CounterVec
-	pipelineLogCounter *prometheus.CounterVec
+	streamLogCounter       *prometheus.CounterVec
+	pipelineLogCounter     *prometheus.CounterVec
+	forwarderQueueDepth    *prometheus.GaugeVec
+	forwarderFlushDuration *prometheus.HistogramVec
 )
 
 // Setup creates the FlowG metrics and registers them with the default
@@ -26,9 +32,27 @@ func Setup() {
 		[]string{"pipeline", "status"},
 	)
 
+	forwarderQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "flowg_forwarder_queue_depth",
+			Help: "Number of log messages waiting in a forwarder's batching queue",
+		},
+		[]string{"forwarder"},
+	)
+	forwarderFlushDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "flowg_forwarder_flush_duration_seconds",
+			Help:    "Time taken by a forwarder to deliver a batch of log messages",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"forwarder", "status"},
+	)
+
 	prometheus.MustRegister(
 		streamLogCounter,
 		pipelineLogCounter,
+		forwarderQueueDepth,
+		forwarderFlushDuration,
 	)
 }
 
@@ -50,3 +74,22 @@ func IncPipelineLogCounter(pipeline string, success bool) {
 
 	pipelineLogCounter.WithLabelValues(pipeline, status).Inc()
 }
+
+// AddForwarderQueueDepth adjusts the number of log records waiting in the
+// batching queue of the given forwarder.
+func AddForwarderQueueDepth(forwarder string, delta int) {
+	forwarderQueueDepth.WithLabelValues(forwarder).Add(float64(delta))
+}
+
+// ObserveForwarderFlush records how long the given forwarder took to deliver a
+// batch, labelling the sample as a success or an error.
+func ObserveForwarderFlush(forwarder string, duration time.Duration, success bool) {
+	var status string
+	if success {
+		status = "success"
+	} else {
+		status = "error"
+	}
+
+	forwarderFlushDuration.WithLabelValues(forwarder, status).Observe(duration.Seconds())
+}
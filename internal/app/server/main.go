@@ -10,11 +10,14 @@ import (
 
 	storage "link-society.com/flowg/internal/storage/interfaces"
 
+	"link-society.com/flowg/internal/engines/alerts"
+	"link-society.com/flowg/internal/engines/audit"
 	"link-society.com/flowg/internal/engines/lognotify"
 	"link-society.com/flowg/internal/engines/pipelines"
 
 	"link-society.com/flowg/internal/services/http"
 	"link-society.com/flowg/internal/services/mgmt"
+	"link-society.com/flowg/internal/services/otlp"
 	"link-society.com/flowg/internal/services/syslog"
 )
 
@@ -24,16 +27,19 @@ import (
 type Options struct {
 	HttpBindAddress string
 	HttpMountPath   string
+	HttpExternalURL string
 	HttpTlsConfig   *tls.Config
 
 	MgmtBindAddress string
 	MgmtTlsConfig   *tls.Config
 
-	SyslogTcpMode               bool
-	SyslogBindAddress           string
-	SyslogTlsConfig             *tls.Config
+	SyslogListeners             []syslog.ServerOptions
 	SyslogInitialAllowedOrigins []string
 
+	OtlpEnabled     bool
+	OtlpBindAddress string
+	OtlpTlsConfig   *tls.Config
+
 	Storage StorageOptions
 
 	AuthInitialUser     string
@@ -55,10 +61,25 @@ type StorageOptions interface {
 }
 
 // NewServer assembles the complete FlowG server as a single fx module. It wires
-// the three storage backends, the engine layer (log notifier and pipeline
-// runner) and the network services (HTTP, management, syslog) together, and
-// registers a bootstrap handler that seeds the default configuration on start.
+// the three storage backends, the engine layer (log notifier, pipeline runner,
+// alert manager and audit recorder) and the network services (HTTP,
+// management, one syslog server per listener and, when enabled, OTLP)
+// together, and registers a bootstrap handler that seeds the default
+// configuration on start.
 func NewServer(opts Options) fx.Option {
+	syslogServices := make([]fx.Option, len(opts.SyslogListeners))
+	for i, listener := range opts.SyslogListeners {
+		syslogServices[i] = syslog.NewServer(listener)
+	}
+
+	otlpService := fx.Options()
+	if opts.OtlpEnabled {
+		otlpService = otlp.NewServer(otlp.ServerOptions{
+			BindAddress: opts.OtlpBindAddress,
+			TlsConfig:   opts.OtlpTlsConfig,
+		})
+	}
+
 	return fx.Module(
 		"app.server",
 		// Storage Layer
@@ -68,21 +89,21 @@ func NewServer(opts Options) fx.Option {
 		// Engine Layer
 		lognotify.NewLogNotifier(),
 		pipelines.NewRunner(),
+		alerts.NewManager(),
+		audit.NewRecorder(),
 		// Service Layer
 		http.NewServer(http.ServerOptions{
 			BindAddress: opts.HttpBindAddress,
 			MountPath:   opts.HttpMountPath,
+			ExternalURL: opts.HttpExternalURL,
 			TlsConfig:   opts.HttpTlsConfig,
 		}),
 		mgmt.NewServer(mgmt.ServerOptions{
 			BindAddress: opts.MgmtBindAddress,
 			TlsConfig:   opts.MgmtTlsConfig,
 		}),
-		syslog.NewServer(syslog.ServerOptions{
-			TcpMode:     opts.SyslogTcpMode,
-			BindAddress: opts.SyslogBindAddress,
-			TlsConfig:   opts.SyslogTlsConfig,
-		}),
+		fx.Options(syslogServices...),
+		otlpService,
 		fx.Provide(func(
 			lc fx.Lifecycle,
 			authStorage storage.AuthStorage,
@@ -118,10 +139,13 @@ func NewServer(opts Options) fx.Option {
 			// Engine layer
 			LogNotifier     lognotify.LogNotifier
 			PipelinesRunner pipelines.Runner
+			AlertManager    alerts.Manager
+			AuditRecorder   audit.Recorder
 			// Service layer
 			HttpServer       *http.Server
 			ManagementServer *mgmt.Server
-			SyslogServer     *syslog.Server
+			SyslogServers    []*syslog.Server `group:"syslog_servers"`
+			OtlpServer       *otlp.Server     `optional:"true"`
 			// Bootstrap handler
 			BootstrapHandler *bootstrapHandler
 		}) {
@@ -0,0 +1,106 @@
+package alerts_test
+
+import (
+	"testing"
+
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+	"link-society.com/flowg/internal/storage/mocks"
+
+	"link-society.com/flowg/internal/engines/alerts"
+	"link-society.com/flowg/internal/engines/lognotify"
+)
+
+// TestAlertFiresOnceWithinCooldown guards against a rule firing before its
+// threshold is reached, counting records its filter rejects, or firing again
+// while cooling down.
+func TestAlertFiresOnceWithinCooldown(t *testing.T) {
+	requests := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- string(body)
+	}))
+	defer server.Close()
+
+	events := make(chan models.AlertEvent, 10)
+
+	configStorage := mocks.NewMockConfigStorage().(*mocks.MockConfigStorage)
+	configStorage.On("ListAlerts", mock.Anything).Return([]string{"errors"}, nil)
+	configStorage.On("ReadAlert", mock.Anything, "errors").Return(
+		&models.AlertRule{
+			Stream:    "test",
+			Filter:    `level == "error"`,
+			Threshold: 2,
+			Window:    60,
+			Cooldown:  3600,
+			Forwarder: "webhook",
+		},
+		nil,
+	)
+	configStorage.On("ReadForwarder", mock.Anything, "webhook").Return(
+		&models.ForwarderV2{
+			Version: 2,
+			Config: models.ForwarderConfigV2{
+				Http: &models.ForwarderHttpV2{Type: "http", Url: server.URL},
+			},
+		},
+		nil,
+	)
+	configStorage.
+		On("RecordAlertEvent", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			events <- args.Get(1).(models.AlertEvent)
+		}).
+		Return(nil)
+
+	var notifier lognotify.LogNotifier
+
+	app := fxtest.New(
+		t,
+		lognotify.NewLogNotifier(),
+		alerts.NewManager(),
+		fx.Provide(func() storage.ConfigStorage { return configStorage }),
+		fx.Populate(&notifier),
+		fx.Invoke(func(alerts.Manager) {}),
+		fx.NopLogger,
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	for _, level := range []string{"error", "info", "error", "error", "error"} {
+		record := models.NewLogRecord(map[string]string{"level": level})
+		if err := notifier.Notify(t.Context(), "test", "key", *record); err != nil {
+			t.Fatalf("unexpected error while notifying: %v", err)
+		}
+	}
+
+	select {
+	case event := <-events:
+		if event.Alert != "errors" || event.Count != 2 || event.Error != "" {
+			t.Fatalf("unexpected alert event: %+v", event)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("alert did not fire")
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 delivered alert, got %d", len(requests))
+	}
+
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected alert event during cooldown: %+v", event)
+
+	case <-time.After(200 * time.Millisecond):
+	}
+}
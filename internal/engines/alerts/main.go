@@ -0,0 +1,112 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/vladopajic/go-actor/actor"
+	"go.uber.org/fx"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+
+	"link-society.com/flowg/internal/engines/lognotify"
+)
+
+// Manager evaluates the stored alert rules against the records routed to their
+// streams, and delivers an alert through the rule's forwarder whenever one
+// fires. Rules are loaded when the application starts.
+type Manager interface {
+	// Reload re-reads every alert rule from storage and replaces the rules under
+	// evaluation, along with their forwarders and stream subscriptions; call it
+	// after a rule or a forwarder changes. The running counts are reset.
+	Reload(ctx context.Context) error
+}
+
+type managerImpl struct {
+	mbox actor.MailboxSender[message]
+}
+
+type deps struct {
+	fx.In
+
+	ConfigStorage storage.ConfigStorage
+	LogNotifier   lognotify.LogNotifier
+}
+
+var _ Manager = (*managerImpl)(nil)
+
+// NewManager returns an fx module providing a Manager backed by a single actor.
+// The rules are loaded once the actor is started; on shutdown the subscriptions
+// are cancelled and the forwarders closed before the actor stops.
+func NewManager() fx.Option {
+	return fx.Module(
+		"alertManager",
+		fx.Provide(func(lc fx.Lifecycle) actor.Mailbox[message] {
+			mbox := actor.NewMailbox[message]()
+
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					mbox.Start()
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					mbox.Stop()
+					return nil
+				},
+			})
+
+			return mbox
+		}),
+		fx.Provide(func(lc fx.Lifecycle, d deps, mbox actor.Mailbox[message]) Manager {
+			a := actor.New(&worker{
+				mbox:          mbox,
+				configStorage: d.ConfigStorage,
+				logNotifier:   d.LogNotifier,
+				rules:         make(map[string][]*rule),
+			})
+			manager := &managerImpl{mbox: mbox}
+
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					a.Start()
+					return manager.Reload(ctx)
+				},
+				OnStop: func(ctx context.Context) error {
+					if err := manager.unload(ctx); err != nil {
+						return err
+					}
+
+					a.Stop()
+					return nil
+				},
+			})
+
+			return manager
+		}),
+	)
+}
+
+// Reload asks the actor to replace its rules with the stored ones and waits for
+// the new rules to be in place.
+func (m *managerImpl) Reload(ctx context.Context) error {
+	replyTo := make(chan error, 1)
+
+	err := m.mbox.Send(ctx, reloadMessage{replyTo: replyTo})
+	if err != nil {
+		return err
+	}
+
+	return <-replyTo
+}
+
+// unload asks the actor to drop every rule, cancelling its subscriptions and
+// closing its forwarders, and waits for it to be done.
+func (m *managerImpl) unload(ctx context.Context) error {
+	replyTo := make(chan error, 1)
+
+	err := m.mbox.Send(ctx, unloadMessage{replyTo: replyTo})
+	if err != nil {
+		return err
+	}
+
+	return <-replyTo
+}
@@ -0,0 +1,111 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"time"
+
+	"link-society.com/flowg/internal/models"
+
+	"link-society.com/flowg/internal/engines/lognotify"
+)
+
+// message is a request handled by the manager actor; each variant knows how to
+// service itself against the worker.
+type message interface {
+	handle(ctx context.Context, w *worker)
+}
+
+// reloadMessage requests that the rules under evaluation be replaced by the
+// stored ones.
+type reloadMessage struct {
+	replyTo chan<- error
+}
+
+// unloadMessage requests that every rule be dropped, e.g. on shutdown.
+type unloadMessage struct {
+	replyTo chan<- error
+}
+
+// recordMessage carries a record routed to a watched stream, as received from
+// the log notifier.
+type recordMessage struct {
+	log        lognotify.LogMessage
+	receivedAt time.Time
+}
+
+func (msg reloadMessage) handle(ctx context.Context, w *worker) {
+	unloadErr := w.unload(ctx)
+	loadErr := w.load(ctx)
+
+	msg.replyTo <- errors.Join(unloadErr, loadErr)
+}
+
+func (msg unloadMessage) handle(ctx context.Context, w *worker) {
+	msg.replyTo <- w.unload(ctx)
+}
+
+func (msg recordMessage) handle(ctx context.Context, w *worker) {
+	for _, r := range w.rules[msg.log.Stream] {
+		fired, count, err := r.observe(msg.receivedAt, &msg.log.LogRecord)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to evaluate alert filter",
+				"channel", "alerts",
+				"alert", r.name,
+				"stream", msg.log.Stream,
+				"error", err.Error(),
+			)
+			continue
+		}
+
+		if fired {
+			w.deliver(ctx, r, msg.receivedAt, count)
+		}
+	}
+}
+
+// deliver sends the alert of a fired rule through its forwarder, in the
+// background so that a slow destination does not hold up the evaluation of
+// other records, and records the firing in the rule's history.
+func (w *worker) deliver(ctx context.Context, r *rule, now time.Time, count int64) {
+	w.deliveries.Add(1)
+
+	go func() {
+		defer w.deliveries.Done()
+
+		event := models.AlertEvent{
+			Timestamp: now,
+			Alert:     r.name,
+			Stream:    r.config.Stream,
+			Forwarder: r.config.Forwarder,
+			Count:     count,
+		}
+
+		if err := r.runtime.Call(ctx, r.alertRecord(now, count)); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to deliver alert",
+				"channel", "alerts",
+				"alert", r.name,
+				"forwarder", r.config.Forwarder,
+				"error", err.Error(),
+			)
+
+			event.Error = err.Error()
+		}
+
+		if err := w.configStorage.RecordAlertEvent(ctx, event); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to record alert event",
+				"channel", "alerts",
+				"alert", r.name,
+				"error", err.Error(),
+			)
+		}
+	}()
+}
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"link-society.com/flowg/internal/engines/alerts"
+)
+
+// MockManager is a testify mock implementation of Manager for use in tests.
+type MockManager struct {
+	mock.Mock
+}
+
+var _ alerts.Manager = (*MockManager)(nil)
+
+// NewMockManager returns a Manager whose calls can be stubbed and asserted.
+func NewMockManager() alerts.Manager {
+	return &MockManager{}
+}
+
+func (m *MockManager) Reload(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
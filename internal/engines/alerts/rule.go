@@ -0,0 +1,134 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+
+	"strconv"
+	"time"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+	"link-society.com/flowg/internal/utils/langs/filtering"
+
+	"link-society.com/flowg/internal/engines/forwarders"
+)
+
+// rule is an alert rule under evaluation: its compiled filter, its forwarder's
+// runtime, and the arrival times of the latest matching records.
+type rule struct {
+	name    string
+	config  *models.AlertRule
+	filter  filtering.Filter
+	runtime forwarders.Runtime
+
+	matches   []time.Time
+	lastFired time.Time
+}
+
+// buildRule compiles a stored alert rule and initializes the runtime of its
+// forwarder.
+func buildRule(
+	ctx context.Context,
+	configStorage storage.ConfigStorage,
+	name string,
+	config *models.AlertRule,
+) (*rule, error) {
+	var filter filtering.Filter
+	if config.Filter != "" {
+		var err error
+		filter, err = filtering.Compile(config.Filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	forwarder, err := configStorage.ReadForwarder(ctx, config.Forwarder)
+	if err != nil {
+		return nil, err
+	}
+	if forwarder == nil {
+		return nil, fmt.Errorf("forwarder %q not found", config.Forwarder)
+	}
+
+	runtime, err := forwarders.NewRuntime(forwarder)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runtime.Init(ctx); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+
+	return &rule{
+		name:    name,
+		config:  config,
+		filter:  filter,
+		runtime: runtime,
+	}, nil
+}
+
+// observe accounts for a record received at the given time and reports whether
+// the rule fires, along with the number of matching records within the window.
+//
+// Only the arrival times of the latest Threshold matches are kept: the rule
+// fires when the oldest of them is still within the window, unless it already
+// fired less than Cooldown seconds ago. Firing resets the count, so the next
+// alert requires Threshold new matches.
+func (r *rule) observe(now time.Time, record *models.LogRecord) (bool, int64, error) {
+	if r.filter != nil {
+		matched, err := r.filter.Evaluate(record)
+		if err != nil {
+			return false, 0, err
+		}
+		if !matched {
+			return false, 0, nil
+		}
+	}
+
+	r.matches = append(r.matches, now)
+
+	cutoff := now.Add(-time.Duration(r.config.Window) * time.Second)
+	for len(r.matches) > 0 && !r.matches[0].After(cutoff) {
+		r.matches = r.matches[1:]
+	}
+	if int64(len(r.matches)) > r.config.Threshold {
+		r.matches = r.matches[int64(len(r.matches))-r.config.Threshold:]
+	}
+
+	count := int64(len(r.matches))
+	if count < r.config.Threshold {
+		return false, count, nil
+	}
+
+	cooldown := time.Duration(r.config.Cooldown) * time.Second
+	if !r.lastFired.IsZero() && now.Sub(r.lastFired) < cooldown {
+		return false, count, nil
+	}
+
+	r.lastFired = now
+	r.matches = nil
+
+	return true, count, nil
+}
+
+// alertRecord synthesizes the log record delivered through the rule's
+// forwarder when it fires.
+func (r *rule) alertRecord(now time.Time, count int64) *models.LogRecord {
+	return &models.LogRecord{
+		Timestamp: now,
+		Fields: map[string]string{
+			"alert":     r.name,
+			"stream":    r.config.Stream,
+			"filter":    r.config.Filter,
+			"threshold": strconv.FormatInt(r.config.Threshold, 10),
+			"window":    strconv.FormatInt(r.config.Window, 10),
+			"count":     strconv.FormatInt(count, 10),
+			"message": fmt.Sprintf(
+				"Alert %q fired: %d records matched in stream %q within %d seconds",
+				r.name, count, r.config.Stream, r.config.Window,
+			),
+		},
+	}
+}
@@ -0,0 +1,140 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"sync"
+	"time"
+
+	"github.com/vladopajic/go-actor/actor"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+
+	"link-society.com/flowg/internal/engines/lognotify"
+)
+
+// worker is the manager's actor body. It owns the rules under evaluation,
+// grouped by stream, and their sliding-window state; serialising message
+// handling keeps that state free of locking.
+type worker struct {
+	mbox actor.Mailbox[message]
+
+	configStorage storage.ConfigStorage
+	logNotifier   lognotify.LogNotifier
+
+	rules       map[string][]*rule
+	unsubscribe context.CancelFunc
+	deliveries  sync.WaitGroup
+}
+
+var _ actor.Worker = (*worker)(nil)
+
+func (w *worker) DoWork(ctx actor.Context) actor.WorkerStatus {
+	select {
+	case <-ctx.Done():
+		return actor.WorkerEnd
+
+	case msg, ok := <-w.mbox.ReceiveC():
+		if !ok {
+			return actor.WorkerEnd
+		}
+
+		msg.handle(ctx, w)
+
+		return actor.WorkerContinue
+	}
+}
+
+// load builds every stored rule and subscribes to the streams they watch. A
+// rule that fails to build (invalid filter, missing forwarder, ...) is logged
+// and skipped rather than preventing the others from being evaluated.
+func (w *worker) load(ctx context.Context) error {
+	names, err := w.configStorage.ListAlerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		config, err := w.configStorage.ReadAlert(ctx, name)
+		if err != nil {
+			return err
+		}
+		if config == nil {
+			continue
+		}
+
+		r, err := buildRule(ctx, w.configStorage, name, config)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to load alert",
+				"channel", "alerts",
+				"alert", name,
+				"error", err.Error(),
+			)
+			continue
+		}
+
+		w.rules[config.Stream] = append(w.rules[config.Stream], r)
+	}
+
+	subCtx, cancel := context.WithCancel(context.Background())
+	w.unsubscribe = cancel
+
+	for stream := range w.rules {
+		logM, err := w.logNotifier.Subscribe(subCtx, stream)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			for {
+				select {
+				case <-subCtx.Done():
+					return
+
+				case msg, ok := <-logM.ReceiveC():
+					if !ok {
+						return
+					}
+
+					err := w.mbox.Send(subCtx, recordMessage{
+						log:        msg,
+						receivedAt: time.Now(),
+					})
+					if err != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	return nil
+}
+
+// unload cancels the stream subscriptions, waits for the alerts being
+// delivered, then closes the forwarders of every rule.
+func (w *worker) unload(ctx context.Context) error {
+	if w.unsubscribe != nil {
+		w.unsubscribe()
+		w.unsubscribe = nil
+	}
+
+	w.deliveries.Wait()
+
+	var errs []error
+
+	for stream, rules := range w.rules {
+		for _, r := range rules {
+			if err := r.runtime.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		delete(w.rules, stream)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,101 @@
+package audit_test
+
+import (
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/storage/generic/kv"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+	"link-society.com/flowg/internal/storage/mocks"
+
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/engines/lognotify"
+)
+
+// TestRecordStoresAndForwardsEvents guards against audit events not reaching
+// the reserved stream, or not being sent through the audit forwarder.
+func TestRecordStoresAndForwardsEvents(t *testing.T) {
+	requests := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("unexpected payload: %v", err)
+		}
+
+		requests <- payload.Body
+	}))
+	defer server.Close()
+
+	configStorage := mocks.NewMockConfigStorage().(*mocks.MockConfigStorage)
+	configStorage.On("ReadSystemConfig", mock.Anything).Return(
+		&models.SystemConfiguration{AuditForwarder: "siem"},
+		nil,
+	)
+	configStorage.On("ReadForwarder", mock.Anything, "siem").Return(
+		&models.ForwarderV2{
+			Version: 2,
+			Config: models.ForwarderConfigV2{
+				Http: &models.ForwarderHttpV2{
+					Type: "http",
+					Url:  server.URL,
+					Body: "@expr:log.actor + ' ' + log.operation + ' ' + log.target + ' ' + log.source_ip",
+				},
+			},
+		},
+		nil,
+	)
+
+	logStorage := mocks.NewMockLogStorage().(*mocks.MockLogStorage)
+	logStorage.
+		On("Ingest", mock.Anything, models.AUDIT_STREAM, mock.Anything).
+		Return(kv.Key{"entry", models.AUDIT_STREAM, "0"}, nil)
+
+	var recorder audit.Recorder
+
+	app := fxtest.New(
+		t,
+		lognotify.NewLogNotifier(),
+		audit.NewRecorder(),
+		fx.Provide(func() storage.ConfigStorage { return configStorage }),
+		fx.Provide(func() storage.LogStorage { return logStorage }),
+		fx.Populate(&recorder),
+		fx.NopLogger,
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	err := recorder.Record(t.Context(), models.AuditEvent{
+		Timestamp: time.Now(),
+		Actor:     "root",
+		Operation: "delete_pipeline",
+		Target:    "pipeline:default",
+		SourceIP:  "10.0.0.1",
+		Before:    &models.FlowGraphV2{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error while recording: %v", err)
+	}
+
+	logStorage.AssertExpectations(t)
+
+	select {
+	case body := <-requests:
+		if body != "root delete_pipeline pipeline:default 10.0.0.1" {
+			t.Fatalf("unexpected audit event: %q", body)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("audit event was not forwarded")
+	}
+}
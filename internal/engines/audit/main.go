@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+
+	"link-society.com/flowg/internal/engines/forwarders"
+	"link-society.com/flowg/internal/engines/lognotify"
+)
+
+// Recorder keeps track of the changes made through the API, as audit events
+// stored in the reserved [models.AUDIT_STREAM].
+type Recorder interface {
+	// Record stores an audit event, and notifies the subscribers of the audit
+	// stream. When the system configuration names an audit forwarder, the event
+	// is also sent through it, in the background.
+	Record(ctx context.Context, event models.AuditEvent) error
+}
+
+type recorderImpl struct {
+	configStorage storage.ConfigStorage
+	logStorage    storage.LogStorage
+	logNotifier   lognotify.LogNotifier
+
+	deliveries sync.WaitGroup
+}
+
+type deps struct {
+	fx.In
+
+	ConfigStorage storage.ConfigStorage
+	LogStorage    storage.LogStorage
+	LogNotifier   lognotify.LogNotifier
+}
+
+var _ Recorder = (*recorderImpl)(nil)
+
+// NewRecorder returns an fx module providing a Recorder. On shutdown, it waits
+// for the audit events being sent through the audit forwarder.
+func NewRecorder() fx.Option {
+	return fx.Module(
+		"auditRecorder",
+		fx.Provide(func(lc fx.Lifecycle, d deps) Recorder {
+			recorder := &recorderImpl{
+				configStorage: d.ConfigStorage,
+				logStorage:    d.LogStorage,
+				logNotifier:   d.LogNotifier,
+			}
+
+			lc.Append(fx.Hook{
+				OnStop: func(ctx context.Context) error {
+					recorder.deliveries.Wait()
+					return nil
+				},
+			})
+
+			return recorder
+		}),
+	)
+}
+
+func (r *recorderImpl) Record(ctx context.Context, event models.AuditEvent) error {
+	record, err := event.LogRecord()
+	if err != nil {
+		return err
+	}
+
+	key, err := r.logStorage.Ingest(ctx, models.AUDIT_STREAM, record)
+	if err != nil {
+		return fmt.Errorf("failed to store audit event: %w", err)
+	}
+
+	err = r.logNotifier.Notify(ctx, models.AUDIT_STREAM, strings.Join(key, ":"), *record)
+	if err != nil {
+		return fmt.Errorf("failed to notify audit event: %w", err)
+	}
+
+	systemConfig, err := r.configStorage.ReadSystemConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read system configuration: %w", err)
+	}
+
+	if systemConfig.AuditForwarder != "" {
+		r.deliver(context.WithoutCancel(ctx), systemConfig.AuditForwarder, record)
+	}
+
+	return nil
+}
+
+// deliver sends an audit event through the audit forwarder, in the background
+// so that a slow destination does not hold up the request that made the
+// change. Audit events are scarce, so the forwarder is set up for each of them,
+// always honoring its latest configuration. An event it fails to deliver is
+// moved to its dead-letter queue, to be replayed later.
+func (r *recorderImpl) deliver(ctx context.Context, forwarderName string, record *models.LogRecord) {
+	r.deliveries.Add(1)
+
+	go func() {
+		defer r.deliveries.Done()
+
+		if err := r.forward(ctx, forwarderName, record); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to deliver audit event",
+				"channel", "audit",
+				"forwarder", forwarderName,
+				"error", err.Error(),
+			)
+		}
+	}()
+}
+
+// forward sets up the named forwarder and sends a record through it, queuing
+// the record as a dead letter if the delivery fails.
+func (r *recorderImpl) forward(ctx context.Context, forwarderName string, record *models.LogRecord) error {
+	forwarder, err := r.configStorage.ReadForwarder(ctx, forwarderName)
+	if err != nil {
+		return err
+	}
+	if forwarder == nil {
+		return fmt.Errorf("forwarder %q not found", forwarderName)
+	}
+
+	runtime, err := forwarders.NewRuntime(forwarder)
+	if err != nil {
+		return err
+	}
+
+	if err := runtime.Init(ctx); err != nil {
+		_ = runtime.Close(ctx)
+		return err
+	}
+
+	err = errors.Join(runtime.Call(ctx, record), runtime.Close(ctx))
+	if err == nil {
+		return nil
+	}
+
+	attempts := 1
+
+	var deliveryErr *forwarders.DeliveryError
+	if errors.As(err, &deliveryErr) {
+		attempts = deliveryErr.Attempts
+	}
+
+	letter := models.DeadLetter{
+		Timestamp: time.Now(),
+		Forwarder: forwarderName,
+		Record:    *record,
+		Attempts:  attempts,
+		Error:     err.Error(),
+	}
+
+	if deadLetterErr := r.configStorage.PushDeadLetter(ctx, letter); deadLetterErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to queue dead letter: %w", deadLetterErr))
+	}
+
+	return err
+}
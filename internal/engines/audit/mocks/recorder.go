@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"link-society.com/flowg/internal/engines/audit"
+	"link-society.com/flowg/internal/models"
+)
+
+// MockRecorder is a testify mock implementation of Recorder for use in tests.
+type MockRecorder struct {
+	mock.Mock
+}
+
+var _ audit.Recorder = (*MockRecorder)(nil)
+
+// NewMockRecorder returns a Recorder whose calls can be stubbed and asserted.
+func NewMockRecorder() audit.Recorder {
+	return &MockRecorder{}
+}
+
+func (m *MockRecorder) Record(ctx context.Context, event models.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
@@ -1,8 +1,10 @@
 package forwarders
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 
 	"encoding/json"
 
@@ -14,14 +16,14 @@ import (
 )
 
 // awsCloudWatchRuntime sends records as log events to an AWS CloudWatch Logs
-// stream.
+// stream. Batches are sent in a single PutLogEvents call.
 type awsCloudWatchRuntime struct {
 	config *models.ForwarderAwsCloudWatchV2
 
 	client *cloudwatchlogs.Client
 }
 
-var _ Runtime = (*awsCloudWatchRuntime)(nil)
+var _ BatchRuntime = (*awsCloudWatchRuntime)(nil)
 
 func (rt *awsCloudWatchRuntime) Init(ctx context.Context) error {
 	rt.client = cloudwatchlogs.New(cloudwatchlogs.Options{
@@ -47,18 +49,31 @@ func awsCloudWatchTimestamp(record *models.LogRecord) int64 {
 }
 
 func (rt *awsCloudWatchRuntime) Call(ctx context.Context, record *models.LogRecord) error {
-	message, err := json.Marshal(record.Fields)
-	if err != nil {
-		return fmt.Errorf("failed to marshal record: %w", err)
-	}
+	return rt.CallBatch(ctx, []*models.LogRecord{record})
+}
+
+func (rt *awsCloudWatchRuntime) CallBatch(ctx context.Context, records []*models.LogRecord) error {
+	events := make([]types.InputLogEvent, 0, len(records))
 
-	event := types.InputLogEvent{
-		Message:   new(string(message)),
-		Timestamp: new(awsCloudWatchTimestamp(record)),
+	for _, record := range records {
+		message, err := json.Marshal(record.Fields)
+		if err != nil {
+			return fmt.Errorf("failed to marshal record: %w", err)
+		}
+
+		events = append(events, types.InputLogEvent{
+			Message:   new(string(message)),
+			Timestamp: new(awsCloudWatchTimestamp(record)),
+		})
 	}
 
-	_, err = rt.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
-		LogEvents:     []types.InputLogEvent{event},
+	// PutLogEvents rejects batches whose events are not in chronological order.
+	slices.SortStableFunc(events, func(a, b types.InputLogEvent) int {
+		return cmp.Compare(*a.Timestamp, *b.Timestamp)
+	})
+
+	_, err := rt.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+		LogEvents:     events,
 		LogGroupName:  &rt.config.Group,
 		LogStreamName: &rt.config.Stream,
 	})
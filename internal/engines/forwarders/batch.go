@@ -0,0 +1,188 @@
+package forwarders
+
+import (
+	"context"
+	"errors"
+
+	"sync"
+	"time"
+
+	"link-society.com/flowg/internal/app/metrics"
+	"link-society.com/flowg/internal/models"
+)
+
+// ErrRuntimeClosed is returned by a batching runtime's Call once it has been
+// closed.
+var ErrRuntimeClosed = errors.New("runtime closed")
+
+// FailureHandler is notified of the records a batching runtime failed to
+// deliver, along with the delivery error. It runs on the runtime's flushing
+// goroutine, long after the Call that queued them returned.
+type FailureHandler func(ctx context.Context, records []*models.LogRecord, err error)
+
+// batchRuntime queues the records passed to Call and delivers them from a
+// background goroutine, in batches when the wrapped runtime implements
+// BatchRuntime, one by one otherwise. Call only blocks while the queue is full,
+// which pushes back on the pipelines feeding a slow destination.
+type batchRuntime struct {
+	name      string
+	runtime   Runtime
+	policy    models.ForwarderBatchPolicyV2
+	onFailure FailureHandler
+
+	queue  chan *models.LogRecord
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+var _ Runtime = (*batchRuntime)(nil)
+
+// NewBatchRuntime wraps the runtime of the named forwarder so that records are
+// queued and delivered asynchronously according to policy. Records that cannot
+// be delivered are passed to onFailure.
+func NewBatchRuntime(
+	name string,
+	runtime Runtime,
+	policy *models.ForwarderBatchPolicyV2,
+	onFailure FailureHandler,
+) Runtime {
+	rt := &batchRuntime{
+		name:      name,
+		runtime:   runtime,
+		policy:    *policy,
+		onFailure: onFailure,
+	}
+
+	if rt.policy.MaxSize < 1 {
+		rt.policy.MaxSize = 1
+	}
+	if rt.policy.FlushInterval < 1 {
+		rt.policy.FlushInterval = 1
+	}
+	if rt.policy.QueueSize < 1 {
+		rt.policy.QueueSize = 10 * rt.policy.MaxSize
+	}
+
+	return rt
+}
+
+func (rt *batchRuntime) Init(ctx context.Context) error {
+	if err := rt.runtime.Init(ctx); err != nil {
+		return err
+	}
+
+	rt.queue = make(chan *models.LogRecord, rt.policy.QueueSize)
+	rt.done = make(chan struct{})
+
+	go rt.flushLoop()
+
+	return nil
+}
+
+// Close stops accepting records, delivers the ones still queued, then closes
+// the wrapped runtime.
+func (rt *batchRuntime) Close(ctx context.Context) error {
+	rt.mu.Lock()
+	started := rt.queue != nil
+	if started && !rt.closed {
+		rt.closed = true
+		close(rt.queue)
+	}
+	rt.mu.Unlock()
+
+	if started {
+		<-rt.done
+	}
+
+	return rt.runtime.Close(ctx)
+}
+
+func (rt *batchRuntime) Call(ctx context.Context, record *models.LogRecord) error {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	if rt.closed {
+		return ErrRuntimeClosed
+	}
+
+	select {
+	case rt.queue <- record:
+		metrics.AddForwarderQueueDepth(rt.name, 1)
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flushLoop accumulates queued records and delivers them whenever the batch is
+// full or the flush interval elapses, until the queue is closed and drained.
+func (rt *batchRuntime) flushLoop() {
+	defer close(rt.done)
+
+	ticker := time.NewTicker(time.Duration(rt.policy.FlushInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	batch := make([]*models.LogRecord, 0, rt.policy.MaxSize)
+
+	flush := func() {
+		if len(batch) > 0 {
+			rt.flush(batch)
+			batch = make([]*models.LogRecord, 0, rt.policy.MaxSize)
+		}
+	}
+
+	for {
+		select {
+		case record, ok := <-rt.queue:
+			if !ok {
+				flush()
+				return
+			}
+
+			metrics.AddForwarderQueueDepth(rt.name, -1)
+			batch = append(batch, record)
+
+			if len(batch) >= rt.policy.MaxSize {
+				flush()
+				ticker.Reset(time.Duration(rt.policy.FlushInterval) * time.Millisecond)
+			}
+
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// flush delivers one batch, reporting the records that failed to onFailure:
+// the whole batch, or only its rejected records on a PartialBatchError.
+func (rt *batchRuntime) flush(batch []*models.LogRecord) {
+	ctx := context.Background()
+	start := time.Now()
+
+	var failed bool
+
+	if batchRuntime, ok := rt.runtime.(BatchRuntime); ok {
+		if err := batchRuntime.CallBatch(ctx, batch); err != nil {
+			failed = true
+
+			records := batch
+			var partialErr *PartialBatchError
+			if errors.As(err, &partialErr) {
+				records = partialErr.Records
+			}
+
+			rt.onFailure(ctx, records, err)
+		}
+	} else {
+		for _, record := range batch {
+			if err := rt.runtime.Call(ctx, record); err != nil {
+				failed = true
+				rt.onFailure(ctx, []*models.LogRecord{record}, err)
+			}
+		}
+	}
+
+	metrics.ObserveForwarderFlush(rt.name, time.Since(start), !failed)
+}
@@ -0,0 +1,235 @@
+package forwarders_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"link-society.com/flowg/internal/engines/forwarders"
+	"link-society.com/flowg/internal/models"
+)
+
+// TestForwarderBatch guards against batching runtimes delivering records one
+// request at a time, exceeding their batch size, losing the records still
+// queued when closed, or dropping failed batches without reporting them.
+func TestForwarderBatch(t *testing.T) {
+	for name, bulkStatus := range map[string]int{
+		"success": http.StatusOK,
+		"failure": http.StatusInternalServerError,
+	} {
+		var (
+			mu      sync.Mutex
+			batches []int
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Elastic-Product", "Elasticsearch")
+
+			switch {
+			case r.Method == "HEAD" && r.URL.Path == "/test-index":
+				w.WriteHeader(http.StatusOK)
+
+			case r.Method == "POST" && r.URL.Path == "/test-index/_bulk":
+				lines := 0
+				scanner := bufio.NewScanner(r.Body)
+				for scanner.Scan() {
+					if strings.TrimSpace(scanner.Text()) != "" {
+						lines++
+					}
+				}
+
+				mu.Lock()
+				batches = append(batches, lines/2)
+				mu.Unlock()
+
+				w.WriteHeader(bulkStatus)
+				w.Write([]byte(`{"errors":false,"items":[]}`))
+
+			default:
+				w.WriteHeader(http.StatusNotImplemented)
+			}
+		}))
+
+		forwarder := &models.ForwarderV2{
+			Version: 2,
+			Config: models.ForwarderConfigV2{
+				Elastic: &models.ForwarderElasticV2{
+					Type:      "elastic",
+					Index:     "test-index",
+					Addresses: []string{server.URL},
+				},
+			},
+		}
+
+		runtime, err := forwarders.NewRuntime(forwarder)
+		if err != nil {
+			t.Fatalf("%s: failed to create runtime: %v", name, err)
+		}
+
+		var failed int
+		runtime = forwarders.NewBatchRuntime(
+			"test",
+			runtime,
+			&models.ForwarderBatchPolicyV2{MaxSize: 3, FlushInterval: 60000},
+			func(ctx context.Context, records []*models.LogRecord, err error) {
+				failed += len(records)
+			},
+		)
+
+		if err := runtime.Init(t.Context()); err != nil {
+			t.Fatalf("%s: failed to initialize forwarder: %v", name, err)
+		}
+
+		for range 7 {
+			record := models.NewLogRecord(map[string]string{"msg": "hello"})
+			if err := runtime.Call(t.Context(), record); err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+		}
+
+		if err := runtime.Close(t.Context()); err != nil {
+			t.Fatalf("%s: failed to close forwarder: %v", name, err)
+		}
+		server.Close()
+
+		if len(batches) != 3 || batches[0] != 3 || batches[1] != 3 || batches[2] != 1 {
+			t.Fatalf("%s: expected batches of 3, 3 and 1 records, got %v", name, batches)
+		}
+
+		expectedFailed := 0
+		if bulkStatus != http.StatusOK {
+			expectedFailed = 7
+		}
+		if failed != expectedFailed {
+			t.Fatalf("%s: expected %d failed records, got %d", name, expectedFailed, failed)
+		}
+
+		if err := runtime.Call(t.Context(), models.NewLogRecord(nil)); err != forwarders.ErrRuntimeClosed {
+			t.Fatalf("%s: expected closed runtime to refuse records, got %v", name, err)
+		}
+	}
+}
+
+// TestForwarderBatchPartialFailure guards against the records Elasticsearch
+// accepted being delivered again or reported as failed along with the ones it
+// rejected.
+func TestForwarderBatchPartialFailure(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		batches  [][]string
+		throttle = true
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+
+		switch {
+		case r.Method == "HEAD" && r.URL.Path == "/test-index":
+			w.WriteHeader(http.StatusOK)
+
+		case r.Method == "POST" && r.URL.Path == "/test-index/_bulk":
+			var (
+				messages []string
+				items    []string
+			)
+
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var doc struct {
+					Fields map[string]string `json:"fields"`
+				}
+				if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil || doc.Fields == nil {
+					continue
+				}
+
+				msg := doc.Fields["msg"]
+				messages = append(messages, msg)
+
+				status := http.StatusCreated
+				switch {
+				case msg == "throttled" && throttle:
+					status = http.StatusTooManyRequests
+				case msg == "rejected":
+					status = http.StatusBadRequest
+				}
+				items = append(items, fmt.Sprintf(`{"index":{"status":%d}}`, status))
+			}
+
+			mu.Lock()
+			batches = append(batches, messages)
+			throttle = false
+			mu.Unlock()
+
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"errors":true,"items":[%s]}`, strings.Join(items, ","))
+
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer server.Close()
+
+	forwarder := &models.ForwarderV2{
+		Version: 2,
+		Config: models.ForwarderConfigV2{
+			Elastic: &models.ForwarderElasticV2{
+				Type:      "elastic",
+				Index:     "test-index",
+				Addresses: []string{server.URL},
+			},
+		},
+		Retry: &models.ForwarderRetryPolicyV2{
+			MaxAttempts:    3,
+			InitialBackoff: 1,
+		},
+	}
+
+	runtime, err := forwarders.NewRuntime(forwarder)
+	if err != nil {
+		t.Fatalf("failed to create runtime: %v", err)
+	}
+
+	var failed []string
+	runtime = forwarders.NewBatchRuntime(
+		"test",
+		runtime,
+		&models.ForwarderBatchPolicyV2{MaxSize: 3, FlushInterval: 60000},
+		func(ctx context.Context, records []*models.LogRecord, err error) {
+			for _, record := range records {
+				failed = append(failed, record.Fields["msg"])
+			}
+		},
+	)
+
+	if err := runtime.Init(t.Context()); err != nil {
+		t.Fatalf("failed to initialize forwarder: %v", err)
+	}
+
+	for _, msg := range []string{"accepted", "throttled", "rejected"} {
+		record := models.NewLogRecord(map[string]string{"msg": msg})
+		if err := runtime.Call(t.Context(), record); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := runtime.Close(t.Context()); err != nil {
+		t.Fatalf("failed to close forwarder: %v", err)
+	}
+
+	expectedBatches := [][]string{{"accepted", "throttled", "rejected"}, {"throttled", "rejected"}}
+	if !slices.EqualFunc(batches, expectedBatches, slices.Equal) {
+		t.Fatalf("expected batches %v, got %v", expectedBatches, batches)
+	}
+
+	if !slices.Equal(failed, []string{"rejected"}) {
+		t.Fatalf("expected only the rejected record to fail, got %v", failed)
+	}
+}
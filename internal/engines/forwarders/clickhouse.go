@@ -16,15 +16,16 @@ import (
 )
 
 // clickhouseRuntime inserts records into a ClickHouse table, created at Init
-// if it does not exist.
+// if it does not exist. Batches are sent as a single batch insert.
 type clickhouseRuntime struct {
 	config *models.ForwarderClickhouseV2
 
-	conn        driver.Conn
-	insertQuery string
+	conn             driver.Conn
+	insertQuery      string
+	batchInsertQuery string
 }
 
-var _ Runtime = (*clickhouseRuntime)(nil)
+var _ BatchRuntime = (*clickhouseRuntime)(nil)
 
 const clickhouseCreateDbQuery = `
 CREATE TABLE IF NOT EXISTS %s (
@@ -39,6 +40,8 @@ INSERT INTO %s
 VALUES (?, ?, ?)
 `
 
+const clickhouseBatchInsertLogQuery = `INSERT INTO %s`
+
 func (rt *clickhouseRuntime) Init(ctx context.Context) error {
 	var tlscfg *tls.Config
 	if rt.config.UseTls {
@@ -81,6 +84,7 @@ func (rt *clickhouseRuntime) Init(ctx context.Context) error {
 
 	rt.conn = conn
 	rt.insertQuery = fmt.Sprintf(clickhouseInsertLogQuery, rt.config.Table)
+	rt.batchInsertQuery = fmt.Sprintf(clickhouseBatchInsertLogQuery, rt.config.Table)
 
 	return nil
 }
@@ -110,3 +114,33 @@ func (rt *clickhouseRuntime) Call(ctx context.Context, record *models.LogRecord)
 
 	return nil
 }
+
+func (rt *clickhouseRuntime) CallBatch(ctx context.Context, records []*models.LogRecord) error {
+	if rt.conn == nil {
+		return fmt.Errorf("clickhouse state hasn't been properly initialized")
+	}
+
+	batch, err := rt.conn.PrepareBatch(ctx, rt.batchInsertQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare batch: %w", err)
+	}
+
+	for _, record := range records {
+		pk, err := uuid.NewRandom()
+		if err != nil {
+			_ = batch.Abort()
+			return fmt.Errorf("failed to generate uuid: %w", err)
+		}
+
+		if err := batch.Append(pk, record.Timestamp, record.Fields); err != nil {
+			_ = batch.Abort()
+			return fmt.Errorf("failed to append row: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to insert rows: %w", err)
+	}
+
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 
 // datadogRuntime pushes records to the Datadog logs intake API, evaluating the
 // source, tags, hostname, message and service dynamic fields per record.
+// Batches are sent as a JSON array in a single request.
 type datadogRuntime struct {
 	config *models.ForwarderDatadogV2
 
@@ -29,7 +30,7 @@ type datadogRuntime struct {
 	serviceProg  *vm.Program
 }
 
-var _ Runtime = (*datadogRuntime)(nil)
+var _ BatchRuntime = (*datadogRuntime)(nil)
 
 func (rt *datadogRuntime) Init(ctx context.Context) error {
 	var err error
@@ -90,6 +91,31 @@ func (rt *datadogRuntime) Close(context.Context) error {
 }
 
 func (rt *datadogRuntime) Call(ctx context.Context, record *models.LogRecord) error {
+	entry, err := rt.entry(record)
+	if err != nil {
+		return err
+	}
+
+	return rt.send(ctx, entry)
+}
+
+func (rt *datadogRuntime) CallBatch(ctx context.Context, records []*models.LogRecord) error {
+	entries := make([]map[string]any, 0, len(records))
+
+	for _, record := range records {
+		entry, err := rt.entry(record)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	return rt.send(ctx, entries)
+}
+
+// entry evaluates the dynamic fields against a record and returns the Datadog
+// log entry to send.
+func (rt *datadogRuntime) entry(record *models.LogRecord) (map[string]any, error) {
 	env := map[string]any{
 		"timestamp": record.Timestamp,
 		"log":       record.Fields,
@@ -109,38 +135,41 @@ func (rt *datadogRuntime) Call(ctx context.Context, record *models.LogRecord) er
 
 	ddsource, err := eval(rt.ddsourceProg, "ddsource")
 	if err != nil {
-		return fmt.Errorf("failed to evaluate `ddsource` record: %w", err)
+		return nil, fmt.Errorf("failed to evaluate `ddsource` record: %w", err)
 	}
 
 	ddtags, err := eval(rt.ddtagsProg, "ddtags")
 	if err != nil {
-		return fmt.Errorf("failed to evaluate `ddtags` record: %w", err)
+		return nil, fmt.Errorf("failed to evaluate `ddtags` record: %w", err)
 	}
 
 	hostname, err := eval(rt.hostnameProg, "hostname")
 	if err != nil {
-		return fmt.Errorf("failed to evaluate `hostname` record: %w", err)
+		return nil, fmt.Errorf("failed to evaluate `hostname` record: %w", err)
 	}
 
 	message, err := eval(rt.messageProg, "message")
 	if err != nil {
-		return fmt.Errorf("failed to evaluate `message` record: %w", err)
+		return nil, fmt.Errorf("failed to evaluate `message` record: %w", err)
 	}
 
 	service, err := eval(rt.serviceProg, "service")
 	if err != nil {
-		return fmt.Errorf("failed to evaluate `service` record: %w", err)
+		return nil, fmt.Errorf("failed to evaluate `service` record: %w", err)
 	}
 
-	rec := map[string]any{
+	return map[string]any{
 		"timestamp": record.Timestamp,
 		"ddsource":  ddsource,
 		"ddtags":    ddtags,
 		"hostname":  hostname,
 		"message":   message,
 		"service":   service,
-	}
+	}, nil
+}
 
+// send posts a log entry, or an array of entries, to the intake API.
+func (rt *datadogRuntime) send(ctx context.Context, rec any) error {
 	payload, err := json.Marshal(rec)
 	if err != nil {
 		return fmt.Errorf("failed to marshal log record: %w", err)
@@ -166,7 +195,7 @@ func (rt *datadogRuntime) Call(ctx context.Context, record *models.LogRecord) er
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return &StatusCodeError{StatusCode: resp.StatusCode}
 	}
 
 	return nil
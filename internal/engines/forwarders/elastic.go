@@ -13,14 +13,15 @@ import (
 )
 
 // elasticRuntime indexes records into an Elasticsearch index, creating the
-// index on first delivery if it does not exist.
+// index on first delivery if it does not exist. Batches are sent through the
+// _bulk API, whose rejected documents are reported as a PartialBatchError.
 type elasticRuntime struct {
 	config *models.ForwarderElasticV2
 
 	client *elasticsearch.Client
 }
 
-var _ Runtime = (*elasticRuntime)(nil)
+var _ BatchRuntime = (*elasticRuntime)(nil)
 
 func (rt *elasticRuntime) Init(context.Context) error {
 	opts := []elasticsearch.Option{
@@ -48,6 +49,115 @@ func (rt *elasticRuntime) Close(context.Context) error {
 }
 
 func (rt *elasticRuntime) Call(ctx context.Context, record *models.LogRecord) error {
+	if err := rt.ensureIndex(ctx); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log record: %w", err)
+	}
+	data := bytes.NewReader(payload)
+
+	resp, err := rt.client.Index(rt.config.Index, data, rt.client.Index.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to send ElasticSearch request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf(
+			"failed to index log record: %w",
+			&StatusCodeError{StatusCode: resp.StatusCode, Body: resp.String()},
+		)
+	}
+
+	return nil
+}
+
+func (rt *elasticRuntime) CallBatch(ctx context.Context, records []*models.LogRecord) error {
+	if err := rt.ensureIndex(ctx); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	for _, record := range records {
+		payload, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal log record: %w", err)
+		}
+
+		body.WriteString(`{"index":{}}` + "\n")
+		body.Write(payload)
+		body.WriteString("\n")
+	}
+
+	resp, err := rt.client.Bulk(
+		&body,
+		rt.client.Bulk.WithIndex(rt.config.Index),
+		rt.client.Bulk.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to send ElasticSearch request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf(
+			"failed to index log records: %w",
+			&StatusCodeError{StatusCode: resp.StatusCode, Body: resp.String()},
+		)
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode ElasticSearch response: %w", err)
+	}
+
+	if !result.Errors {
+		return nil
+	}
+
+	if len(result.Items) != len(records) {
+		return fmt.Errorf(
+			"failed to index log records: expected %d bulk results, got %d",
+			len(records),
+			len(result.Items),
+		)
+	}
+
+	// Items are in request order: only the rejected documents are reported, the
+	// status of the first one telling whether retrying is worth it.
+	partialErr := &PartialBatchError{}
+	for i, item := range result.Items {
+		for _, action := range item {
+			if action.Status < 200 || action.Status >= 300 {
+				partialErr.Records = append(partialErr.Records, records[i])
+				if partialErr.Err == nil {
+					partialErr.Err = fmt.Errorf(
+						"failed to index log records: %w",
+						&StatusCodeError{StatusCode: action.Status, Body: string(action.Error)},
+					)
+				}
+			}
+		}
+	}
+
+	if len(partialErr.Records) == 0 {
+		return fmt.Errorf("failed to index log records")
+	}
+
+	return partialErr
+}
+
+// ensureIndex creates the configured index if it does not exist yet.
+func (rt *elasticRuntime) ensureIndex(ctx context.Context) error {
 	resp, err := rt.client.Indices.Exists([]string{rt.config.Index}, rt.client.Indices.Exists.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to send ElasticSearch request: %w", err)
@@ -70,21 +180,5 @@ func (rt *elasticRuntime) Call(ctx context.Context, record *models.LogRecord) er
 		}
 	}
 
-	payload, err := json.Marshal(record)
-	if err != nil {
-		return fmt.Errorf("failed to marshal log record: %w", err)
-	}
-	data := bytes.NewReader(payload)
-
-	resp, err = rt.client.Index(rt.config.Index, data, rt.client.Index.WithContext(ctx))
-	if err != nil {
-		return fmt.Errorf("failed to send ElasticSearch request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.IsError() {
-		return fmt.Errorf("failed to index log record: %s", resp.String())
-	}
-
 	return nil
 }
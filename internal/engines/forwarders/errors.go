@@ -0,0 +1,60 @@
+package forwarders
+
+import (
+	"fmt"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// StatusCodeError is returned by the HTTP-based backends when the destination
+// answers with an unexpected status code, so that retry policies can tell
+// transient failures (429, 503, ...) from permanent ones.
+type StatusCodeError struct {
+	StatusCode int
+	Body       string
+}
+
+var _ error = (*StatusCodeError)(nil)
+
+func (e *StatusCodeError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("unexpected status code: %d, body: %s", e.StatusCode, e.Body)
+	}
+
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// PartialBatchError is returned by CallBatch when the destination accepted only
+// part of a batch. Records holds the rejected records, in batch order, so that
+// only they are retried and reported; Err is the error of the first of them.
+type PartialBatchError struct {
+	Records []*models.LogRecord
+	Err     error
+}
+
+var _ error = (*PartialBatchError)(nil)
+
+func (e *PartialBatchError) Error() string {
+	return fmt.Sprintf("%d record(s) of the batch failed: %v", len(e.Records), e.Err)
+}
+
+func (e *PartialBatchError) Unwrap() error {
+	return e.Err
+}
+
+// DeliveryError is returned by a runtime once its retry policy is exhausted,
+// wrapping the error of the last attempt.
+type DeliveryError struct {
+	Attempts int
+	Err      error
+}
+
+var _ error = (*DeliveryError)(nil)
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("delivery failed after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
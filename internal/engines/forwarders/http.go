@@ -110,7 +110,7 @@ func (rt *httpRuntime) Call(ctx context.Context, record *models.LogRecord) error
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return &StatusCodeError{StatusCode: resp.StatusCode}
 	}
 
 	return nil
@@ -28,9 +28,36 @@ type Runtime interface {
 	Call(ctx context.Context, record *models.LogRecord) error
 }
 
+// BatchRuntime is implemented by the runtimes of backends offering a bulk API:
+// CallBatch delivers several records at once, in a single request or insert.
+// The batch fails or succeeds as a whole, unless the backend reports which
+// records were rejected with a [PartialBatchError].
+type BatchRuntime interface {
+	Runtime
+	CallBatch(ctx context.Context, records []*models.LogRecord) error
+}
+
 // NewRuntime returns the Runtime implementation matching the configuration's
-// tagged union, or ErrNotImplemented when no backend is selected.
+// tagged union, or ErrNotImplemented when no backend is selected. The runtime
+// retries failed deliveries according to the configuration's retry policy, and
+// implements BatchRuntime when the backend does.
 func NewRuntime(cfg *models.ForwarderV2) (Runtime, error) {
+	runtime, err := newBackendRuntime(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := newRetryRuntime(runtime, cfg.Retry)
+	if _, ok := runtime.(BatchRuntime); ok {
+		return &retryBatchRuntime{retryRuntime: retry}, nil
+	}
+
+	return retry, nil
+}
+
+// newBackendRuntime returns the Runtime implementation of the backend selected
+// by the configuration's tagged union.
+func newBackendRuntime(cfg *models.ForwarderV2) (Runtime, error) {
 	switch {
 	case cfg.Config.Http != nil:
 		return &httpRuntime{config: cfg.Config.Http}, nil
@@ -0,0 +1,15 @@
+package forwarders_test
+
+import (
+	"os"
+	"testing"
+
+	"link-society.com/flowg/internal/app/metrics"
+)
+
+func TestMain(m *testing.M) {
+	// Batching runtimes feed the global metrics, which the server sets up on
+	// startup.
+	metrics.Setup()
+	os.Exit(m.Run())
+}
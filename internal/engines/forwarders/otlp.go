@@ -103,7 +103,7 @@ func (rt *otlpRuntime) Call(ctx context.Context, record *models.LogRecord) error
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+		return &StatusCodeError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	return nil
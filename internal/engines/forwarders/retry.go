@@ -0,0 +1,150 @@
+package forwarders
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// defaultRetryableStatusCodes are the status codes retried when a retry policy
+// does not list its own.
+var defaultRetryableStatusCodes = []int{408, 429, 500, 502, 503, 504}
+
+const (
+	// defaultRetryBackoff is the delay before the first retry when a retry
+	// policy does not set one.
+	defaultRetryBackoff = 100 * time.Millisecond
+	// minRetryBackoff is the shortest delay between two attempts, so that a
+	// failing destination is never retried in a tight loop.
+	minRetryBackoff = 10 * time.Millisecond
+)
+
+// retryRuntime wraps a backend runtime with the forwarder's retry policy. Every
+// runtime returned by NewRuntime is wrapped, so that failed deliveries are
+// always reported as a DeliveryError carrying the number of attempts.
+type retryRuntime struct {
+	runtime Runtime
+	policy  models.ForwarderRetryPolicyV2
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+var _ Runtime = (*retryRuntime)(nil)
+
+func newRetryRuntime(runtime Runtime, policy *models.ForwarderRetryPolicyV2) *retryRuntime {
+	rt := &retryRuntime{
+		runtime: runtime,
+		policy:  models.ForwarderRetryPolicyV2{MaxAttempts: 1},
+	}
+
+	if policy != nil {
+		rt.policy = *policy
+	}
+	if rt.policy.MaxAttempts < 1 {
+		rt.policy.MaxAttempts = 1
+	}
+	if len(rt.policy.RetryableStatusCodes) == 0 {
+		rt.policy.RetryableStatusCodes = defaultRetryableStatusCodes
+	}
+
+	rt.initialBackoff = time.Duration(rt.policy.InitialBackoff) * time.Millisecond
+	if rt.initialBackoff <= 0 {
+		rt.initialBackoff = defaultRetryBackoff
+	}
+	rt.initialBackoff = max(rt.initialBackoff, minRetryBackoff)
+
+	rt.maxBackoff = time.Duration(rt.policy.MaxBackoff) * time.Millisecond
+	if rt.maxBackoff > 0 {
+		rt.maxBackoff = max(rt.maxBackoff, minRetryBackoff)
+	}
+
+	return rt
+}
+
+func (rt *retryRuntime) Init(ctx context.Context) error {
+	return rt.runtime.Init(ctx)
+}
+
+func (rt *retryRuntime) Close(ctx context.Context) error {
+	return rt.runtime.Close(ctx)
+}
+
+func (rt *retryRuntime) Call(ctx context.Context, record *models.LogRecord) error {
+	return rt.retry(ctx, func() error {
+		return rt.runtime.Call(ctx, record)
+	})
+}
+
+// retry runs call until it succeeds, fails with a non-retryable error, or the
+// policy's attempts are exhausted, waiting between attempts.
+func (rt *retryRuntime) retry(ctx context.Context, call func() error) error {
+	backoff := rt.initialBackoff
+	maxBackoff := rt.maxBackoff
+
+	attempt := 1
+	for {
+		err := call()
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= rt.policy.MaxAttempts || !rt.isRetryable(err) {
+			return &DeliveryError{Attempts: attempt, Err: err}
+		}
+
+		select {
+		case <-ctx.Done():
+			return &DeliveryError{Attempts: attempt, Err: err}
+
+		case <-time.After(backoff):
+		}
+
+		attempt++
+		backoff *= 2
+		if maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// retryBatchRuntime is the retryRuntime of a backend implementing
+// BatchRuntime: batches are retried as a whole, or only their rejected records
+// when the backend reports a PartialBatchError.
+type retryBatchRuntime struct {
+	*retryRuntime
+}
+
+var _ BatchRuntime = (*retryBatchRuntime)(nil)
+
+func (rt *retryBatchRuntime) CallBatch(ctx context.Context, records []*models.LogRecord) error {
+	return rt.retry(ctx, func() error {
+		err := rt.runtime.(BatchRuntime).CallBatch(ctx, records)
+
+		var partialErr *PartialBatchError
+		if errors.As(err, &partialErr) {
+			records = partialErr.Records
+		}
+
+		return err
+	})
+}
+
+// isRetryable reports whether a failed attempt may succeed if tried again:
+// status codes must be listed by the policy, cancellations are final, and any
+// other error (connection reset, timeout, ...) is assumed to be transient.
+func (rt *retryRuntime) isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *StatusCodeError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(rt.policy.RetryableStatusCodes, statusErr.StatusCode)
+	}
+
+	return true
+}
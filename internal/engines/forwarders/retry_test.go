@@ -0,0 +1,113 @@
+package forwarders_test
+
+import (
+	"errors"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"link-society.com/flowg/internal/engines/forwarders"
+	"link-society.com/flowg/internal/models"
+)
+
+// TestForwarderRetry guards against transient failures being reported without
+// retrying, permanent ones being retried, and the attempts count of a failed
+// delivery being lost.
+func TestForwarderRetry(t *testing.T) {
+	for name, tc := range map[string]struct {
+		statuses []int
+		attempts int32
+		success  bool
+	}{
+		"recovers":   {[]int{503, 429, 200}, 3, true},
+		"exhausted":  {[]int{503, 503, 503, 503}, 3, false},
+		"permanent":  {[]int{400, 200}, 1, false},
+		"first-shot": {[]int{200}, 1, true},
+	} {
+		var calls atomic.Int32
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			i := calls.Add(1) - 1
+			w.WriteHeader(tc.statuses[i])
+		}))
+
+		forwarder := &models.ForwarderV2{
+			Version: 2,
+			Config: models.ForwarderConfigV2{
+				Http: &models.ForwarderHttpV2{Url: testServer.URL},
+			},
+			Retry: &models.ForwarderRetryPolicyV2{
+				MaxAttempts:    3,
+				InitialBackoff: 1,
+				MaxBackoff:     2,
+			},
+		}
+
+		runtime, err := forwarders.NewRuntime(forwarder)
+		if err != nil {
+			t.Fatalf("%s: failed to create runtime: %v", name, err)
+		}
+		if err := runtime.Init(t.Context()); err != nil {
+			t.Fatalf("%s: failed to initialize forwarder: %v", name, err)
+		}
+
+		err = runtime.Call(t.Context(), models.NewLogRecord(map[string]string{}))
+		testServer.Close()
+
+		if tc.success && err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !tc.success {
+			var deliveryErr *forwarders.DeliveryError
+			if !errors.As(err, &deliveryErr) {
+				t.Fatalf("%s: expected a delivery error, got %v", name, err)
+			}
+			if deliveryErr.Attempts != int(tc.attempts) {
+				t.Fatalf("%s: expected %d attempts to be reported, got %d", name, tc.attempts, deliveryErr.Attempts)
+			}
+		}
+		if calls.Load() != tc.attempts {
+			t.Fatalf("%s: expected %d attempts, got %d", name, tc.attempts, calls.Load())
+		}
+	}
+}
+
+// TestForwarderRetryDefaultBackoff guards against a policy without an initial
+// backoff retrying a failing destination without any delay.
+func TestForwarderRetryDefaultBackoff(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	}))
+	defer testServer.Close()
+
+	forwarder := &models.ForwarderV2{
+		Version: 2,
+		Config: models.ForwarderConfigV2{
+			Http: &models.ForwarderHttpV2{Url: testServer.URL},
+		},
+		Retry: &models.ForwarderRetryPolicyV2{MaxAttempts: 3},
+	}
+
+	runtime, err := forwarders.NewRuntime(forwarder)
+	if err != nil {
+		t.Fatalf("failed to create runtime: %v", err)
+	}
+	if err := runtime.Init(t.Context()); err != nil {
+		t.Fatalf("failed to initialize forwarder: %v", err)
+	}
+
+	start := time.Now()
+	err = runtime.Call(t.Context(), models.NewLogRecord(map[string]string{}))
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatalf("expected the delivery to fail")
+	}
+
+	// 100ms before the second attempt, then 200ms before the third.
+	if elapsed < 300*time.Millisecond {
+		t.Fatalf("expected the attempts to be spaced out, took %s", elapsed)
+	}
+}
@@ -15,7 +15,8 @@ import (
 )
 
 // splunkRuntime pushes records to a Splunk HTTP Event Collector, evaluating
-// the source and host dynamic fields per record.
+// the source and host dynamic fields per record. Batches are sent as
+// concatenated events in a single request.
 type splunkRuntime struct {
 	config *models.ForwarderSplunkV2
 
@@ -25,7 +26,7 @@ type splunkRuntime struct {
 	host   *vm.Program
 }
 
-var _ Runtime = (*splunkRuntime)(nil)
+var _ BatchRuntime = (*splunkRuntime)(nil)
 
 func (rt *splunkRuntime) Init(context.Context) error {
 	var err error
@@ -58,6 +59,32 @@ func (rt *splunkRuntime) Close(context.Context) error {
 }
 
 func (rt *splunkRuntime) Call(ctx context.Context, record *models.LogRecord) error {
+	event, err := rt.event(record)
+	if err != nil {
+		return err
+	}
+
+	return rt.send(ctx, event)
+}
+
+func (rt *splunkRuntime) CallBatch(ctx context.Context, records []*models.LogRecord) error {
+	var body []byte
+
+	// HEC accepts several events in one request, as concatenated JSON objects.
+	for _, record := range records {
+		event, err := rt.event(record)
+		if err != nil {
+			return err
+		}
+		body = append(body, event...)
+	}
+
+	return rt.send(ctx, body)
+}
+
+// event evaluates the dynamic fields against a record and returns the encoded
+// HEC event to send.
+func (rt *splunkRuntime) event(record *models.LogRecord) ([]byte, error) {
 	env := map[string]any{
 		"timestamp": record.Timestamp,
 		"log":       record.Fields,
@@ -77,12 +104,12 @@ func (rt *splunkRuntime) Call(ctx context.Context, record *models.LogRecord) err
 
 	source, err := eval(rt.source, "source")
 	if err != nil {
-		return fmt.Errorf("failed to evaluate `source` record: %w", err)
+		return nil, fmt.Errorf("failed to evaluate `source` record: %w", err)
 	}
 
 	host, err := eval(rt.host, "host")
 	if err != nil {
-		return fmt.Errorf("failed to evaluate `host` record: %w", err)
+		return nil, fmt.Errorf("failed to evaluate `host` record: %w", err)
 	}
 
 	// Convert map[string]string to map[string]interface{}
@@ -108,9 +135,14 @@ func (rt *splunkRuntime) Call(ctx context.Context, record *models.LogRecord) err
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal log record: %w", err)
+		return nil, fmt.Errorf("failed to marshal log record: %w", err)
 	}
 
+	return jsonData, nil
+}
+
+// send posts encoded HEC events to the collector.
+func (rt *splunkRuntime) send(ctx context.Context, jsonData []byte) error {
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", rt.config.Endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -128,7 +160,8 @@ func (rt *splunkRuntime) Call(ctx context.Context, record *models.LogRecord) err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code from Splunk: %d", resp.StatusCode)
+		_ = resp.Body.Close()
+		return fmt.Errorf("unexpected response from Splunk: %w", &StatusCodeError{StatusCode: resp.StatusCode})
 	}
 
 	var result struct {
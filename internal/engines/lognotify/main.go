@@ -79,6 +79,7 @@ func NewLogNotifier() fx.Option {
 					subscribers: make(map[string]subscriberSet),
 					subMbox:     subMbox,
 					logMbox:     logMbox,
+					unsubC:      make(chan SubscribeMessage),
 				}),
 
 				subMbox: subMbox,
@@ -16,14 +16,15 @@ type worker struct {
 	subscribers map[string]subscriberSet
 	subMbox     actor.MailboxReceiver[SubscribeMessage]
 	logMbox     actor.MailboxReceiver[LogMessage]
+	unsubC      chan SubscribeMessage
 }
 
 var _ actor.Worker = (*worker)(nil)
 
-// DoWork handles one subscription or one broadcast per invocation: it registers
-// new subscribers (wiring their teardown to DoneC) and fans incoming records out
-// to every subscriber of the target stream, logging — but not failing on —
-// individual delivery errors.
+// DoWork handles one subscription, unsubscription or broadcast per invocation:
+// it registers new subscribers (wiring their teardown to DoneC), drops the ones
+// whose DoneC fired, and fans incoming records out to every subscriber of the
+// target stream, logging — but not failing on — individual delivery errors.
 func (w *worker) DoWork(ctx actor.Context) actor.WorkerStatus {
 	select {
 	case <-ctx.Done():
@@ -45,9 +46,15 @@ func (w *worker) DoWork(ctx actor.Context) actor.WorkerStatus {
 		w.subscribers[msg.Stream][msg.SenderM] = struct{}{}
 
 		go func() {
-			<-msg.DoneC
-			delete(w.subscribers[msg.Stream], msg.SenderM)
-			msg.SenderM.Stop()
+			select {
+			case <-msg.DoneC:
+				select {
+				case w.unsubC <- msg:
+				case <-ctx.Done():
+				}
+
+			case <-ctx.Done():
+			}
 		}()
 
 		go func() {
@@ -57,6 +64,12 @@ func (w *worker) DoWork(ctx actor.Context) actor.WorkerStatus {
 
 		return actor.WorkerContinue
 
+	case msg := <-w.unsubC:
+		delete(w.subscribers[msg.Stream], msg.SenderM)
+		msg.SenderM.Stop()
+
+		return actor.WorkerContinue
+
 	case msg, ok := <-w.logMbox.ReceiveC():
 		if !ok {
 			return actor.WorkerEnd
@@ -0,0 +1,81 @@
+package pipelines_test
+
+import (
+	"testing"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/storage/mocks"
+
+	"link-society.com/flowg/internal/engines/pipelines"
+)
+
+// TestCaseFirstMatchWins guards against a case node sending a record to more
+// than one output, evaluating its conditions out of order, or dropping the
+// records that match none of them instead of using the default output.
+func TestCaseFirstMatchWins(t *testing.T) {
+	flow := &models.FlowGraphV2{
+		Nodes: []*models.FlowNodeV2{
+			{ID: "source", Type: "source", Data: map[string]string{}},
+			{
+				ID:   "case",
+				Type: "case",
+				Data: map[string]string{
+					"conditions": `["level == \"error\"", "level in [\"error\", \"warn\"]", "level == \"info\""]`,
+				},
+			},
+			{ID: "errors", Type: "router", Data: map[string]string{"stream": "errors"}},
+			{ID: "warnings", Type: "router", Data: map[string]string{"stream": "warnings"}},
+			{ID: "infos", Type: "router", Data: map[string]string{"stream": "infos"}},
+			{ID: "others", Type: "router", Data: map[string]string{"stream": "others"}},
+		},
+		Edges: []*models.FlowEdgeV2{
+			{ID: "e1", Source: "source", Target: "case"},
+			{ID: "e2", Source: "case", SourceHandle: "0", Target: "errors"},
+			{ID: "e3", Source: "case", SourceHandle: "1", Target: "warnings"},
+			{ID: "e4", Source: "case", SourceHandle: "2", Target: "infos"},
+			{ID: "e5", Source: "case", SourceHandle: pipelines.CASE_DEFAULT_HANDLE, Target: "others"},
+		},
+	}
+
+	pipeline, err := pipelines.BuildFlow(t.Context(), mocks.NewMockConfigStorage(), "test", flow)
+	if err != nil {
+		t.Fatalf("failed to build flow: %v", err)
+	}
+	if err := pipeline.Init(t.Context()); err != nil {
+		t.Fatalf("failed to init pipeline: %v", err)
+	}
+	defer pipeline.Close(t.Context())
+
+	for level, expected := range map[string]struct {
+		branch string
+		target string
+	}{
+		"error": {"0", "errors"},
+		"warn":  {"1", "warnings"},
+		"info":  {"2", "infos"},
+		"debug": {pipelines.CASE_DEFAULT_HANDLE, "others"},
+	} {
+		tracer := &pipelines.NodeTracer{Flow: *flow}
+		ctx := pipelines.WithTracer(t.Context(), tracer)
+
+		record := models.NewLogRecord(map[string]string{"level": level})
+		if err := pipeline.Process(ctx, pipelines.DIRECT_ENTRYPOINT, record); err != nil {
+			t.Fatalf("%s: failed to process record: %v", level, err)
+		}
+
+		visited := map[string]models.PipelineNodeTrace{}
+		for _, trace := range tracer.Trace {
+			visited[trace.NodeID] = trace
+		}
+
+		if branch := visited["case"].Branch; branch != expected.branch {
+			t.Fatalf("%s: expected branch %q, got %q", level, expected.branch, branch)
+		}
+		if _, exists := visited[expected.target]; !exists {
+			t.Fatalf("%s: expected record to reach %s, got %v", level, expected.target, tracer.Trace)
+		}
+		if len(visited) != 3 {
+			t.Fatalf("%s: expected 3 traced nodes, got %v", level, tracer.Trace)
+		}
+	}
+}
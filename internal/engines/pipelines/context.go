@@ -9,8 +9,15 @@ type pipelineCtxKey string
 const (
 	workerKey   pipelineCtxKey = "worker"
 	pipelineKey pipelineCtxKey = "pipeline"
+	depthKey    pipelineCtxKey = "depth"
 )
 
+// MAX_PIPELINE_DEPTH bounds how many pipeline nodes a single record may go
+// through before being rejected. Cycles are refused when saving and building
+// pipelines; this limit only guards against one slipping through (e.g. a
+// pipeline written directly to storage).
+const MAX_PIPELINE_DEPTH = 32
+
 // getWorker retrieves the runner worker stashed in the context by the actor when
 // it begins handling a logMessage; nodes use it to reach storage and the cache.
 func getWorker(ctx context.Context) *worker {
@@ -22,3 +29,15 @@ func getWorker(ctx context.Context) *worker {
 func getPipeline(ctx context.Context) *Pipeline {
 	return ctx.Value(pipelineKey).(*Pipeline)
 }
+
+// getPipelineDepth returns how many pipeline nodes the record being processed
+// has gone through so far.
+func getPipelineDepth(ctx context.Context) int {
+	depth, _ := ctx.Value(depthKey).(int)
+	return depth
+}
+
+// withPipelineDepth returns a context recording the given pipeline depth.
+func withPipelineDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, depthKey, depth)
+}
@@ -0,0 +1,80 @@
+package pipelines
+
+import (
+	"context"
+	"slices"
+
+	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// CheckPipelineCycles walks the pipelines referenced by flowGraph, reading
+// them from storage, and returns a [PipelineCycleError] if any of them leads
+// back to a pipeline already on the path, including name itself. The given
+// flow graph is used in place of the stored one for name, so that a pipeline
+// can be checked before being saved. Referenced pipelines that do not exist are
+// ignored: they are reported when the record reaches them.
+func CheckPipelineCycles(
+	ctx context.Context,
+	configStorage storage.ConfigStorage,
+	name string,
+	flowGraph *models.FlowGraphV2,
+) error {
+	checked := map[string]bool{}
+	return checkPipelineCycles(ctx, configStorage, []string{name}, flowGraph, checked)
+}
+
+func checkPipelineCycles(
+	ctx context.Context,
+	configStorage storage.ConfigStorage,
+	path []string,
+	flowGraph *models.FlowGraphV2,
+	checked map[string]bool,
+) error {
+	for _, ref := range referencedPipelines(flowGraph) {
+		if i := slices.Index(path, ref); i >= 0 {
+			cycle := append(slices.Clone(path[i:]), ref)
+			return &PipelineCycleError{Path: cycle}
+		}
+
+		if checked[ref] {
+			continue
+		}
+
+		refGraph, err := configStorage.ReadPipeline(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if refGraph == nil {
+			continue
+		}
+
+		subpath := append(slices.Clone(path), ref)
+		if err := checkPipelineCycles(ctx, configStorage, subpath, refGraph, checked); err != nil {
+			return err
+		}
+
+		checked[ref] = true
+	}
+
+	return nil
+}
+
+// referencedPipelines returns the sorted, deduplicated names of the pipelines
+// called by the pipeline nodes of flowGraph.
+func referencedPipelines(flowGraph *models.FlowGraphV2) []string {
+	var refs []string
+
+	for _, flowNode := range flowGraph.Nodes {
+		if flowNode.Type != "pipeline" {
+			continue
+		}
+
+		if ref, exists := flowNode.Data["pipeline"]; exists {
+			refs = append(refs, ref)
+		}
+	}
+
+	slices.Sort(refs)
+	return slices.Compact(refs)
+}
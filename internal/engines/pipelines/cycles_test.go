@@ -0,0 +1,58 @@
+package pipelines_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/storage/mocks"
+
+	"link-society.com/flowg/internal/engines/pipelines"
+)
+
+func callingPipeline(name string) *models.FlowGraphV2 {
+	return &models.FlowGraphV2{
+		Nodes: []*models.FlowNodeV2{
+			{ID: "source", Type: "source", Data: map[string]string{}},
+			{ID: "call", Type: "pipeline", Data: map[string]string{"pipeline": name}},
+		},
+		Edges: []*models.FlowEdgeV2{
+			{ID: "e1", Source: "source", Target: "call"},
+		},
+	}
+}
+
+// TestPipelineCycles guards against pipelines calling each other, directly or
+// through other pipelines, being accepted and recursing forever at runtime.
+func TestPipelineCycles(t *testing.T) {
+	configStorage := mocks.NewMockConfigStorage().(*mocks.MockConfigStorage)
+	configStorage.On("ReadPipeline", mock.Anything, "b").Return(callingPipeline("c"), nil)
+	configStorage.On("ReadPipeline", mock.Anything, "c").Return(callingPipeline("a"), nil)
+	configStorage.On("ReadPipeline", mock.Anything, "d").Return((*models.FlowGraphV2)(nil), nil)
+
+	for name, expected := range map[string][]string{
+		"a": {"a", "a"},
+		"b": {"a", "b", "c", "a"},
+		"d": nil,
+	} {
+		_, err := pipelines.BuildFlow(t.Context(), configStorage, "a", callingPipeline(name))
+
+		var cycleErr *pipelines.PipelineCycleError
+		if expected == nil {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			continue
+		}
+
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("%s: expected a cycle error, got %v", name, err)
+		}
+		if !slices.Equal(cycleErr.Path, expected) {
+			t.Fatalf("%s: expected cycle %v, got %v", name, expected, cycleErr.Path)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package pipelines
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type InvalidFlowNodeTypeError struct {
 	Type string
@@ -23,6 +26,22 @@ func (e *MissingFlowNodeDataError) Error() string {
 	return fmt.Sprintf("missing data key %s for node %s", e.Key, e.NodeID)
 }
 
+type InvalidFlowNodeDataError struct {
+	NodeID string
+	Key    string
+	Err    error
+}
+
+var _ error = (*InvalidFlowNodeDataError)(nil)
+
+func (e *InvalidFlowNodeDataError) Error() string {
+	return fmt.Sprintf("invalid data key %s for node %s: %v", e.Key, e.NodeID, e.Err)
+}
+
+func (e *InvalidFlowNodeDataError) Unwrap() error {
+	return e.Err
+}
+
 type InvalidFlowEdgeError struct {
 	Source string
 	Target string
@@ -34,6 +53,17 @@ func (e *InvalidFlowEdgeError) Error() string {
 	return fmt.Sprintf("invalid edge: %s -> %s", e.Source, e.Target)
 }
 
+type InvalidFlowEdgeHandleError struct {
+	Source string
+	Handle string
+}
+
+var _ error = (*InvalidFlowEdgeHandleError)(nil)
+
+func (e *InvalidFlowEdgeHandleError) Error() string {
+	return fmt.Sprintf("invalid output handle %s for node %s", e.Handle, e.Source)
+}
+
 type InvalidEntrypointError struct {
 	Entrypoint string
 }
@@ -53,3 +83,39 @@ var _ error = (*PipelineNotFoundError)(nil)
 func (e *PipelineNotFoundError) Error() string {
 	return fmt.Sprintf("pipeline not found: %s", e.Pipeline)
 }
+
+type PipelineCycleError struct {
+	Path []string
+}
+
+var _ error = (*PipelineCycleError)(nil)
+
+func (e *PipelineCycleError) Error() string {
+	return fmt.Sprintf("pipeline cycle detected: %s", strings.Join(e.Path, " -> "))
+}
+
+type PipelineDepthExceededError struct {
+	Pipeline string
+	Depth    int
+}
+
+var _ error = (*PipelineDepthExceededError)(nil)
+
+func (e *PipelineDepthExceededError) Error() string {
+	return fmt.Sprintf("maximum pipeline depth %d exceeded while calling pipeline %s", e.Depth, e.Pipeline)
+}
+
+type InvalidTransformTimestampError struct {
+	NodeID string
+	Err    error
+}
+
+var _ error = (*InvalidTransformTimestampError)(nil)
+
+func (e *InvalidTransformTimestampError) Error() string {
+	return fmt.Sprintf("invalid %s emitted by node %s: %v", TRANSFORM_TIMESTAMP_FIELD, e.NodeID, e.Err)
+}
+
+func (e *InvalidTransformTimestampError) Unwrap() error {
+	return e.Err
+}
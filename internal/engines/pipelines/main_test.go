@@ -0,0 +1,15 @@
+package pipelines_test
+
+import (
+	"os"
+	"testing"
+
+	"link-society.com/flowg/internal/app/metrics"
+)
+
+func TestMain(m *testing.M) {
+	// Pipeline.Process feeds the global metrics, which the server sets up on
+	// startup.
+	metrics.Setup()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,34 @@
+package pipelines_test
+
+import (
+	"errors"
+	"testing"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/storage/mocks"
+
+	"link-society.com/flowg/internal/engines/pipelines"
+)
+
+// TestRouterRejectsAuditStream guards against pipelines forging audit events by
+// routing logs to the reserved audit stream.
+func TestRouterRejectsAuditStream(t *testing.T) {
+	configStorage := mocks.NewMockConfigStorage()
+
+	flow := &models.FlowGraphV2{
+		Nodes: []*models.FlowNodeV2{
+			{ID: "source", Type: "source", Data: map[string]string{}},
+			{ID: "router", Type: "router", Data: map[string]string{"stream": models.AUDIT_STREAM}},
+		},
+		Edges: []*models.FlowEdgeV2{
+			{ID: "e1", Source: "source", Target: "router"},
+		},
+	}
+
+	_, err := pipelines.BuildFlow(t.Context(), configStorage, "forge", flow)
+
+	var dataErr *pipelines.InvalidFlowNodeDataError
+	if !errors.As(err, &dataErr) || dataErr.NodeID != "router" {
+		t.Fatalf("expected the router node to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package pipelines_test
+
+import (
+	"errors"
+	"testing"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/storage/mocks"
+
+	"link-society.com/flowg/internal/engines/pipelines"
+)
+
+// TestSwitchElseBranch guards against records that miss a switch condition
+// being dropped instead of reaching the else output, and against the dry-run
+// trace losing track of which output each record took.
+func TestSwitchElseBranch(t *testing.T) {
+	flow := &models.FlowGraphV2{
+		Nodes: []*models.FlowNodeV2{
+			{ID: "source", Type: "source", Data: map[string]string{}},
+			{ID: "switch", Type: "switch", Data: map[string]string{"condition": `level == "error"`}},
+			{ID: "errors", Type: "router", Data: map[string]string{"stream": "errors"}},
+			{ID: "others", Type: "router", Data: map[string]string{"stream": "others"}},
+		},
+		Edges: []*models.FlowEdgeV2{
+			{ID: "e1", Source: "source", Target: "switch"},
+			{ID: "e2", Source: "switch", Target: "errors"},
+			{ID: "e3", Source: "switch", SourceHandle: pipelines.SWITCH_ELSE_HANDLE, Target: "others"},
+		},
+	}
+
+	configStorage := mocks.NewMockConfigStorage()
+
+	for level, expected := range map[string]struct {
+		branch string
+		target string
+	}{
+		"error": {pipelines.SWITCH_MATCH_HANDLE, "errors"},
+		"info":  {pipelines.SWITCH_ELSE_HANDLE, "others"},
+	} {
+		pipeline, err := pipelines.BuildFlow(t.Context(), configStorage, "test", flow)
+		if err != nil {
+			t.Fatalf("failed to build flow: %v", err)
+		}
+		if err := pipeline.Init(t.Context()); err != nil {
+			t.Fatalf("failed to init pipeline: %v", err)
+		}
+
+		tracer := &pipelines.NodeTracer{Flow: *flow}
+		ctx := pipelines.WithTracer(t.Context(), tracer)
+
+		record := models.NewLogRecord(map[string]string{"level": level})
+		if err := pipeline.Process(ctx, pipelines.DIRECT_ENTRYPOINT, record); err != nil {
+			t.Fatalf("%s: failed to process record: %v", level, err)
+		}
+
+		visited := map[string]models.PipelineNodeTrace{}
+		for _, trace := range tracer.Trace {
+			visited[trace.NodeID] = trace
+		}
+
+		if branch := visited["switch"].Branch; branch != expected.branch {
+			t.Fatalf("%s: expected branch %q, got %q", level, expected.branch, branch)
+		}
+		if _, exists := visited[expected.target]; !exists {
+			t.Fatalf("%s: expected record to reach %s, got %v", level, expected.target, tracer.Trace)
+		}
+		if len(visited) != 3 {
+			t.Fatalf("%s: expected 3 traced nodes, got %v", level, tracer.Trace)
+		}
+
+		if err := pipeline.Close(t.Context()); err != nil {
+			t.Fatalf("failed to close pipeline: %v", err)
+		}
+	}
+
+	flow.Edges[2].SourceHandle = "unknown"
+	_, err := pipelines.BuildFlow(t.Context(), configStorage, "test", flow)
+
+	var handleErr *pipelines.InvalidFlowEdgeHandleError
+	if !errors.As(err, &handleErr) {
+		t.Fatalf("expected an invalid handle error, got %v", err)
+	}
+}
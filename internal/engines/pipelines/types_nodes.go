@@ -5,19 +5,23 @@ import (
 	"errors"
 
 	"fmt"
+	"strconv"
 	"strings"
 
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"link-society.com/flowg/internal/app/metrics"
 
 	"link-society.com/flowg/internal/models"
+	storage "link-society.com/flowg/internal/storage/interfaces"
 
 	"link-society.com/flowg/internal/engines/forwarders"
 
 	"link-society.com/flowg/internal/utils/langs/filtering"
 	"link-society.com/flowg/internal/utils/langs/vrl"
+	"link-society.com/flowg/internal/utils/timestamp"
 )
 
 // Node is one vertex of a compiled pipeline. Records flow through nodes from a
@@ -40,6 +44,10 @@ type SourceNode struct {
 	Next []Node
 }
 
+// TRANSFORM_TIMESTAMP_FIELD is the field a VRL transformer sets to override the
+// timestamp of the records it emits. It is removed from the emitted records.
+const TRANSFORM_TIMESTAMP_FIELD = "_timestamp"
+
 // TransformNode runs a VRL transformer, which may emit zero, one or many records
 // for each input, forwarding each to its successors.
 type TransformNode struct {
@@ -50,16 +58,44 @@ type TransformNode struct {
 	runner *vrl.ScriptRunner
 }
 
-// SwitchNode forwards a record to its successors only when the record matches
-// its filtering condition.
+const (
+	// SWITCH_MATCH_HANDLE is the output of a switch node taken by the records
+	// matching its condition. Edges without a source handle leave from it.
+	SWITCH_MATCH_HANDLE = "match"
+	// SWITCH_ELSE_HANDLE is the output of a switch node taken by the records
+	// that do not match its condition.
+	SWITCH_ELSE_HANDLE = "else"
+)
+
+// SwitchNode forwards a record to the successors of its match output when the
+// record matches its filtering condition, and to those of its else output
+// otherwise.
 type SwitchNode struct {
 	ID        string
 	Condition string
 	Next      []Node
+	Else      []Node
 
 	runner filtering.Filter
 }
 
+// CASE_DEFAULT_HANDLE is the output of a case node taken by the records that
+// match none of its conditions. The output of each condition is named after its
+// zero-based position.
+const CASE_DEFAULT_HANDLE = "default"
+
+// CaseNode evaluates its conditions in order and forwards a record to the
+// successors of the first one it matches, or to those of its default output
+// when it matches none.
+type CaseNode struct {
+	ID         string
+	Conditions []string
+	Branches   [][]Node
+	Default    []Node
+
+	runners []filtering.Filter
+}
+
 // PipelineNode delegates processing to another named pipeline.
 type PipelineNode struct {
 	ID       string
@@ -67,9 +103,11 @@ type PipelineNode struct {
 }
 
 // ForwardNode sends the record to an external destination through a forwarder.
+// Records the forwarder fails to deliver are moved to its dead-letter queue.
 type ForwardNode struct {
-	ID      string
-	Runtime forwarders.Runtime
+	ID        string
+	Forwarder string
+	Runtime   forwarders.Runtime
 }
 
 // RouterNode persists the record into a log stream and notifies live
@@ -90,6 +128,7 @@ type MetricNode struct {
 var _ Node = (*SourceNode)(nil)
 var _ Node = (*TransformNode)(nil)
 var _ Node = (*SwitchNode)(nil)
+var _ Node = (*CaseNode)(nil)
 var _ Node = (*PipelineNode)(nil)
 var _ Node = (*ForwardNode)(nil)
 var _ Node = (*RouterNode)(nil)
@@ -131,6 +170,19 @@ func traceNode(
 	err error,
 	input map[string]string,
 	output []map[string]string,
+) {
+	traceBranch(ctx, nodeID, "", err, input, output)
+}
+
+// traceBranch is [traceNode] for branching nodes: the trace entry also records
+// the output the record was sent to.
+func traceBranch(
+	ctx context.Context,
+	nodeID string,
+	branch string,
+	err error,
+	input map[string]string,
+	output []map[string]string,
 ) {
 	tracer := GetTracer(ctx)
 	if tracer != nil {
@@ -139,6 +191,7 @@ func traceNode(
 			Input:  input,
 			Output: output,
 			Error:  TraceError(err),
+			Branch: branch,
 		})
 	}
 }
@@ -183,11 +236,28 @@ func (n *TransformNode) Process(ctx context.Context, record *models.LogRecord) e
 		return err
 	}
 
-	for _, event := range output {
-		err := sendRecordToNextNodes(ctx, n.Next, &models.LogRecord{
+	records := make([]*models.LogRecord, len(output))
+	for i, event := range output {
+		records[i] = &models.LogRecord{
 			Timestamp: record.Timestamp,
 			Fields:    event,
-		})
+		}
+
+		if value, exists := event[TRANSFORM_TIMESTAMP_FIELD]; exists {
+			ts, err := timestamp.Parse(value, timestamp.FORMAT_AUTO)
+			if err != nil {
+				err = &InvalidTransformTimestampError{NodeID: n.ID, Err: err}
+				traceNode(ctx, n.ID, err, record.Fields, output)
+				return err
+			}
+
+			records[i].Timestamp = ts
+			delete(event, TRANSFORM_TIMESTAMP_FIELD)
+		}
+	}
+
+	for _, outputRecord := range records {
+		err := sendRecordToNextNodes(ctx, n.Next, outputRecord)
 		if err != nil {
 			traceNode(ctx, n.ID, err, record.Fields, output)
 			return err
@@ -216,13 +286,63 @@ func (n *SwitchNode) Process(ctx context.Context, record *models.LogRecord) erro
 		return err
 	}
 
-	if matches {
-		err = sendRecordToNextNodes(ctx, n.Next, record)
-		traceNode(ctx, n.ID, err, record.Fields, []map[string]string{record.Fields})
-		return err
+	branch, next := SWITCH_MATCH_HANDLE, n.Next
+	if !matches {
+		branch, next = SWITCH_ELSE_HANDLE, n.Else
 	}
 
-	traceNode(ctx, n.ID, nil, record.Fields, nil)
+	if len(next) == 0 {
+		traceBranch(ctx, n.ID, branch, nil, record.Fields, nil)
+		return nil
+	}
+
+	err = sendRecordToNextNodes(ctx, next, record)
+	traceBranch(ctx, n.ID, branch, err, record.Fields, []map[string]string{record.Fields})
+	return err
+}
+
+// MARK: case
+func (n *CaseNode) Init(ctx context.Context) error {
+	n.runners = make([]filtering.Filter, len(n.Conditions))
+
+	for i, condition := range n.Conditions {
+		runner, err := filtering.Compile(condition)
+		if err != nil {
+			return fmt.Errorf("failed to compile condition %d of node %s: %w", i, n.ID, err)
+		}
+		n.runners[i] = runner
+	}
+
+	return nil
+}
+
+func (n *CaseNode) Close(ctx context.Context) error {
+	return nil
+}
+
+func (n *CaseNode) Process(ctx context.Context, record *models.LogRecord) error {
+	branch, next := CASE_DEFAULT_HANDLE, n.Default
+
+	for i, runner := range n.runners {
+		matches, err := runner.Evaluate(record)
+		if err != nil {
+			traceNode(ctx, n.ID, err, record.Fields, nil)
+			return err
+		}
+
+		if matches {
+			branch, next = strconv.Itoa(i), n.Branches[i]
+			break
+		}
+	}
+
+	if len(next) == 0 {
+		traceBranch(ctx, n.ID, branch, nil, record.Fields, nil)
+		return nil
+	}
+
+	err := sendRecordToNextNodes(ctx, next, record)
+	traceBranch(ctx, n.ID, branch, err, record.Fields, []map[string]string{record.Fields})
 	return err
 }
 
@@ -238,6 +358,13 @@ func (n *PipelineNode) Close(ctx context.Context) error {
 func (n *PipelineNode) Process(ctx context.Context, record *models.LogRecord) error {
 	w := getWorker(ctx)
 
+	depth := getPipelineDepth(ctx) + 1
+	if depth > MAX_PIPELINE_DEPTH {
+		err := &PipelineDepthExceededError{Pipeline: n.Pipeline, Depth: MAX_PIPELINE_DEPTH}
+		traceNode(ctx, n.ID, err, record.Fields, nil)
+		return err
+	}
+
 	pipeline, err := w.getOrBuildPipeline(ctx, n.Pipeline)
 	traceNode(ctx, n.ID, err, record.Fields, nil)
 	if err != nil {
@@ -248,7 +375,7 @@ func (n *PipelineNode) Process(ctx context.Context, record *models.LogRecord) er
 		return nil
 	}
 
-	return pipeline.Process(ctx, DIRECT_ENTRYPOINT, record)
+	return pipeline.Process(withPipelineDepth(ctx, depth), DIRECT_ENTRYPOINT, record)
 }
 
 // MARK: forward
@@ -266,7 +393,48 @@ func (n *ForwardNode) Process(ctx context.Context, record *models.LogRecord) err
 		return nil
 	}
 
-	return n.Runtime.Call(ctx, record)
+	err := n.Runtime.Call(ctx, record)
+	if err == nil {
+		return nil
+	}
+
+	records := []*models.LogRecord{record}
+	deadLetterErr := pushDeadLetters(ctx, getWorker(ctx).configStorage, n.Forwarder, records, err)
+
+	return errors.Join(err, deadLetterErr)
+}
+
+// pushDeadLetters moves records a forwarder failed to deliver to its
+// dead-letter queue, along with the delivery error.
+func pushDeadLetters(
+	ctx context.Context,
+	configStorage storage.ConfigStorage,
+	forwarder string,
+	records []*models.LogRecord,
+	err error,
+) error {
+	attempts := 1
+
+	var deliveryErr *forwarders.DeliveryError
+	if errors.As(err, &deliveryErr) {
+		attempts = deliveryErr.Attempts
+	}
+
+	for _, record := range records {
+		letter := models.DeadLetter{
+			Timestamp: time.Now(),
+			Forwarder: forwarder,
+			Record:    *record,
+			Attempts:  attempts,
+			Error:     err.Error(),
+		}
+
+		if err := configStorage.PushDeadLetter(ctx, letter); err != nil {
+			return fmt.Errorf("failed to queue dead letter: %w", err)
+		}
+	}
+
+	return nil
 }
 
 // MARK: router
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+
+	"encoding/json"
+	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"link-society.com/flowg/internal/app/metrics"
@@ -41,9 +45,15 @@ func BuildFromStorage(ctx context.Context, configStorage storage.ConfigStorage,
 
 // BuildFlow compiles a flow graph into a Pipeline: it turns each flow node into
 // the matching Node implementation (resolving referenced transformers and
-// forwarders from storage), then wires the edges as each node's successors.
-// Source nodes become entrypoints keyed by their declared type.
+// forwarders from storage), then wires the edges as each node's successors,
+// following the edge's source handle on switch and case nodes. Source nodes become
+// entrypoints keyed by their declared type. Flows whose pipeline nodes lead back
+// to the pipeline being built are rejected with a [PipelineCycleError].
 func BuildFlow(ctx context.Context, configStorage storage.ConfigStorage, name string, flowGraph *models.FlowGraphV2) (*Pipeline, error) {
+	if err := CheckPipelineCycles(ctx, configStorage, name, flowGraph); err != nil {
+		return nil, err
+	}
+
 	var (
 		pipelineNodes   = make(map[string]Node)
 		flowNodesByID   = make(map[string]*models.FlowNodeV2)
@@ -93,6 +103,31 @@ func BuildFlow(ctx context.Context, configStorage storage.ConfigStorage, name st
 			}
 			pipelineNodes[flowNode.ID] = pipelineNode
 
+		case "case":
+			encodedConditions, exists := flowNode.Data["conditions"]
+			if !exists {
+				return nil, &MissingFlowNodeDataError{
+					NodeID: flowNode.ID,
+					Key:    "conditions",
+				}
+			}
+
+			var conditions []string
+			if err := json.Unmarshal([]byte(encodedConditions), &conditions); err != nil {
+				return nil, &InvalidFlowNodeDataError{
+					NodeID: flowNode.ID,
+					Key:    "conditions",
+					Err:    err,
+				}
+			}
+
+			pipelineNode := &CaseNode{
+				ID:         flowNode.ID,
+				Conditions: conditions,
+				Branches:   make([][]Node, len(conditions)),
+			}
+			pipelineNodes[flowNode.ID] = pipelineNode
+
 		case "metric":
 			metricName, exists := flowNode.Data["name"]
 			if !exists {
@@ -145,9 +180,30 @@ func BuildFlow(ctx context.Context, configStorage storage.ConfigStorage, name st
 				return nil, err
 			}
 
+			if forwarder.Batch != nil {
+				runtime = forwarders.NewBatchRuntime(
+					forwarderName,
+					runtime,
+					forwarder.Batch,
+					func(ctx context.Context, records []*models.LogRecord, err error) {
+						deadLetterErr := pushDeadLetters(ctx, configStorage, forwarderName, records, err)
+						slog.ErrorContext(
+							ctx,
+							"failed to deliver batch",
+							"channel", "pipelines",
+							"pipeline", name,
+							"forwarder", forwarderName,
+							"records", len(records),
+							"error", errors.Join(err, deadLetterErr).Error(),
+						)
+					},
+				)
+			}
+
 			pipelineNode := &ForwardNode{
-				ID:      flowNode.ID,
-				Runtime: runtime,
+				ID:        flowNode.ID,
+				Forwarder: forwarderName,
+				Runtime:   runtime,
 			}
 			pipelineNodes[flowNode.ID] = pipelineNode
 
@@ -159,6 +215,13 @@ func BuildFlow(ctx context.Context, configStorage storage.ConfigStorage, name st
 					Key:    "stream",
 				}
 			}
+			if stream == models.AUDIT_STREAM {
+				return nil, &InvalidFlowNodeDataError{
+					NodeID: flowNode.ID,
+					Key:    "stream",
+					Err:    fmt.Errorf("stream %q is reserved", stream),
+				}
+			}
 
 			pipelineNode := &RouterNode{
 				ID:     flowNode.ID,
@@ -203,7 +266,34 @@ func BuildFlow(ctx context.Context, configStorage storage.ConfigStorage, name st
 			source.Next = append(source.Next, targetNode)
 
 		case *SwitchNode:
-			source.Next = append(source.Next, targetNode)
+			switch flowEdge.SourceHandle {
+			case "", SWITCH_MATCH_HANDLE:
+				source.Next = append(source.Next, targetNode)
+
+			case SWITCH_ELSE_HANDLE:
+				source.Else = append(source.Else, targetNode)
+
+			default:
+				return nil, &InvalidFlowEdgeHandleError{
+					Source: flowEdge.Source,
+					Handle: flowEdge.SourceHandle,
+				}
+			}
+
+		case *CaseNode:
+			if flowEdge.SourceHandle == CASE_DEFAULT_HANDLE {
+				source.Default = append(source.Default, targetNode)
+				break
+			}
+
+			i, err := strconv.Atoi(flowEdge.SourceHandle)
+			if err != nil || i < 0 || i >= len(source.Branches) {
+				return nil, &InvalidFlowEdgeHandleError{
+					Source: flowEdge.Source,
+					Handle: flowEdge.SourceHandle,
+				}
+			}
+			source.Branches[i] = append(source.Branches[i], targetNode)
 
 		default:
 			panic("unreachable")
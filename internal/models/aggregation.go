@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AggregationFunction names a statistic computed over a group of log records.
+type AggregationFunction string
+
+const (
+	AGGREGATION_COUNT          AggregationFunction = "count"
+	AGGREGATION_COUNT_DISTINCT AggregationFunction = "count_distinct"
+	AGGREGATION_MIN            AggregationFunction = "min"
+	AGGREGATION_MAX            AggregationFunction = "max"
+	AGGREGATION_AVG            AggregationFunction = "avg"
+)
+
+// Aggregation is one statistic to compute over each group of records: a
+// function and, for every function but count, the field it applies to.
+//
+// min, max and avg parse the field's values as numbers and ignore the records
+// whose value is missing or not numeric; count_distinct counts the distinct
+// values of the field, including the empty value of records lacking it.
+type Aggregation struct {
+	Function AggregationFunction `json:"function" required:"true" enum:"count,count_distinct,min,max,avg"`
+	Field    string              `json:"field,omitempty"`
+}
+
+// ParseAggregation converts the textual form of an aggregation, "count" or
+// "<function>(<field>)", into an Aggregation.
+func ParseAggregation(s string) (Aggregation, error) {
+	s = strings.TrimSpace(s)
+	if s == string(AGGREGATION_COUNT) {
+		return Aggregation{Function: AGGREGATION_COUNT}, nil
+	}
+
+	name, rest, found := strings.Cut(s, "(")
+	if !found || !strings.HasSuffix(rest, ")") {
+		return Aggregation{}, fmt.Errorf("invalid aggregation: %q", s)
+	}
+
+	field := strings.TrimSpace(strings.TrimSuffix(rest, ")"))
+	if field == "" {
+		return Aggregation{}, fmt.Errorf("missing field in aggregation: %q", s)
+	}
+
+	switch fn := AggregationFunction(strings.TrimSpace(name)); fn {
+	case AGGREGATION_COUNT_DISTINCT, AGGREGATION_MIN, AGGREGATION_MAX, AGGREGATION_AVG:
+		return Aggregation{Function: fn, Field: field}, nil
+
+	default:
+		return Aggregation{}, fmt.Errorf("unknown aggregation function: %q", name)
+	}
+}
+
+// String returns the textual form of the aggregation, as accepted by
+// [ParseAggregation]. It also names the aggregation's value in an
+// [AggregationBucket].
+func (a Aggregation) String() string {
+	if a.Function == AGGREGATION_COUNT {
+		return string(AGGREGATION_COUNT)
+	}
+
+	return fmt.Sprintf("%s(%s)", a.Function, a.Field)
+}
+
+// AggregationQuery describes how to summarize a stream's records: the
+// statistics to compute, the fields whose values split the records into
+// groups, and, when Interval is positive, the width of the time buckets that
+// further split each group into a histogram.
+type AggregationQuery struct {
+	Aggregations []Aggregation `json:"aggregations"`
+	GroupBy      []string      `json:"group_by"`
+	Interval     time.Duration `json:"interval"`
+}
+
+// IsCountOnly reports whether every requested statistic is a plain count, which
+// can be answered without decoding the records.
+func (q AggregationQuery) IsCountOnly() bool {
+	for _, aggregation := range q.Aggregations {
+		if aggregation.Function != AGGREGATION_COUNT {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AggregationBucket holds the statistics of one group of records. Timestamp is
+// the start of the bucket's time interval, and is only set for histograms.
+// Values is keyed by the aggregations' textual form (see [Aggregation.String]);
+// a min, max or avg over a group without any numeric value is left out.
+type AggregationBucket struct {
+	Timestamp *time.Time         `json:"timestamp,omitempty" format:"date-time"`
+	Group     map[string]string  `json:"group" required:"true"`
+	Values    map[string]float64 `json:"values" required:"true"`
+}
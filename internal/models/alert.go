@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// AlertRule watches a stream for a burst of matching records: it fires when at
+// least Threshold records satisfying Filter are routed to Stream within Window
+// seconds, then stays silent for Cooldown seconds. A firing is delivered through
+// the named forwarder as a synthesized alert record.
+type AlertRule struct {
+	Stream    string `json:"stream" required:"true" minLength:"1"`
+	Filter    string `json:"filter" description:"expr filter selecting the records to count, empty to count every record"`
+	Threshold int64  `json:"threshold" required:"true" minimum:"1" description:"Number of matching records that fires the alert"`
+	Window    int64  `json:"window" required:"true" minimum:"1" description:"Time window in seconds"`
+	Cooldown  int64  `json:"cooldown" required:"true" minimum:"0" description:"Minimum delay in seconds between two firings, 0 to disable"`
+	Forwarder string `json:"forwarder" required:"true" minLength:"1"`
+}
+
+// AlertEvent records one firing of an alert rule: when it happened, how many
+// records matched within the window, and whether delivering it through the
+// rule's forwarder failed.
+type AlertEvent struct {
+	Timestamp time.Time `json:"timestamp" required:"true" format:"date-time"`
+	Alert     string    `json:"alert" required:"true"`
+	Stream    string    `json:"stream" required:"true"`
+	Forwarder string    `json:"forwarder" required:"true"`
+	Count     int64     `json:"count" required:"true"`
+	Error     string    `json:"error,omitempty"`
+}
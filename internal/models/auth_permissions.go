@@ -16,6 +16,9 @@ type Permissions struct {
 	CanViewForwarders bool `json:"can_view_forwarders" required:"true"`
 	CanEditForwarders bool `json:"can_edit_forwarders" required:"true"`
 
+	CanViewAlerts bool `json:"can_view_alerts" required:"true"`
+	CanEditAlerts bool `json:"can_edit_alerts" required:"true"`
+
 	CanViewACLs bool `json:"can_view_acls" required:"true"`
 	CanEditACLs bool `json:"can_edit_acls" required:"true"`
 
@@ -50,6 +53,10 @@ func PermissionsFromScopes(scopes []Scope) Permissions {
 			permissions.CanViewForwarders = true
 		case SCOPE_WRITE_FORWARDERS:
 			permissions.CanEditForwarders = true
+		case SCOPE_READ_ALERTS:
+			permissions.CanViewAlerts = true
+		case SCOPE_WRITE_ALERTS:
+			permissions.CanEditAlerts = true
 		case SCOPE_READ_ACLS:
 			permissions.CanViewACLs = true
 		case SCOPE_WRITE_ACLS:
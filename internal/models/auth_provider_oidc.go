@@ -1,10 +1,14 @@
 package models
 
 // AuthProviderOidc contains the configuration for an OpenID Connect
-// authentication provider.
+// authentication provider. Scopes defaults to "openid profile email", and
+// GroupsClaim, the ID token claim whose values name the roles of the user, to
+// "groups".
 type AuthProviderOidc struct {
-	Type         string `json:"type" enum:"oidc" required:"true"`
-	Issuer       string `json:"issuer" required:"true" format:"uri"`
-	ClientID     string `json:"client_id" required:"true"`
-	ClientSecret string `json:"client_secret" required:"true"`
-}
\ No newline at end of file
+	Type         string   `json:"type" enum:"oidc" required:"true"`
+	Issuer       string   `json:"issuer" required:"true" format:"uri"`
+	ClientID     string   `json:"client_id" required:"true"`
+	ClientSecret string   `json:"client_secret" required:"true"`
+	Scopes       []string `json:"scopes,omitempty"`
+	GroupsClaim  string   `json:"groups_claim,omitempty"`
+}
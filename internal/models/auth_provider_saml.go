@@ -1,8 +1,16 @@
 package models
 
 // AuthProviderSaml contains the configuration for a SAML authentication
-// provider.
+// provider. IdpCertificate, a PEM encoded certificate, pins the one the
+// assertions must be signed with instead of those listed in the IdP metadata,
+// which must then be served over HTTPS.
+// UsernameAttribute defaults to the NameID of the assertion subject, and
+// GroupsAttribute, the attribute whose values name the roles of the user, to
+// "groups".
 type AuthProviderSaml struct {
-	Type           string `json:"type" enum:"saml" required:"true"`
-	IdpMetadataURL string `json:"idp_metadata_url" required:"true" format:"uri"`
-}
\ No newline at end of file
+	Type              string `json:"type" enum:"saml" required:"true"`
+	IdpMetadataURL    string `json:"idp_metadata_url" required:"true" format:"uri"`
+	IdpCertificate    string `json:"idp_certificate,omitempty"`
+	UsernameAttribute string `json:"username_attribute,omitempty"`
+	GroupsAttribute   string `json:"groups_attribute,omitempty"`
+}
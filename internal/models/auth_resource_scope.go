@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Resource identifies the objects an operation acts upon, by the values of
+// the path parameters naming them, e.g. {"pipeline": "ingress-eu"}. An empty
+// resource designates no object in particular, e.g. when listing them.
+type Resource map[string]string
+
+// ResourceScope attaches a Scope to the resources whose name matches a
+// pattern, e.g. "read_streams:payments-*" or "send_logs:pipeline=ingress-eu".
+//
+// The pattern follows the syntax of [path.Match], and may be prefixed with the
+// name of the path parameter it applies to, which must be the one of the scope
+// (see [Scope.ResourceParameter]).
+type ResourceScope string
+
+// ParseResourceScope converts a wire string into a ResourceScope, returning an
+// error for unknown scopes, scopes that cannot be restricted to some resources,
+// and malformed patterns.
+func ParseResourceScope(s string) (ResourceScope, error) {
+	scopeName, pattern, found := strings.Cut(s, ":")
+	if !found {
+		return "", fmt.Errorf("invalid resource scope %q: missing resource pattern", s)
+	}
+
+	scope, err := ParseScope(scopeName)
+	if err != nil {
+		return "", fmt.Errorf("invalid resource scope %q: %w", s, err)
+	}
+
+	parameter := scope.ResourceParameter()
+	if parameter == "" {
+		return "", fmt.Errorf("invalid resource scope %q: scope %q does not apply to resources", s, scope)
+	}
+
+	if name, value, found := strings.Cut(pattern, "="); found {
+		if name != parameter {
+			return "", fmt.Errorf("invalid resource scope %q: scope %q applies to %q, not %q", s, scope, parameter, name)
+		}
+
+		pattern = value
+	}
+
+	if pattern == "" {
+		return "", fmt.Errorf("invalid resource scope %q: empty resource pattern", s)
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid resource scope %q: %w", s, err)
+	}
+
+	return ResourceScope(s), nil
+}
+
+// Scope returns the scope granted on the matching resources.
+func (r ResourceScope) Scope() Scope {
+	scope, _, _ := strings.Cut(string(r), ":")
+	return Scope(scope)
+}
+
+// Pattern returns the pattern matching the names of the resources.
+func (r ResourceScope) Pattern() string {
+	_, pattern, _ := strings.Cut(string(r), ":")
+	if _, value, found := strings.Cut(pattern, "="); found {
+		return value
+	}
+
+	return pattern
+}
+
+// Matches reports whether the resource is one the pattern applies to.
+func (r ResourceScope) Matches(resource Resource) bool {
+	name, exists := resource[r.Scope().ResourceParameter()]
+	if !exists {
+		return false
+	}
+
+	matched, err := path.Match(r.Pattern(), name)
+	return err == nil && matched
+}
+
+// Grants reports whether the resource scope grants a scope on a resource.
+func (r ResourceScope) Grants(scope Scope, resource Resource) bool {
+	return r.Scope().Implies(scope) && r.Matches(resource)
+}
@@ -0,0 +1,57 @@
+package models_test
+
+import (
+	"testing"
+
+	"link-society.com/flowg/internal/models"
+)
+
+func TestParseResourceScope(t *testing.T) {
+	valid := []string{
+		"read_streams:payments-*",
+		"read_streams:stream=payments-*",
+		"send_logs:pipeline=ingress-eu",
+		"write_pipelines:team-[ab]",
+	}
+
+	for _, s := range valid {
+		if _, err := models.ParseResourceScope(s); err != nil {
+			t.Errorf("expected %q to be valid, got %v", s, err)
+		}
+	}
+
+	invalid := []string{
+		"read_streams",
+		"read_streams:",
+		"read_streams:pipeline=payments-*",
+		"read_acls:admins",
+		"unknown:payments-*",
+		"read_streams:payments-[",
+	}
+
+	for _, s := range invalid {
+		if _, err := models.ParseResourceScope(s); err == nil {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestResourceScopeGrants(t *testing.T) {
+	resourceScope := models.ResourceScope("write_streams:stream=payments-*")
+
+	if !resourceScope.Grants(models.SCOPE_READ_STREAMS, models.Resource{"stream": "payments-eu"}) {
+		t.Error("expected the write scope to imply the read scope on a matching stream")
+	}
+
+	if resourceScope.Grants(models.SCOPE_READ_STREAMS, models.Resource{"stream": "audit"}) {
+		t.Error("expected no scope on a stream not matching the pattern")
+	}
+
+	if resourceScope.Grants(models.SCOPE_READ_STREAMS, nil) {
+		t.Error("expected no scope without resource")
+	}
+
+	if resourceScope.Grants(models.SCOPE_SEND_LOGS, models.Resource{"stream": "payments-eu"}) {
+		t.Error("expected no other scope on a matching stream")
+	}
+}
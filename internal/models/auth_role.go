@@ -2,9 +2,13 @@ package models
 
 // Role is a named bundle of Scopes. Users are granted roles, and a user's
 // effective permissions are the union of the scopes of all its roles.
+//
+// A role may also grant scopes on some resources only, through its
+// ResourceScopes.
 type Role struct {
-	Name   string  `json:"name" required:"true" minLength:"1"`
-	Scopes []Scope `json:"scopes" required:"true"`
+	Name           string          `json:"name" required:"true" minLength:"1"`
+	Scopes         []Scope         `json:"scopes" required:"true"`
+	ResourceScopes []ResourceScope `json:"resource_scopes,omitempty" description:"Scopes granted on the resources matching a pattern only, e.g. read_streams:payments-*"`
 }
 
 // HasScope reports whether the role grants the given scope.
@@ -16,6 +16,8 @@ const (
 	SCOPE_WRITE_STREAMS              Scope = "write_streams"
 	SCOPE_READ_FORWARDERS            Scope = "read_forwarders"
 	SCOPE_WRITE_FORWARDERS           Scope = "write_forwarders"
+	SCOPE_READ_ALERTS                Scope = "read_alerts"
+	SCOPE_WRITE_ALERTS               Scope = "write_alerts"
 	SCOPE_READ_ACLS                  Scope = "read_acls"
 	SCOPE_WRITE_ACLS                 Scope = "write_acls"
 	SCOPE_SEND_LOGS                  Scope = "send_logs"
@@ -45,6 +47,10 @@ func ParseScope(s string) (Scope, error) {
 		return SCOPE_READ_FORWARDERS, nil
 	case "write_forwarders":
 		return SCOPE_WRITE_FORWARDERS, nil
+	case "read_alerts":
+		return SCOPE_READ_ALERTS, nil
+	case "write_alerts":
+		return SCOPE_WRITE_ALERTS, nil
 	case "read_acls":
 		return SCOPE_READ_ACLS, nil
 	case "write_acls":
@@ -75,6 +81,8 @@ func (s Scope) Enum() []any {
 		SCOPE_WRITE_STREAMS,
 		SCOPE_READ_FORWARDERS,
 		SCOPE_WRITE_FORWARDERS,
+		SCOPE_READ_ALERTS,
+		SCOPE_WRITE_ALERTS,
 		SCOPE_READ_ACLS,
 		SCOPE_WRITE_ACLS,
 		SCOPE_SEND_LOGS,
@@ -84,3 +92,53 @@ func (s Scope) Enum() []any {
 		SCOPE_WRITE_AUTH_PROVIDERS,
 	}
 }
+
+// Implies reports whether holding the scope grants another one: every scope
+// grants itself, and a write scope grants its matching read scope.
+func (s Scope) Implies(scope Scope) bool {
+	switch {
+	case s == scope:
+		return true
+
+	case scope == SCOPE_READ_PIPELINES && s == SCOPE_WRITE_PIPELINES:
+		return true
+
+	case scope == SCOPE_READ_TRANSFORMERS && s == SCOPE_WRITE_TRANSFORMERS:
+		return true
+
+	case scope == SCOPE_READ_STREAMS && s == SCOPE_WRITE_STREAMS:
+		return true
+
+	case scope == SCOPE_READ_FORWARDERS && s == SCOPE_WRITE_FORWARDERS:
+		return true
+
+	case scope == SCOPE_READ_ALERTS && s == SCOPE_WRITE_ALERTS:
+		return true
+
+	case scope == SCOPE_READ_ACLS && s == SCOPE_WRITE_ACLS:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// ResourceParameter returns the name of the path parameter identifying the
+// resources the scope applies to (e.g. "stream" for [SCOPE_READ_STREAMS]), or
+// an empty string for scopes that cannot be restricted to some resources.
+func (s Scope) ResourceParameter() string {
+	switch s {
+	case SCOPE_READ_PIPELINES, SCOPE_WRITE_PIPELINES, SCOPE_SEND_LOGS:
+		return "pipeline"
+	case SCOPE_READ_TRANSFORMERS, SCOPE_WRITE_TRANSFORMERS:
+		return "transformer"
+	case SCOPE_READ_STREAMS, SCOPE_WRITE_STREAMS:
+		return "stream"
+	case SCOPE_READ_FORWARDERS, SCOPE_WRITE_FORWARDERS:
+		return "forwarder"
+	case SCOPE_READ_ALERTS, SCOPE_WRITE_ALERTS:
+		return "alert"
+	default:
+		return ""
+	}
+}
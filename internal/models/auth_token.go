@@ -0,0 +1,39 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Token describes a personal access token, never its secret value. A token
+// acts on behalf of its owner, restricted to Scopes unless empty, until it
+// expires. Tokens created before names and scopes existed have neither, nor a
+// creation date.
+type Token struct {
+	UUID       string     `json:"uuid" required:"true"`
+	Name       string     `json:"name" required:"true"`
+	Scopes     []Scope    `json:"scopes" required:"true" description:"Scopes the token is restricted to, empty for every scope of its owner"`
+	CreatedAt  *time.Time `json:"created_at,omitempty" format:"date-time"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" format:"date-time"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" format:"date-time"`
+}
+
+// IsRestricted reports whether the token is restricted to a subset of the
+// scopes of its owner.
+func (t *Token) IsRestricted() bool {
+	return len(t.Scopes) > 0
+}
+
+// AllowsScope reports whether the token may be used for the given scope,
+// provided its owner is granted it. As with roles, a write scope allows its
+// matching read scope (see [Scope.Implies]).
+func (t *Token) AllowsScope(scope Scope) bool {
+	return !t.IsRestricted() || slices.ContainsFunc(t.Scopes, func(s Scope) bool {
+		return s.Implies(scope)
+	})
+}
+
+// IsExpired reports whether the token can no longer be used at the given time.
+func (t *Token) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
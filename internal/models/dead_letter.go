@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// DeadLetter is a record a forwarder failed to deliver once its retry policy
+// was exhausted. It is kept in the forwarder's dead-letter queue, along with the
+// last delivery error, until it is replayed or purged.
+type DeadLetter struct {
+	ID        string    `json:"id" required:"true"`
+	Timestamp time.Time `json:"timestamp" required:"true" format:"date-time"`
+	Forwarder string    `json:"forwarder" required:"true"`
+	Record    LogRecord `json:"record" required:"true"`
+	Attempts  int       `json:"attempts" required:"true"`
+	Error     string    `json:"error" required:"true"`
+}
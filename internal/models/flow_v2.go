@@ -17,13 +17,16 @@ type FlowGraphV2 struct {
 // forwarder name).
 type FlowNodeV2 struct {
 	ID       string            `json:"id" required:"true" minLength:"1"`
-	Type     string            `json:"type" required:"true" enum:"source,transform,switch,metric,forwarder,pipeline,router"`
+	Type     string            `json:"type" required:"true" enum:"source,transform,switch,case,metric,forwarder,pipeline,router"`
 	Position FlowPositionV2    `json:"position" required:"true"`
 	Data     map[string]string `json:"data" required:"true"`
 }
 
 // FlowEdgeV2 connects a source node to a target node; SourceHandle selects which
-// output of the source the edge leaves from.
+// output of the source the edge leaves from. Switch nodes have a "match" output,
+// also selected by an empty handle, and an "else" output. Case nodes have one
+// output per condition, named after its zero-based position, and a "default"
+// output.
 type FlowEdgeV2 struct {
 	ID           string `json:"id" required:"true" minLength:"1"`
 	Source       string `json:"source" required:"true" minLength:"1"`
@@ -8,10 +8,29 @@ import (
 
 // ForwarderV2 is the current forwarder model: a destination a router/forward
 // node can send records to. The concrete destination lives in Config, which is a
-// tagged union of one backend type.
+// tagged union of one backend type. Retry optionally describes how failed
+// deliveries are retried; without it, each record is attempted once. Batch
+// optionally makes pipelines queue records and deliver them in batches; without
+// it, each record is delivered inline as it goes through the pipeline.
 type ForwarderV2 struct {
-	Version int               `json:"version" default:"2"`
-	Config  ForwarderConfigV2 `json:"config" required:"true"`
+	Version int                     `json:"version" default:"2"`
+	Config  ForwarderConfigV2       `json:"config" required:"true"`
+	Retry   *ForwarderRetryPolicyV2 `json:"retry,omitempty"`
+	Batch   *ForwarderBatchPolicyV2 `json:"batch,omitempty"`
+}
+
+// ForwarderRetryPolicyV2 bounds how many times a record is delivered before
+// being given up on (and moved to the forwarder's dead-letter queue). The delay
+// between two attempts starts at InitialBackoff (100ms when unset, and never
+// below 10ms) and doubles after each attempt, up to MaxBackoff. Failures carrying a status code (HTTP-based backends) are
+// only retried when it is listed in RetryableStatusCodes, or is one of 408, 429,
+// 500, 502, 503 and 504 when the list is empty; other failures (connection
+// errors, timeouts, ...) are always retried.
+type ForwarderRetryPolicyV2 struct {
+	MaxAttempts          int   `json:"max_attempts" required:"true" minimum:"1" description:"Maximum number of delivery attempts per record"`
+	InitialBackoff       int64 `json:"initial_backoff" minimum:"0" description:"Delay in milliseconds before the first retry, 0 for the default of 100 (never below 10)"`
+	MaxBackoff           int64 `json:"max_backoff" minimum:"0" description:"Maximum delay in milliseconds between two attempts, 0 to disable"`
+	RetryableStatusCodes []int `json:"retryable_status_codes,omitempty" items.minimum:"100" items.maximum:"599"`
 }
 
 // ForwarderConfigV2 is a tagged union: exactly one field is non-nil, selecting
@@ -31,6 +50,16 @@ type ForwarderConfigV2 struct {
 	AzureMonitor       *ForwarderAzureMonitorV2       `json:"-"`
 }
 
+// ForwarderBatchPolicyV2 describes how records are queued before delivery: a
+// batch is flushed once it holds MaxSize records, or FlushInterval milliseconds
+// after the previous flush, whichever comes first. At most QueueSize records
+// wait in memory; once full, pipelines block until a flush frees some room.
+type ForwarderBatchPolicyV2 struct {
+	MaxSize       int   `json:"max_size" required:"true" minimum:"1" description:"Maximum number of records per batch"`
+	FlushInterval int64 `json:"flush_interval" required:"true" minimum:"1" description:"Maximum delay in milliseconds before a partial batch is delivered"`
+	QueueSize     int   `json:"queue_size" minimum:"0" description:"Maximum number of records waiting for delivery, defaults to 10 batches"`
+}
+
 // JSONSchemaOneOf advertises every backend variant so the generated OpenAPI
 // schema models Config as a "oneOf".
 func (*ForwarderConfigV2) JSONSchemaOneOf() []any {
@@ -2,9 +2,11 @@ package models
 
 // PipelineNodeTrace is the record of a single node's execution during a
 // pipeline dry run: its input fields, the records it emitted, and any error.
+// Branch names the output a branching node (e.g. a switch) sent the record to.
 type PipelineNodeTrace struct {
 	NodeID string              `json:"nodeID"`
 	Input  map[string]string   `json:"input"`
 	Output []map[string]string `json:"output"`
 	Error  *string             `json:"error"`
+	Branch string              `json:"branch,omitempty"`
 }
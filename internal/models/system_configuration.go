@@ -1,9 +1,36 @@
 package models
 
 // SystemConfiguration holds the global, server-wide settings. SyslogAllowedOrigins
-// restricts which source IPs or CIDR ranges may push logs to the syslog endpoint.
-// DefaultRoles defines the default roles assigned to new users.
+// restricts which source IPs or CIDR ranges may push logs to the syslog endpoint,
+// and SyslogAllowedSubjects which client certificate subjects may push logs to
+// its TLS listeners. SyslogRoutes selects the pipelines receiving each syslog
+// message. DefaultRoles defines the default roles assigned to new users.
+// TimestampPolicy bounds the timestamps clients may assign to the records they
+// send. AuditForwarder names the forwarder audit events are sent through,
+// besides being stored in [AUDIT_STREAM].
 type SystemConfiguration struct {
-	SyslogAllowedOrigins []string `json:"syslog_allowed_origins"`
-	DefaultRoles         []string `json:"default_roles"`
+	SyslogAllowedOrigins  []string        `json:"syslog_allowed_origins"`
+	SyslogAllowedSubjects []string        `json:"syslog_allowed_subjects" description:"Patterns matching the common name or a subject alternative name of client certificates"`
+	SyslogRoutes          []SyslogRoute   `json:"syslog_routes"`
+	DefaultRoles          []string        `json:"default_roles"`
+	TimestampPolicy       TimestampPolicy `json:"timestamp_policy"`
+	AuditForwarder        string          `json:"audit_forwarder,omitempty" description:"Name of the forwarder audit events are sent through, empty to only store them"`
+}
+
+// SyslogRoute sends the syslog messages matching all of its criteria to a
+// pipeline. A criterion left empty matches every message.
+type SyslogRoute struct {
+	Pipeline  string   `json:"pipeline" required:"true" minLength:"1"`
+	Listeners []string `json:"listeners,omitempty" description:"Names of the syslog listeners"`
+	Origins   []string `json:"origins,omitempty" description:"Source IPs or CIDR ranges"`
+	AppNames  []string `json:"app_names,omitempty" description:"Application names (RFC 5424 APP-NAME, or RFC 3164 TAG)"`
+}
+
+// TimestampPolicy bounds how far in the past or in the future (in seconds,
+// relative to the time of ingestion) a client-supplied timestamp may be. A
+// record whose timestamp is out of bounds is stamped with the time of ingestion
+// instead. A zero bound disables the corresponding check.
+type TimestampPolicy struct {
+	MaxPast   int64 `json:"max_past" minimum:"0" description:"Maximum age in seconds of a client-supplied timestamp, 0 to disable"`
+	MaxFuture int64 `json:"max_future" minimum:"0" description:"Maximum delay in seconds into the future of a client-supplied timestamp, 0 to disable"`
 }
@@ -13,14 +13,17 @@ import (
 	"go.uber.org/fx"
 
 	"link-society.com/flowg/api"
+	"link-society.com/flowg/api/sso"
 	"link-society.com/flowg/web"
 )
 
 // ServerOptions configures the HTTP server: where to bind, the path everything
-// is mounted under, and an optional TLS configuration (nil serves plain HTTP).
+// is mounted under, the URL browsers reach it at (empty if unknown), and an
+// optional TLS configuration (nil serves plain HTTP).
 type ServerOptions struct {
 	BindAddress string
 	MountPath   string
+	ExternalURL string
 	TlsConfig   *tls.Config
 }
 
@@ -48,6 +51,7 @@ func NewServer(opts ServerOptions) fx.Option {
 	return fx.Module(
 		"services.http",
 		api.Module("service-http-api"),
+		fx.Supply(sso.Options{ExternalURL: opts.ExternalURL}),
 		web.Module("service-http-web", opts.MountPath),
 		fx.Provide(func(lc fx.Lifecycle, h handlers) *Server {
 			rootHandler := http.NewServeMux()
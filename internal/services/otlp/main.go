@@ -0,0 +1,104 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"crypto/tls"
+	"net"
+
+	"go.uber.org/fx"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	collectlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+
+	"link-society.com/flowg/internal/engines/pipelines"
+)
+
+// ServerOptions configures the OTLP/gRPC server: where to bind and an optional
+// TLS configuration (nil serves plain-text gRPC).
+type ServerOptions struct {
+	BindAddress string
+	TlsConfig   *tls.Config
+}
+
+// Server is the running OTLP/gRPC service.
+type Server struct {
+	grpcServer *grpc.Server
+}
+
+// NewServer returns an fx module that serves the OpenTelemetry logs collector
+// service over gRPC, feeding each exported record into the pipeline engine.
+// The listener is bound to the application lifecycle.
+func NewServer(opts ServerOptions) fx.Option {
+	logger := slog.Default().With(
+		slog.String("channel", "otlp"),
+		slog.Group("otlp",
+			slog.String("bind", opts.BindAddress),
+			slog.Bool("tls", opts.TlsConfig != nil),
+		),
+	)
+
+	return fx.Module(
+		"services.otlp",
+		fx.Provide(func(
+			lc fx.Lifecycle,
+			authStorage storage.AuthStorage,
+			pipelineRunner pipelines.Runner,
+		) *Server {
+			var serverOpts []grpc.ServerOption
+			if opts.TlsConfig != nil {
+				serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(opts.TlsConfig)))
+			}
+
+			srv := &Server{
+				grpcServer: grpc.NewServer(serverOpts...),
+			}
+
+			collectlogs.RegisterLogsServiceServer(srv.grpcServer, &logsService{
+				logger:         logger,
+				authStorage:    authStorage,
+				pipelineRunner: pipelineRunner,
+			})
+
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					logger.InfoContext(ctx, "Starting OTLP gRPC server")
+
+					l, err := net.Listen("tcp", opts.BindAddress)
+					if err != nil {
+						return fmt.Errorf("failed to start OTLP gRPC server: %w", err)
+					}
+
+					go srv.grpcServer.Serve(l)
+
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					logger.InfoContext(ctx, "Stopping OTLP gRPC server")
+
+					stopped := make(chan struct{})
+					go func() {
+						srv.grpcServer.GracefulStop()
+						close(stopped)
+					}()
+
+					select {
+					case <-stopped:
+					case <-ctx.Done():
+						srv.grpcServer.Stop()
+					}
+
+					return nil
+				},
+			})
+
+			return srv
+		}),
+	)
+}
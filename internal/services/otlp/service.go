@@ -0,0 +1,173 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	collectlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	otlplogmodels "go.opentelemetry.io/proto/otlp/logs/v1"
+
+	"link-society.com/flowg/api/auth"
+	"link-society.com/flowg/internal/engines/pipelines"
+	"link-society.com/flowg/internal/models"
+	otlputils "link-society.com/flowg/internal/utils/otlp"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+const (
+	// PIPELINE_METADATA_KEY is the gRPC metadata key naming the pipeline the
+	// exported records are sent to.
+	PIPELINE_METADATA_KEY = "x-flowg-pipeline"
+	// PIPELINE_RESOURCE_ATTRIBUTE is the resource attribute naming the pipeline
+	// the records of that resource are sent to. It takes precedence over the
+	// metadata key.
+	PIPELINE_RESOURCE_ATTRIBUTE = "flowg.pipeline"
+)
+
+// logsService implements the OpenTelemetry logs collector service on top of
+// the pipeline engine.
+type logsService struct {
+	collectlogs.UnimplementedLogsServiceServer
+
+	logger         *slog.Logger
+	authStorage    storage.AuthStorage
+	pipelineRunner pipelines.Runner
+}
+
+var _ collectlogs.LogsServiceServer = (*logsService)(nil)
+
+// Export authenticates the caller with the FlowG token found in the
+// "authorization" metadata, then runs every exported record through the
+// "direct" entrypoint of its pipeline.
+//
+// Callers must have the send-logs permission on the pipeline of each resource.
+// Those are resolved before any record is processed, so that a request missing
+// one, or naming one the caller may not send logs to, is rejected as a whole.
+// Ingestion stops at the first record that fails.
+func (s *logsService) Export(
+	ctx context.Context,
+	req *collectlogs.ExportLogsServiceRequest,
+) (*collectlogs.ExportLogsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	user, token, err := auth.VerifyBearerToken(ctx, s.authStorage, metadataValue(md, "authorization"))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ctx = auth.ContextWithUser(ctx, user)
+	if token != nil {
+		ctx = auth.ContextWithToken(ctx, token)
+	}
+
+	authorized, err := auth.VerifyAnyPermission(ctx, s.authStorage, models.SCOPE_SEND_LOGS)
+	if err != nil {
+		s.logger.ErrorContext(
+			ctx,
+			"Failed to verify user permission",
+			slog.String("user", user.Name),
+			slog.String("error", err.Error()),
+		)
+		return nil, status.Error(codes.Internal, "failed to verify user permission")
+	}
+
+	if !authorized {
+		return nil, status.Error(codes.PermissionDenied, "missing permission: send_logs")
+	}
+
+	canSendLogs, err := auth.ResourceFilter(ctx, s.authStorage, models.SCOPE_SEND_LOGS)
+	if err != nil {
+		s.logger.ErrorContext(
+			ctx,
+			"Failed to verify user permission",
+			slog.String("user", user.Name),
+			slog.String("error", err.Error()),
+		)
+		return nil, status.Error(codes.Internal, "failed to verify user permission")
+	}
+
+	defaultPipeline := metadataValue(md, PIPELINE_METADATA_KEY)
+	resourceLogs := req.GetResourceLogs()
+	pipelineNames := make([]string, len(resourceLogs))
+
+	for i, resource := range resourceLogs {
+		pipelineNames[i] = resourcePipeline(resource, defaultPipeline)
+		if pipelineNames[i] == "" {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"no pipeline specified, set the %q metadata or the %q resource attribute",
+				PIPELINE_METADATA_KEY,
+				PIPELINE_RESOURCE_ATTRIBUTE,
+			)
+		}
+
+		if !canSendLogs(pipelineNames[i]) {
+			return nil, status.Errorf(
+				codes.PermissionDenied,
+				"missing permission: send_logs on pipeline %q",
+				pipelineNames[i],
+			)
+		}
+	}
+
+	for i, resource := range resourceLogs {
+		pipelineName := pipelineNames[i]
+
+		for _, logRecord := range otlputils.ConvertResourceLogs(resource) {
+			err := s.pipelineRunner.Run(
+				ctx,
+				pipelineName,
+				pipelines.DIRECT_ENTRYPOINT,
+				logRecord,
+			)
+			if err != nil {
+				var notFoundErr *pipelines.PipelineNotFoundError
+				if errors.As(err, &notFoundErr) {
+					return nil, status.Error(codes.NotFound, err.Error())
+				}
+
+				s.logger.ErrorContext(
+					ctx,
+					"Failed to process log entry",
+					slog.String("pipeline", pipelineName),
+					slog.String("error", err.Error()),
+				)
+
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+	}
+
+	return &collectlogs.ExportLogsServiceResponse{}, nil
+}
+
+// resourcePipeline returns the pipeline named by the resource's attributes, or
+// defaultPipeline if it names none.
+func resourcePipeline(resource *otlplogmodels.ResourceLogs, defaultPipeline string) string {
+	for _, attribute := range resource.GetResource().GetAttributes() {
+		if attribute.GetKey() == PIPELINE_RESOURCE_ATTRIBUTE {
+			if name := attribute.GetValue().GetStringValue(); name != "" {
+				return name
+			}
+		}
+	}
+
+	return defaultPipeline
+}
+
+// metadataValue returns the first value of a gRPC metadata key, or an empty
+// string if it is not set.
+func metadataValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
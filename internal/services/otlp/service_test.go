@@ -0,0 +1,216 @@
+package otlp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	collectlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	otlpcommonmodels "go.opentelemetry.io/proto/otlp/common/v1"
+	otlplogmodels "go.opentelemetry.io/proto/otlp/logs/v1"
+	otlpresourcemodels "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/storage/mocks"
+)
+
+type recordingRunner struct {
+	runs map[string]int
+}
+
+func (r *recordingRunner) Run(
+	ctx context.Context,
+	pipelineName string,
+	entrypoint string,
+	record *models.LogRecord,
+) error {
+	r.runs[pipelineName]++
+	return nil
+}
+
+func (r *recordingRunner) ScrapMetrics(ctx context.Context, pipelineName string, w io.Writer) error {
+	return nil
+}
+
+func (r *recordingRunner) InvalidateCachedBuild(ctx context.Context, pipelineName string) error {
+	return nil
+}
+
+func (r *recordingRunner) InvalidateAllCachedBuilds(ctx context.Context) error {
+	return nil
+}
+
+func newResourceLogs(pipeline string, count int) *otlplogmodels.ResourceLogs {
+	resource := &otlpresourcemodels.Resource{}
+	if pipeline != "" {
+		resource.Attributes = []*otlpcommonmodels.KeyValue{
+			{
+				Key: PIPELINE_RESOURCE_ATTRIBUTE,
+				Value: &otlpcommonmodels.AnyValue{
+					Value: &otlpcommonmodels.AnyValue_StringValue{StringValue: pipeline},
+				},
+			},
+		}
+	}
+
+	records := make([]*otlplogmodels.LogRecord, count)
+	for i := range records {
+		records[i] = &otlplogmodels.LogRecord{
+			Body: &otlpcommonmodels.AnyValue{
+				Value: &otlpcommonmodels.AnyValue_StringValue{StringValue: "hello"},
+			},
+		}
+	}
+
+	return &otlplogmodels.ResourceLogs{
+		Resource:  resource,
+		ScopeLogs: []*otlplogmodels.ScopeLogs{{LogRecords: records}},
+	}
+}
+
+func newTestService(t *testing.T, authorized bool) (*logsService, *recordingRunner) {
+	authStorage := mocks.NewMockAuthStorage().(*mocks.MockAuthStorage)
+	authStorage.On("VerifyToken", mock.Anything, "pat_secret").
+		Return(&models.User{Name: "alice"}, &models.Token{UUID: "uuid"}, nil)
+	authStorage.On("VerifyToken", mock.Anything, "pat_reader").
+		Return(
+			&models.User{Name: "alice"},
+			&models.Token{UUID: "reader", Scopes: []models.Scope{models.SCOPE_READ_STREAMS}},
+			nil,
+		)
+	authStorage.On("VerifyToken", mock.Anything, "pat_shipper").
+		Return(&models.User{Name: "bob"}, &models.Token{UUID: "shipper"}, nil)
+	authStorage.On("VerifyToken", mock.Anything, mock.Anything).
+		Return((*models.User)(nil), (*models.Token)(nil), nil)
+	authStorage.On("VerifyUserPermission", mock.Anything, "alice", models.SCOPE_SEND_LOGS, mock.Anything).
+		Return(authorized, nil)
+	authStorage.On("ListUserResourceScopes", mock.Anything, "alice").
+		Return([]models.ResourceScope{}, nil).
+		Maybe()
+	authStorage.On("VerifyUserPermission", mock.Anything, "bob", models.SCOPE_SEND_LOGS, mock.Anything).
+		Return(false, nil).
+		Maybe()
+	authStorage.On("ListUserResourceScopes", mock.Anything, "bob").
+		Return([]models.ResourceScope{"send_logs:pipeline=default"}, nil).
+		Maybe()
+
+	runner := &recordingRunner{runs: map[string]int{}}
+
+	return &logsService{
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		authStorage:    authStorage,
+		pipelineRunner: runner,
+	}, runner
+}
+
+func TestExportRoutesResourcesToPipelines(t *testing.T) {
+	svc, runner := newTestService(t, true)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer pat_secret",
+		PIPELINE_METADATA_KEY, "default",
+	))
+
+	_, err := svc.Export(ctx, &collectlogs.ExportLogsServiceRequest{
+		ResourceLogs: []*otlplogmodels.ResourceLogs{
+			newResourceLogs("", 2),
+			newResourceLogs("audit", 1),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"default": 2, "audit": 1}, runner.runs)
+}
+
+func TestExportHonorsResourceScopes(t *testing.T) {
+	svc, runner := newTestService(t, true)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer pat_shipper",
+		PIPELINE_METADATA_KEY, "default",
+	))
+
+	_, err := svc.Export(ctx, &collectlogs.ExportLogsServiceRequest{
+		ResourceLogs: []*otlplogmodels.ResourceLogs{newResourceLogs("", 1)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"default": 1}, runner.runs)
+
+	_, err = svc.Export(ctx, &collectlogs.ExportLogsServiceRequest{
+		ResourceLogs: []*otlplogmodels.ResourceLogs{
+			newResourceLogs("", 1),
+			newResourceLogs("audit", 1),
+		},
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, map[string]int{"default": 1}, runner.runs)
+}
+
+func TestExportRejectsRequests(t *testing.T) {
+	testCases := []struct {
+		name       string
+		authorized bool
+		md         metadata.MD
+		code       codes.Code
+	}{
+		{
+			name:       "missing token",
+			authorized: true,
+			md:         metadata.Pairs(PIPELINE_METADATA_KEY, "default"),
+			code:       codes.Unauthenticated,
+		},
+		{
+			name:       "invalid token",
+			authorized: true,
+			md:         metadata.Pairs("authorization", "Bearer pat_invalid", PIPELINE_METADATA_KEY, "default"),
+			code:       codes.Unauthenticated,
+		},
+		{
+			name:       "missing permission",
+			authorized: false,
+			md:         metadata.Pairs("authorization", "Bearer pat_secret", PIPELINE_METADATA_KEY, "default"),
+			code:       codes.PermissionDenied,
+		},
+		{
+			name:       "token restricted to other scopes",
+			authorized: true,
+			md:         metadata.Pairs("authorization", "Bearer pat_reader", PIPELINE_METADATA_KEY, "default"),
+			code:       codes.PermissionDenied,
+		},
+		{
+			name:       "pipeline outside of the resource scopes of the user",
+			authorized: true,
+			md:         metadata.Pairs("authorization", "Bearer pat_shipper", PIPELINE_METADATA_KEY, "audit"),
+			code:       codes.PermissionDenied,
+		},
+		{
+			name:       "missing pipeline",
+			authorized: true,
+			md:         metadata.Pairs("authorization", "Bearer pat_secret"),
+			code:       codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, runner := newTestService(t, tc.authorized)
+
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+			_, err := svc.Export(ctx, &collectlogs.ExportLogsServiceRequest{
+				ResourceLogs: []*otlplogmodels.ResourceLogs{newResourceLogs("", 1)},
+			})
+
+			assert.Equal(t, tc.code, status.Code(err))
+			assert.Empty(t, runner.runs)
+		})
+	}
+}
@@ -11,122 +11,166 @@ import (
 	"go.uber.org/fx"
 
 	gosyslog "gopkg.in/mcuadros/go-syslog.v2"
+	gosyslogformat "gopkg.in/mcuadros/go-syslog.v2/format"
 
 	storage "link-society.com/flowg/internal/storage/interfaces"
 
 	"link-society.com/flowg/internal/engines/pipelines"
 )
 
-// ServerOptions configures the syslog server: UDP (default) or TCP, where to
-// bind, and an optional TLS configuration (TCP only).
+// Message formats accepted by a listener.
+const (
+	// FORMAT_AUTO detects the format and framing of each message.
+	FORMAT_AUTO = "auto"
+	// FORMAT_RFC3164 accepts BSD syslog messages, one per line over TCP.
+	FORMAT_RFC3164 = "rfc3164"
+	// FORMAT_RFC5424 accepts RFC 5424 messages, one per line over TCP.
+	FORMAT_RFC5424 = "rfc5424"
+	// FORMAT_RFC6587 accepts RFC 5424 messages, octet-counted over TCP.
+	FORMAT_RFC6587 = "rfc6587"
+)
+
+var formats = map[string]gosyslogformat.Format{
+	FORMAT_AUTO:    gosyslog.Automatic,
+	FORMAT_RFC3164: gosyslog.RFC3164,
+	FORMAT_RFC5424: gosyslog.RFC5424,
+	FORMAT_RFC6587: gosyslog.RFC6587,
+}
+
+// IsValidFormat reports whether format is a message format accepted by
+// [ServerOptions].
+func IsValidFormat(format string) bool {
+	_, ok := formats[format]
+	return ok
+}
+
+// ServerOptions configures one syslog listener: its name, UDP (default) or TCP,
+// where to bind, the format of the messages (auto-detected if empty), an
+// optional TLS configuration (TCP only), the client IPs or
+// CIDR ranges and the client certificate subjects it accepts messages from (any
+// if empty), and the pipeline receiving the messages matched by no syslog route
+// (every pipeline if empty).
 type ServerOptions struct {
-	TcpMode     bool
-	BindAddress string
-	TlsConfig   *tls.Config
+	Name            string
+	TcpMode         bool
+	BindAddress     string
+	Format          string
+	TlsConfig       *tls.Config
+	AllowedOrigins  []string
+	AllowedSubjects []string
+	Pipeline        string
 }
 
-// Server is the running syslog service: an actor that drains received messages
+// Server is a running syslog listener: an actor that drains received messages
 // into the pipeline engine.
 type Server struct {
 	actor.Actor
 }
 
-// NewServer returns an fx module that listens for syslog messages (auto-detecting
-// the format) and feeds each one, through the worker actor, into every pipeline's
-// syslog entrypoint. Listener and actor are bound to the application lifecycle.
+// NewServer returns an fx module that listens for syslog messages in the
+// configured format and feeds each one, through the worker actor, into the
+// syslog entrypoint of the pipelines it is routed to. Listener and actor are
+// bound to the application lifecycle.
+//
+// One module is created per listener. The resulting [Server] is provided in the
+// "syslog_servers" value group.
 func NewServer(opts ServerOptions) fx.Option {
 	proto := "udp"
 	if opts.TcpMode {
 		proto = "tcp"
 	}
 
+	if opts.Format == "" {
+		opts.Format = FORMAT_AUTO
+	}
+
+	format, ok := formats[opts.Format]
+	if !ok {
+		return fx.Error(fmt.Errorf("unsupported syslog format: %s", opts.Format))
+	}
+
 	logger := slog.Default().With(
 		slog.String("channel", "syslog"),
 		slog.Group("syslog",
+			slog.String("listener", opts.Name),
 			slog.String("proto", proto),
 			slog.String("bind", opts.BindAddress),
+			slog.String("format", opts.Format),
 			slog.Bool("tls", opts.TlsConfig != nil),
+			slog.String("pipeline", opts.Pipeline),
 		),
 	)
 
 	return fx.Module(
-		"services.syslog",
-		fx.Provide(func() gosyslog.LogPartsChannel {
-			return make(gosyslog.LogPartsChannel)
-		}),
-		fx.Provide(func(channel gosyslog.LogPartsChannel) *gosyslog.ChannelHandler {
-			return gosyslog.NewChannelHandler(channel)
-		}),
-		fx.Provide(func(lc fx.Lifecycle, handler *gosyslog.ChannelHandler) *gosyslog.Server {
-			server := gosyslog.NewServer()
-			server.SetFormat(gosyslog.Automatic)
-			server.SetHandler(handler)
-
-			lc.Append(fx.Hook{
-				OnStart: func(ctx context.Context) error {
-					logger.InfoContext(ctx, "Starting Syslog server")
-
-					switch {
-					case opts.TcpMode && opts.TlsConfig != nil:
-						if err := server.ListenTCPTLS(opts.BindAddress, opts.TlsConfig); err != nil {
-							return fmt.Errorf("failed to listen on TCP+TLS: %w", err)
+		"services.syslog."+opts.Name,
+		fx.Provide(fx.Annotate(
+			func(
+				lc fx.Lifecycle,
+				configStorage storage.ConfigStorage,
+				pipelineRunner pipelines.Runner,
+			) *Server {
+				channel := make(gosyslog.LogPartsChannel)
+
+				server := gosyslog.NewServer()
+				server.SetFormat(format)
+				server.SetHandler(gosyslog.NewChannelHandler(channel))
+				server.SetTlsPeerNameFunc(tlsPeerName)
+
+				srv := &Server{
+					Actor: actor.New(&worker{
+						logger:         logger,
+						channel:        channel,
+						configStorage:  configStorage,
+						pipelineRunner: pipelineRunner,
+
+						listener:        opts.Name,
+						tlsEnabled:      opts.TlsConfig != nil,
+						allowedOrigins:  opts.AllowedOrigins,
+						allowedSubjects: opts.AllowedSubjects,
+						defaultPipeline: opts.Pipeline,
+					}),
+				}
+
+				lc.Append(fx.Hook{
+					OnStart: func(ctx context.Context) error {
+						logger.InfoContext(ctx, "Starting Syslog server")
+
+						switch {
+						case opts.TcpMode && opts.TlsConfig != nil:
+							if err := server.ListenTCPTLS(opts.BindAddress, opts.TlsConfig); err != nil {
+								return fmt.Errorf("failed to listen on TCP+TLS: %w", err)
+							}
+
+						case opts.TcpMode && opts.TlsConfig == nil:
+							if err := server.ListenTCP(opts.BindAddress); err != nil {
+								return fmt.Errorf("failed to listen on TCP: %w", err)
+							}
+
+						case !opts.TcpMode:
+							if err := server.ListenUDP(opts.BindAddress); err != nil {
+								return fmt.Errorf("failed to listen on UDP: %w", err)
+							}
 						}
 
-					case opts.TcpMode && opts.TlsConfig == nil:
-						if err := server.ListenTCP(opts.BindAddress); err != nil {
-							return fmt.Errorf("failed to listen on TCP: %w", err)
+						if err := server.Boot(); err != nil {
+							return fmt.Errorf("failed to boot server: %w", err)
 						}
 
-					case !opts.TcpMode:
-						if err := server.ListenUDP(opts.BindAddress); err != nil {
-							return fmt.Errorf("failed to listen on UDP: %w", err)
-						}
-					}
-
-					if err := server.Boot(); err != nil {
-						return fmt.Errorf("failed to boot server: %w", err)
-					}
-
-					return nil
-				},
-				OnStop: func(ctx context.Context) error {
-					logger.InfoContext(ctx, "Stopping Syslog server")
-					return server.Kill()
-				},
-			})
-
-			return server
-		}),
-		fx.Provide(func(
-			lc fx.Lifecycle,
-			channel gosyslog.LogPartsChannel,
-			configStorage storage.ConfigStorage,
-			pipelineRunner pipelines.Runner,
-		) *Server {
-			srv := &Server{
-				Actor: actor.New(&worker{
-					logger:         logger,
-					channel:        channel,
-					configStorage:  configStorage,
-					pipelineRunner: pipelineRunner,
-				}),
-			}
-
-			lc.Append(fx.Hook{
-				OnStart: func(ctx context.Context) error {
-					srv.Start()
-					return nil
-				},
-				OnStop: func(ctx context.Context) error {
-					srv.Stop()
-					return nil
-				},
-			})
-
-			return srv
-		}),
-		fx.Invoke(func(*gosyslog.Server) {
-			// No-op, just to force the creation of all components
-		}),
+						srv.Start()
+						return nil
+					},
+					OnStop: func(ctx context.Context) error {
+						logger.InfoContext(ctx, "Stopping Syslog server")
+
+						err := server.Kill()
+						srv.Stop()
+						return err
+					},
+				})
+
+				return srv
+			},
+			fx.ResultTags(`group:"syslog_servers"`),
+		)),
 	)
 }
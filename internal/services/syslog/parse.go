@@ -2,15 +2,42 @@ package syslog
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	gosyslogformat "gopkg.in/mcuadros/go-syslog.v2/format"
 
 	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/timestamp"
 )
 
+// facilityNames are the keywords of the syslog facilities, indexed by code
+// (RFC 5424, section 6.2.1).
+var facilityNames = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// severityNames are the keywords of the syslog severities, indexed by code
+// (RFC 5424, section 6.2.1).
+var severityNames = []string{
+	"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug",
+}
+
 // parseLogParts converts the loosely-typed fields parsed by the syslog library
 // into a LogRecord, stringifying every value so it fits the flat field map.
-func parseLogParts(logParts gosyslogformat.LogParts) *models.LogRecord {
+//
+// The facility and severity are named after their keyword, their numeric
+// codes being kept in the "facility_code" and "severity_code" fields. RFC 5424
+// structured data is flattened into "sd.<id>.<param>" fields.
+//
+// The record is stamped with the message's timestamp when it has one within
+// the bounds of policy, and with the time of ingestion otherwise.
+func parseLogParts(
+	logParts gosyslogformat.LogParts,
+	policy models.TimestampPolicy,
+) *models.LogRecord {
 	fields := make(map[string]string, len(logParts))
 
 	for key, value := range logParts {
@@ -26,5 +53,120 @@ func parseLogParts(logParts gosyslogformat.LogParts) *models.LogRecord {
 		}
 	}
 
-	return models.NewLogRecord(fields)
+	if facility, ok := logParts["facility"].(int); ok {
+		fields["facility_code"] = fields["facility"]
+		fields["facility"] = codeName(facilityNames, facility)
+	}
+
+	if severity, ok := logParts["severity"].(int); ok {
+		fields["severity_code"] = fields["severity"]
+		fields["severity"] = codeName(severityNames, severity)
+	}
+
+	if structuredData, ok := logParts["structured_data"].(string); ok {
+		for key, value := range parseStructuredData(structuredData) {
+			fields[key] = value
+		}
+	}
+
+	record := models.NewLogRecord(fields)
+
+	if ts, ok := logParts["timestamp"].(time.Time); ok && !ts.IsZero() {
+		record.Timestamp = timestamp.ApplyPolicy(policy, ts, record.Timestamp)
+	}
+
+	return record
+}
+
+// codeName returns the keyword of a facility or severity code, or the code
+// itself if it has none.
+func codeName(names []string, code int) string {
+	if code >= 0 && code < len(names) {
+		return names[code]
+	}
+
+	return fmt.Sprintf("%d", code)
+}
+
+// parseStructuredData flattens the structured data of an RFC 5424 message,
+// such as `[origin ip="10.0.0.1"][meta sequenceId="1"]`, into fields named
+// "sd.<id>.<param>". Elements without parameters yield an empty "sd.<id>"
+// field. Parsing stops at the first malformed element, keeping the parameters
+// decoded so far.
+func parseStructuredData(data string) map[string]string {
+	fields := map[string]string{}
+
+	for strings.HasPrefix(data, "[") {
+		data = data[1:]
+
+		end := strings.IndexAny(data, " ]")
+		if end <= 0 {
+			return fields
+		}
+
+		id := data[:end]
+		data = data[end:]
+
+		hasParams := false
+
+		for strings.HasPrefix(data, " ") {
+			data = data[1:]
+
+			sep := strings.Index(data, `="`)
+			if sep <= 0 {
+				return fields
+			}
+
+			name := data[:sep]
+			data = data[sep+2:]
+
+			value, rest, ok := unescapeParamValue(data)
+			if !ok {
+				return fields
+			}
+
+			fields[fmt.Sprintf("sd.%s.%s", id, name)] = value
+			data = rest
+			hasParams = true
+		}
+
+		if !strings.HasPrefix(data, "]") {
+			return fields
+		}
+
+		data = data[1:]
+
+		if !hasParams {
+			fields[fmt.Sprintf("sd.%s", id)] = ""
+		}
+	}
+
+	return fields
+}
+
+// unescapeParamValue reads a structured data parameter value up to its
+// closing quote, unescaping `\"`, `\\` and `\]`. It returns the value and the
+// data following the closing quote.
+func unescapeParamValue(data string) (string, string, bool) {
+	var value strings.Builder
+
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; c {
+		case '"':
+			return value.String(), data[i+1:], true
+
+		case '\\':
+			if i+1 < len(data) && strings.IndexByte(`"\]`, data[i+1]) >= 0 {
+				i++
+				value.WriteByte(data[i])
+			} else {
+				value.WriteByte(c)
+			}
+
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return "", "", false
 }
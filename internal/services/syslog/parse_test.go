@@ -0,0 +1,86 @@
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	gosyslogformat "gopkg.in/mcuadros/go-syslog.v2/format"
+
+	"link-society.com/flowg/internal/models"
+)
+
+func TestParseStructuredData(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected map[string]string
+	}{
+		{
+			name:     "nil value",
+			data:     "-",
+			expected: map[string]string{},
+		},
+		{
+			name: "multiple elements",
+			data: `[exampleSDID@32473 iut="3" eventSource="Application"][origin ip="10.0.0.1"]`,
+			expected: map[string]string{
+				"sd.exampleSDID@32473.iut":         "3",
+				"sd.exampleSDID@32473.eventSource": "Application",
+				"sd.origin.ip":                     "10.0.0.1",
+			},
+		},
+		{
+			name: "escaped values",
+			data: `[meta text="say \"hi\" [ok\]" path="C:\\tmp" other="a\b"]`,
+			expected: map[string]string{
+				"sd.meta.text":  `say "hi" [ok]`,
+				"sd.meta.path":  `C:\tmp`,
+				"sd.meta.other": `a\b`,
+			},
+		},
+		{
+			name:     "element without parameters",
+			data:     `[timeQuality]`,
+			expected: map[string]string{"sd.timeQuality": ""},
+		},
+		{
+			name:     "malformed",
+			data:     `[origin ip="10.0.0.1"][meta sequenceId=1]`,
+			expected: map[string]string{"sd.origin.ip": "10.0.0.1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseStructuredData(tc.data))
+		})
+	}
+}
+
+func TestParseLogParts(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	record := parseLogParts(
+		gosyslogformat.LogParts{
+			"priority":        165,
+			"facility":        20,
+			"severity":        5,
+			"timestamp":       ts,
+			"app_name":        "nginx",
+			"structured_data": `[origin ip="10.0.0.1"]`,
+			"message":         "hello",
+		},
+		models.TimestampPolicy{},
+	)
+
+	assert.Equal(t, ts, record.Timestamp)
+	assert.Equal(t, "165", record.Fields["priority"])
+	assert.Equal(t, "local4", record.Fields["facility"])
+	assert.Equal(t, "20", record.Fields["facility_code"])
+	assert.Equal(t, "notice", record.Fields["severity"])
+	assert.Equal(t, "5", record.Fields["severity_code"])
+	assert.Equal(t, "10.0.0.1", record.Fields["sd.origin.ip"])
+	assert.Equal(t, "hello", record.Fields["message"])
+}
@@ -0,0 +1,136 @@
+package syslog
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// Record fields describing the verified client certificate of a TLS peer.
+// The TLS_PEER_FIELD holds the subject's common name, the others hold the
+// comma-separated subject alternative names of each kind.
+const (
+	TLS_PEER_FIELD           = "tls_peer"
+	TLS_PEER_CN_FIELD        = "tls_peer.cn"
+	TLS_PEER_SAN_DNS_FIELD   = "tls_peer.san.dns"
+	TLS_PEER_SAN_EMAIL_FIELD = "tls_peer.san.email"
+	TLS_PEER_SAN_IP_FIELD    = "tls_peer.san.ip"
+	TLS_PEER_SAN_URI_FIELD   = "tls_peer.san.uri"
+)
+
+// peerIdentity is the subject of the verified client certificate of a TLS
+// peer. It is carried, JSON-encoded, in the "tls_peer" part of the messages
+// parsed by the syslog library.
+type peerIdentity struct {
+	CommonName string   `json:"cn"`
+	DNSNames   []string `json:"dns,omitempty"`
+	Emails     []string `json:"email,omitempty"`
+	IPs        []string `json:"ip,omitempty"`
+	URIs       []string `json:"uri,omitempty"`
+}
+
+// tlsPeerName extracts the identity of a TLS peer once its handshake is done.
+// Peers without a certificate have no identity: the handshake only succeeds
+// for them when client authentication is not required.
+func tlsPeerName(conn *tls.Conn) (string, bool) {
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return "", true
+	}
+
+	cert := state.PeerCertificates[0]
+	identity := peerIdentity{
+		CommonName: cert.Subject.CommonName,
+		DNSNames:   cert.DNSNames,
+		Emails:     cert.EmailAddresses,
+	}
+
+	for _, ip := range cert.IPAddresses {
+		identity.IPs = append(identity.IPs, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		identity.URIs = append(identity.URIs, uri.String())
+	}
+
+	data, err := json.Marshal(identity)
+	if err != nil {
+		return "", false
+	}
+
+	return string(data), true
+}
+
+// decodePeerIdentity decodes the identity encoded by tlsPeerName, returning
+// nil if the peer has none.
+func decodePeerIdentity(tlsPeer string) *peerIdentity {
+	if tlsPeer == "" {
+		return nil
+	}
+
+	identity := &peerIdentity{}
+	if err := json.Unmarshal([]byte(tlsPeer), identity); err != nil {
+		return nil
+	}
+
+	return identity
+}
+
+// subjects returns the common name and every subject alternative name of the
+// identity.
+func (p *peerIdentity) subjects() []string {
+	subjects := []string{}
+
+	if p.CommonName != "" {
+		subjects = append(subjects, p.CommonName)
+	}
+
+	subjects = append(subjects, p.DNSNames...)
+	subjects = append(subjects, p.Emails...)
+	subjects = append(subjects, p.IPs...)
+	subjects = append(subjects, p.URIs...)
+
+	return subjects
+}
+
+// fields returns the record fields describing the identity.
+func (p *peerIdentity) fields() map[string]string {
+	fields := map[string]string{
+		TLS_PEER_FIELD:    p.CommonName,
+		TLS_PEER_CN_FIELD: p.CommonName,
+	}
+
+	sans := map[string][]string{
+		TLS_PEER_SAN_DNS_FIELD:   p.DNSNames,
+		TLS_PEER_SAN_EMAIL_FIELD: p.Emails,
+		TLS_PEER_SAN_IP_FIELD:    p.IPs,
+		TLS_PEER_SAN_URI_FIELD:   p.URIs,
+	}
+
+	for field, values := range sans {
+		if len(values) > 0 {
+			fields[field] = strings.Join(values, ",")
+		}
+	}
+
+	return fields
+}
+
+// matchSubject reports whether one of the subjects of identity matches one of
+// patterns, using the syntax of [path.Match]. A nil identity matches nothing.
+func matchSubject(patterns []string, identity *peerIdentity) bool {
+	if identity == nil {
+		return false
+	}
+
+	for _, subject := range identity.subjects() {
+		for _, pattern := range patterns {
+			if matched, err := path.Match(pattern, subject); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+package syslog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerIdentityFields(t *testing.T) {
+	peer := decodePeerIdentity(`{"cn":"web-1","dns":["web-1.example.com","web.example.com"],"ip":["10.0.0.1"]}`)
+	require.NotNil(t, peer)
+
+	assert.Equal(
+		t,
+		map[string]string{
+			TLS_PEER_FIELD:         "web-1",
+			TLS_PEER_CN_FIELD:      "web-1",
+			TLS_PEER_SAN_DNS_FIELD: "web-1.example.com,web.example.com",
+			TLS_PEER_SAN_IP_FIELD:  "10.0.0.1",
+		},
+		peer.fields(),
+	)
+}
+
+func TestDecodePeerIdentityWithoutCertificate(t *testing.T) {
+	assert.Nil(t, decodePeerIdentity(""))
+	assert.Nil(t, decodePeerIdentity("not json"))
+}
+
+func TestMatchSubject(t *testing.T) {
+	peer := &peerIdentity{
+		CommonName: "web-1",
+		DNSNames:   []string{"web-1.example.com"},
+		Emails:     []string{"ops@example.com"},
+	}
+
+	assert.True(t, matchSubject([]string{"web-1"}, peer))
+	assert.True(t, matchSubject([]string{"*.example.com"}, peer))
+	assert.True(t, matchSubject([]string{"db-*", "ops@*"}, peer))
+	assert.False(t, matchSubject([]string{"db-*"}, peer))
+	assert.False(t, matchSubject([]string{"*"}, nil))
+}
@@ -0,0 +1,85 @@
+package syslog
+
+import (
+	"net"
+	"slices"
+
+	gosyslogformat "gopkg.in/mcuadros/go-syslog.v2/format"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// LISTENER_FIELD is the record field holding the name of the listener that
+// received a message.
+const LISTENER_FIELD = "listener"
+
+// matchOrigin reports whether ip is one of origins, given as exact IPs or CIDR
+// ranges.
+func matchOrigin(origins []string, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, origin := range origins {
+		if originIP := net.ParseIP(origin); originIP != nil && originIP.Equal(ip) {
+			return true
+		}
+
+		_, ipNet, err := net.ParseCIDR(origin)
+		if err != nil {
+			continue
+		}
+
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// messageAppName returns the name of the application that sent a message: the
+// APP-NAME of RFC 5424 messages, or the TAG of RFC 3164 ones.
+func messageAppName(logParts gosyslogformat.LogParts) string {
+	if appName, ok := logParts["app_name"].(string); ok && appName != "" {
+		return appName
+	}
+
+	if tag, ok := logParts["tag"].(string); ok {
+		return tag
+	}
+
+	return ""
+}
+
+// routePipelines returns the pipelines of every route matching a message sent
+// by appName from clientIP to listener, without duplicates and in the order of
+// the routes.
+func routePipelines(
+	routes []models.SyslogRoute,
+	listener string,
+	clientIP net.IP,
+	appName string,
+) []string {
+	var pipelineNames []string
+
+	for _, route := range routes {
+		if len(route.Listeners) > 0 && !slices.Contains(route.Listeners, listener) {
+			continue
+		}
+
+		if len(route.Origins) > 0 && !matchOrigin(route.Origins, clientIP) {
+			continue
+		}
+
+		if len(route.AppNames) > 0 && !slices.Contains(route.AppNames, appName) {
+			continue
+		}
+
+		if !slices.Contains(pipelineNames, route.Pipeline) {
+			pipelineNames = append(pipelineNames, route.Pipeline)
+		}
+	}
+
+	return pipelineNames
+}
@@ -0,0 +1,86 @@
+package syslog
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gosyslogformat "gopkg.in/mcuadros/go-syslog.v2/format"
+
+	"link-society.com/flowg/internal/models"
+)
+
+func TestRoutePipelines(t *testing.T) {
+	routes := []models.SyslogRoute{
+		{Pipeline: "web", AppNames: []string{"nginx", "haproxy"}},
+		{Pipeline: "lan", Origins: []string{"10.0.0.0/8"}},
+		{Pipeline: "lan-web", Origins: []string{"10.0.0.0/8"}, AppNames: []string{"nginx"}},
+		{Pipeline: "web", Origins: []string{"192.168.1.10"}},
+		{Pipeline: "network", Listeners: []string{"legacy"}},
+	}
+
+	testCases := []struct {
+		name     string
+		listener string
+		clientIP net.IP
+		appName  string
+		expected []string
+	}{
+		{
+			name:     "app name",
+			clientIP: net.ParseIP("172.16.0.1"),
+			appName:  "haproxy",
+			expected: []string{"web"},
+		},
+		{
+			name:     "origin range",
+			clientIP: net.ParseIP("10.1.2.3"),
+			appName:  "sshd",
+			expected: []string{"lan"},
+		},
+		{
+			name:     "all criteria",
+			clientIP: net.ParseIP("10.1.2.3"),
+			appName:  "nginx",
+			expected: []string{"web", "lan", "lan-web"},
+		},
+		{
+			name:     "deduplicated",
+			clientIP: net.ParseIP("192.168.1.10"),
+			appName:  "nginx",
+			expected: []string{"web"},
+		},
+		{
+			name:     "no match",
+			clientIP: net.ParseIP("172.16.0.1"),
+			appName:  "sshd",
+			expected: nil,
+		},
+		{
+			name:     "listener",
+			listener: "legacy",
+			clientIP: net.ParseIP("172.16.0.1"),
+			appName:  "sshd",
+			expected: []string{"network"},
+		},
+		{
+			name:     "unknown client",
+			clientIP: nil,
+			appName:  "sshd",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, routePipelines(routes, tc.listener, tc.clientIP, tc.appName))
+		})
+	}
+}
+
+func TestMessageAppName(t *testing.T) {
+	assert.Equal(t, "nginx", messageAppName(gosyslogformat.LogParts{"app_name": "nginx"}))
+	assert.Equal(t, "sshd", messageAppName(gosyslogformat.LogParts{"tag": "sshd"}))
+	assert.Equal(t, "", messageAppName(gosyslogformat.LogParts{}))
+}
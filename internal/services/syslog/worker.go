@@ -22,14 +22,28 @@ type worker struct {
 	channel        gosyslog.LogPartsChannel
 	configStorage  storage.ConfigStorage
 	pipelineRunner pipelines.Runner
+
+	listener        string
+	tlsEnabled      bool
+	allowedOrigins  []string
+	allowedSubjects []string
+	defaultPipeline string
 }
 
 var _ actor.Worker = (*worker)(nil)
 
-// DoWork handles one received syslog message: it enforces the configured
-// allowed-origin list (exact IPs or CIDR ranges), then runs the message through
-// every pipeline's syslog entrypoint concurrently, logging but not aborting on
-// per-pipeline errors.
+// DoWork handles one received syslog message: it enforces the allowed-origin
+// lists (exact IPs or CIDR ranges) and allowed-subject lists (client
+// certificate subjects) of the listener and of the system configuration, then
+// runs the message through the syslog entrypoint of the selected pipelines
+// concurrently, logging but not aborting on per-pipeline errors. The system
+// configuration's allowed subjects only apply to TLS listeners, since no other
+// client can present a certificate.
+//
+// The pipelines are selected by the system configuration's syslog routes. If no
+// route matches, the message goes to the listener's default pipeline, or to
+// every pipeline if the listener has none. Records carry the listener's name in
+// the "listener" field.
 func (w *worker) DoWork(ctx actor.Context) actor.WorkerStatus {
 	select {
 	case <-ctx.Done():
@@ -50,47 +64,58 @@ func (w *worker) DoWork(ctx actor.Context) actor.WorkerStatus {
 			return actor.WorkerContinue
 		}
 
-		if len(systemConfig.SyslogAllowedOrigins) > 0 {
-			// no logging here to avoid potential performance issues
-
-			client := logParts["client"].(string)
-			clientIp, _, err := net.SplitHostPort(client)
-			if err != nil {
-				return actor.WorkerContinue
+		var clientIp net.IP
+		if client, ok := logParts["client"].(string); ok {
+			if host, _, err := net.SplitHostPort(client); err == nil {
+				clientIp = net.ParseIP(host)
 			}
+		}
 
-			allowed := false
+		// no logging here to avoid potential performance issues
+		if len(w.allowedOrigins) > 0 && !matchOrigin(w.allowedOrigins, clientIp) {
+			return actor.WorkerContinue
+		}
 
-			for _, origin := range systemConfig.SyslogAllowedOrigins {
-				if origin == clientIp {
-					allowed = true
-					break
-				}
+		if len(systemConfig.SyslogAllowedOrigins) > 0 {
+			if !matchOrigin(systemConfig.SyslogAllowedOrigins, clientIp) {
+				return actor.WorkerContinue
+			}
+		}
 
-				_, ipNet, err := net.ParseCIDR(origin)
-				if err != nil {
-					continue
-				}
+		tlsPeer, _ := logParts[TLS_PEER_FIELD].(string)
+		peer := decodePeerIdentity(tlsPeer)
 
-				if ipNet.Contains(net.ParseIP(clientIp)) {
-					allowed = true
-					break
-				}
-			}
+		if len(w.allowedSubjects) > 0 && !matchSubject(w.allowedSubjects, peer) {
+			return actor.WorkerContinue
+		}
 
-			if !allowed {
+		if w.tlsEnabled && len(systemConfig.SyslogAllowedSubjects) > 0 {
+			if !matchSubject(systemConfig.SyslogAllowedSubjects, peer) {
 				return actor.WorkerContinue
 			}
 		}
 
-		pipelineNames, err := w.configStorage.ListPipelines(ctx)
-		if err != nil {
-			w.logger.ErrorContext(
-				ctx,
-				"Failed to list pipelines",
-				slog.String("error", err.Error()),
-			)
-			return actor.WorkerContinue
+		pipelineNames := routePipelines(
+			systemConfig.SyslogRoutes,
+			w.listener,
+			clientIp,
+			messageAppName(logParts),
+		)
+
+		if len(pipelineNames) == 0 && w.defaultPipeline != "" {
+			pipelineNames = []string{w.defaultPipeline}
+		}
+
+		if len(pipelineNames) == 0 {
+			pipelineNames, err = w.configStorage.ListPipelines(ctx)
+			if err != nil {
+				w.logger.ErrorContext(
+					ctx,
+					"Failed to list pipelines",
+					slog.String("error", err.Error()),
+				)
+				return actor.WorkerContinue
+			}
 		}
 
 		wg := sync.WaitGroup{}
@@ -100,7 +125,14 @@ func (w *worker) DoWork(ctx actor.Context) actor.WorkerStatus {
 			go func(pipelineName string) {
 				defer wg.Done()
 
-				record := parseLogParts(logParts)
+				record := parseLogParts(logParts, systemConfig.TimestampPolicy)
+				record.Fields[LISTENER_FIELD] = w.listener
+				record.Fields[TLS_PEER_FIELD] = ""
+				if peer != nil {
+					for key, value := range peer.fields() {
+						record.Fields[key] = value
+					}
+				}
 
 				err := w.pipelineRunner.Run(
 					ctx,
@@ -0,0 +1,108 @@
+package auth_test
+
+import (
+	"testing"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// TestLinkedUserLifecycle exercises the links between auth provider identities
+// and users: creation, the refusal to link an existing user, and their cleanup
+// on DeleteUser and DeleteAuthProvider.
+func TestLinkedUserLifecycle(t *testing.T) {
+	ctx, authStorage := newAuthStorage(t)
+
+	if err := authStorage.SaveUser(ctx, models.User{Name: "root", Roles: []string{"admin"}}, "s3cret"); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+
+	// An identity is never linked to an existing user.
+	created, err := authStorage.CreateLinkedUser(ctx, "corp", "id-root", models.User{Name: "root", Roles: []string{}}, "sso")
+	if err != nil {
+		t.Fatalf("failed to create linked user: %v", err)
+	}
+	if created {
+		t.Fatal("expected an existing user not to be linked")
+	}
+
+	username, err := authStorage.FetchLinkedUser(ctx, "corp", "id-root")
+	if err != nil {
+		t.Fatalf("failed to fetch linked user: %v", err)
+	}
+	if username != "" {
+		t.Fatalf("expected no linked user, got %q", username)
+	}
+
+	created, err = authStorage.CreateLinkedUser(ctx, "corp", "id-alice", models.User{Name: "alice", Roles: []string{}}, "sso")
+	if err != nil {
+		t.Fatalf("failed to create linked user: %v", err)
+	}
+	if !created {
+		t.Fatal("expected the user to be created")
+	}
+
+	username, err = authStorage.FetchLinkedUser(ctx, "corp", "id-alice")
+	if err != nil {
+		t.Fatalf("failed to fetch linked user: %v", err)
+	}
+	if username != "alice" {
+		t.Fatalf("expected alice to be linked, got %q", username)
+	}
+
+	// The link is scoped to the auth provider.
+	username, err = authStorage.FetchLinkedUser(ctx, "other", "id-alice")
+	if err != nil {
+		t.Fatalf("failed to fetch linked user: %v", err)
+	}
+	if username != "" {
+		t.Fatalf("expected no linked user for another provider, got %q", username)
+	}
+
+	// Deleting the user removes the link.
+	if err := authStorage.DeleteUser(ctx, "alice"); err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+
+	username, err = authStorage.FetchLinkedUser(ctx, "corp", "id-alice")
+	if err != nil {
+		t.Fatalf("failed to fetch linked user: %v", err)
+	}
+	if username != "" {
+		t.Fatalf("expected the link to be removed with the user, got %q", username)
+	}
+
+	// Deleting the auth provider removes its links, but not the users.
+	created, err = authStorage.CreateLinkedUser(ctx, "corp", "id-bob", models.User{Name: "bob", Roles: []string{}}, "sso")
+	if err != nil || !created {
+		t.Fatalf("failed to create linked user: %v", err)
+	}
+
+	if err := authStorage.DeleteAuthProvider(ctx, "corp"); err != nil {
+		t.Fatalf("failed to delete auth provider: %v", err)
+	}
+
+	username, err = authStorage.FetchLinkedUser(ctx, "corp", "id-bob")
+	if err != nil {
+		t.Fatalf("failed to fetch linked user: %v", err)
+	}
+	if username != "" {
+		t.Fatalf("expected the link to be removed with the auth provider, got %q", username)
+	}
+
+	user, err := authStorage.FetchUser(ctx, "bob")
+	if err != nil {
+		t.Fatalf("failed to fetch user: %v", err)
+	}
+	if user == nil {
+		t.Fatal("expected the user to be kept")
+	}
+
+	// A later identity of the same name is not linked to the leftover user.
+	created, err = authStorage.CreateLinkedUser(ctx, "corp", "id-bob", models.User{Name: "bob", Roles: []string{}}, "sso")
+	if err != nil {
+		t.Fatalf("failed to create linked user: %v", err)
+	}
+	if created {
+		t.Fatal("expected the leftover user not to be linked again")
+	}
+}
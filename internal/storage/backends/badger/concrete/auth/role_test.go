@@ -0,0 +1,102 @@
+package auth_test
+
+import (
+	"slices"
+	"testing"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// TestRoleResourceScopes exercises roles granting scopes on some resources
+// only: their round trip through storage, and how they are resolved when
+// verifying the permissions of a user.
+func TestRoleResourceScopes(t *testing.T) {
+	ctx, authStorage := newAuthStorage(t)
+
+	role := models.Role{
+		Name:   "payments",
+		Scopes: []models.Scope{models.SCOPE_READ_PIPELINES},
+		ResourceScopes: []models.ResourceScope{
+			"write_streams:payments-*",
+			"send_logs:pipeline=ingress-eu",
+		},
+	}
+	if err := authStorage.SaveRole(ctx, role); err != nil {
+		t.Fatalf("failed to save role: %v", err)
+	}
+
+	saved, err := authStorage.FetchRole(ctx, "payments")
+	if err != nil {
+		t.Fatalf("failed to fetch role: %v", err)
+	}
+	if !slices.Equal(saved.Scopes, role.Scopes) {
+		t.Fatalf("expected scopes %v, got %v", role.Scopes, saved.Scopes)
+	}
+	if len(saved.ResourceScopes) != 2 ||
+		!slices.Contains(saved.ResourceScopes, role.ResourceScopes[0]) ||
+		!slices.Contains(saved.ResourceScopes, role.ResourceScopes[1]) {
+		t.Fatalf("expected resource scopes %v, got %v", role.ResourceScopes, saved.ResourceScopes)
+	}
+
+	if err := authStorage.SaveUser(ctx, models.User{Name: "alice", Roles: []string{"payments"}}, "s3cret"); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		scope    models.Scope
+		resource models.Resource
+		expected bool
+	}{
+		{"global scope", models.SCOPE_READ_PIPELINES, nil, true},
+		{"resource scope without resource", models.SCOPE_READ_STREAMS, nil, false},
+		{"matching resource", models.SCOPE_WRITE_STREAMS, models.Resource{"stream": "payments-eu"}, true},
+		{"write implies read", models.SCOPE_READ_STREAMS, models.Resource{"stream": "payments-eu"}, true},
+		{"other resource", models.SCOPE_READ_STREAMS, models.Resource{"stream": "audit"}, false},
+		{"other parameter", models.SCOPE_READ_STREAMS, models.Resource{"pipeline": "payments-eu"}, false},
+		{"named parameter", models.SCOPE_SEND_LOGS, models.Resource{"pipeline": "ingress-eu"}, true},
+		{"other pipeline", models.SCOPE_SEND_LOGS, models.Resource{"pipeline": "ingress-us"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verified, err := authStorage.VerifyUserPermission(ctx, "alice", tc.scope, tc.resource)
+			if err != nil {
+				t.Fatalf("failed to verify permission: %v", err)
+			}
+			if verified != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, verified)
+			}
+		})
+	}
+
+	scopes, err := authStorage.ListUserScopes(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to list user scopes: %v", err)
+	}
+	if !slices.Equal(scopes, []models.Scope{models.SCOPE_READ_PIPELINES}) {
+		t.Fatalf("expected only the global scopes, got %v", scopes)
+	}
+
+	resourceScopes, err := authStorage.ListUserResourceScopes(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to list user resource scopes: %v", err)
+	}
+	if len(resourceScopes) != 2 {
+		t.Fatalf("expected the resource scopes of the role, got %v", resourceScopes)
+	}
+
+	// Saving the role again reconciles its resource scopes.
+	role.ResourceScopes = []models.ResourceScope{"read_streams:audit"}
+	if err := authStorage.SaveRole(ctx, role); err != nil {
+		t.Fatalf("failed to save role: %v", err)
+	}
+
+	resourceScopes, err = authStorage.ListUserResourceScopes(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to list user resource scopes: %v", err)
+	}
+	if !slices.Equal(resourceScopes, role.ResourceScopes) {
+		t.Fatalf("expected resource scopes %v, got %v", role.ResourceScopes, resourceScopes)
+	}
+}
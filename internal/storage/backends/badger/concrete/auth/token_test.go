@@ -2,7 +2,9 @@ package auth_test
 
 import (
 	"context"
+	"slices"
 	"testing"
+	"time"
 
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxtest"
@@ -44,13 +46,14 @@ func TestTokenLifecycle(t *testing.T) {
 		t.Fatalf("failed to save user: %v", err)
 	}
 
-	token, tokenUUID, err := authStorage.CreateToken(ctx, "alice")
+	token, tokenInfo, err := authStorage.CreateToken(ctx, "alice", models.Token{Name: "laptop"})
 	if err != nil {
 		t.Fatalf("failed to create token: %v", err)
 	}
+	tokenUUID := tokenInfo.UUID
 
 	// A valid token resolves to its owner.
-	user, err := authStorage.VerifyToken(ctx, token)
+	user, _, err := authStorage.VerifyToken(ctx, token)
 	if err != nil {
 		t.Fatalf("failed to verify token: %v", err)
 	}
@@ -59,7 +62,7 @@ func TestTokenLifecycle(t *testing.T) {
 	}
 
 	// An unknown token resolves to nothing, without error.
-	unknown, err := authStorage.VerifyToken(ctx, "pat_unknown")
+	unknown, _, err := authStorage.VerifyToken(ctx, "pat_unknown")
 	if err != nil {
 		t.Fatalf("unexpected error verifying unknown token: %v", err)
 	}
@@ -68,19 +71,19 @@ func TestTokenLifecycle(t *testing.T) {
 	}
 
 	// The token shows up in its owner's list.
-	uuids, err := authStorage.ListTokens(ctx, "alice")
+	tokens, err := authStorage.ListTokens(ctx, "alice")
 	if err != nil {
 		t.Fatalf("failed to list tokens: %v", err)
 	}
-	if len(uuids) != 1 || uuids[0] != tokenUUID {
-		t.Fatalf("expected [%s], got %v", tokenUUID, uuids)
+	if len(tokens) != 1 || tokens[0].UUID != tokenUUID || tokens[0].Name != "laptop" {
+		t.Fatalf("expected [%s], got %+v", tokenUUID, tokens)
 	}
 
 	// Revoking the token makes it (and its index entry) unusable.
 	if err := authStorage.DeleteToken(ctx, "alice", tokenUUID); err != nil {
 		t.Fatalf("failed to delete token: %v", err)
 	}
-	revoked, err := authStorage.VerifyToken(ctx, token)
+	revoked, _, err := authStorage.VerifyToken(ctx, token)
 	if err != nil {
 		t.Fatalf("failed to verify revoked token: %v", err)
 	}
@@ -90,14 +93,14 @@ func TestTokenLifecycle(t *testing.T) {
 
 	// Deleting the user must also drop the reverse index of their tokens, so a
 	// still-held token can never resolve to the deleted account.
-	token2, _, err := authStorage.CreateToken(ctx, "alice")
+	token2, _, err := authStorage.CreateToken(ctx, "alice", models.Token{Name: "ci"})
 	if err != nil {
 		t.Fatalf("failed to create second token: %v", err)
 	}
 	if err := authStorage.DeleteUser(ctx, "alice"); err != nil {
 		t.Fatalf("failed to delete user: %v", err)
 	}
-	orphan, err := authStorage.VerifyToken(ctx, token2)
+	orphan, _, err := authStorage.VerifyToken(ctx, token2)
 	if err != nil {
 		t.Fatalf("failed to verify token of deleted user: %v", err)
 	}
@@ -105,3 +108,65 @@ func TestTokenLifecycle(t *testing.T) {
 		t.Fatalf("expected token of deleted user to resolve to nil, got %+v", orphan)
 	}
 }
+
+// TestTokenDescription checks that a token keeps its name and scopes, records
+// its last use, and stops resolving once expired.
+func TestTokenDescription(t *testing.T) {
+	ctx, authStorage := newAuthStorage(t)
+
+	if err := authStorage.SaveUser(ctx, models.User{Name: "alice", Roles: []string{}}, "s3cret"); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	token, tokenInfo, err := authStorage.CreateToken(ctx, "alice", models.Token{
+		Name:      "shipper",
+		Scopes:    []models.Scope{models.SCOPE_SEND_LOGS},
+		ExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	if tokenInfo.CreatedAt == nil || tokenInfo.LastUsedAt != nil {
+		t.Fatalf("expected a new, unused token, got %+v", tokenInfo)
+	}
+
+	// The token resolves with its restrictions.
+	user, description, err := authStorage.VerifyToken(ctx, token)
+	if err != nil {
+		t.Fatalf("failed to verify token: %v", err)
+	}
+	if user == nil || description == nil || description.UUID != tokenInfo.UUID {
+		t.Fatalf("expected token to resolve, got %+v %+v", user, description)
+	}
+	if !description.AllowsScope(models.SCOPE_SEND_LOGS) || description.AllowsScope(models.SCOPE_READ_STREAMS) {
+		t.Fatalf("expected token to be restricted to send_logs, got %v", description.Scopes)
+	}
+
+	// Its use is recorded.
+	tokens, err := authStorage.ListTokens(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to list tokens: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].LastUsedAt == nil {
+		t.Fatalf("expected the use of the token to be recorded, got %+v", tokens)
+	}
+	if tokens[0].Name != "shipper" || !slices.Equal(tokens[0].Scopes, []models.Scope{models.SCOPE_SEND_LOGS}) {
+		t.Fatalf("expected the token description to be kept, got %+v", tokens[0])
+	}
+
+	// An expired token resolves to nothing, without error.
+	expiredAt := time.Now().Add(-time.Second)
+	expired, _, err := authStorage.CreateToken(ctx, "alice", models.Token{Name: "old", ExpiresAt: &expiredAt})
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	user, _, err = authStorage.VerifyToken(ctx, expired)
+	if err != nil {
+		t.Fatalf("failed to verify expired token: %v", err)
+	}
+	if user != nil {
+		t.Fatalf("expected expired token to resolve to nil, got %+v", user)
+	}
+}
@@ -0,0 +1,93 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"link-society.com/flowg/cmd/flowg-server/logging"
+	"link-society.com/flowg/internal/models"
+
+	badgerconfig "link-society.com/flowg/internal/storage/backends/badger/concrete/config"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// TestListDeadLettersPages guards the cursor used to replay a dead-letter queue
+// a page at a time, while replayed records are deleted from it.
+func TestListDeadLettersPages(t *testing.T) {
+	logging.Discard()
+
+	ctx := t.Context()
+
+	opts := badgerconfig.DefaultOptions()
+	opts.InMemory = true
+
+	var configStorage storage.ConfigStorage
+
+	app := fxtest.New(
+		t,
+		badgerconfig.NewStorage(opts),
+		fx.Populate(&configStorage),
+		fx.NopLogger,
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	start := time.Now()
+	for i := range 5 {
+		letter := models.DeadLetter{
+			Timestamp: start.Add(time.Duration(i) * time.Millisecond),
+			Forwarder: "test",
+			Error:     "unreachable",
+		}
+		if err := configStorage.PushDeadLetter(ctx, letter); err != nil {
+			t.Fatalf("failed to push dead letter: %v", err)
+		}
+	}
+
+	all, err := configStorage.ListDeadLetters(ctx, "test", "", 0)
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %v", err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("expected 5 dead letters, got %d", len(all))
+	}
+
+	// replay the first page, delivering its first record only
+	page, err := configStorage.ListDeadLetters(ctx, "test", "", 2)
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != all[0].ID || page[1].ID != all[1].ID {
+		t.Fatalf("unexpected first page: %v", page)
+	}
+	if err := configStorage.DeleteDeadLetter(ctx, "test", page[0].ID); err != nil {
+		t.Fatalf("failed to delete dead letter: %v", err)
+	}
+
+	page, err = configStorage.ListDeadLetters(ctx, "test", page[1].ID, 2)
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != all[2].ID || page[1].ID != all[3].ID {
+		t.Fatalf("unexpected second page: %v", page)
+	}
+
+	page, err = configStorage.ListDeadLetters(ctx, "test", page[1].ID, 2)
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %v", err)
+	}
+	if len(page) != 1 || page[0].ID != all[4].ID {
+		t.Fatalf("unexpected last page: %v", page)
+	}
+
+	all, err = configStorage.ListDeadLetters(ctx, "test", "", 0)
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("expected 4 dead letters, got %d", len(all))
+	}
+}
@@ -0,0 +1,76 @@
+package config_test
+
+import (
+	"testing"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"link-society.com/flowg/cmd/flowg-server/logging"
+	"link-society.com/flowg/internal/models"
+
+	badgerconfig "link-society.com/flowg/internal/storage/backends/badger/concrete/config"
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// TestListPipelinesCacheInvalidation guards against the cached list of
+// pipelines going stale when pipelines are written or deleted.
+func TestListPipelinesCacheInvalidation(t *testing.T) {
+	logging.Discard()
+
+	ctx := t.Context()
+
+	opts := badgerconfig.DefaultOptions()
+	opts.InMemory = true
+
+	var configStorage storage.ConfigStorage
+
+	app := fxtest.New(
+		t,
+		badgerconfig.NewStorage(opts),
+		fx.Populate(&configStorage),
+		fx.NopLogger,
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	expectPipelines := func(expected ...string) {
+		t.Helper()
+
+		names, err := configStorage.ListPipelines(ctx)
+		if err != nil {
+			t.Fatalf("failed to list pipelines: %v", err)
+		}
+
+		if len(names) != len(expected) {
+			t.Fatalf("expected pipelines %v, got %v", expected, names)
+		}
+		for i := range expected {
+			if names[i] != expected[i] {
+				t.Fatalf("expected pipelines %v, got %v", expected, names)
+			}
+		}
+	}
+
+	expectPipelines()
+
+	if err := configStorage.WritePipeline(ctx, "a", &models.FlowGraphV2{}); err != nil {
+		t.Fatalf("failed to write pipeline: %v", err)
+	}
+	expectPipelines("a")
+
+	if err := configStorage.WriteRawPipeline(ctx, "b", `{"version": 2}`); err != nil {
+		t.Fatalf("failed to write pipeline: %v", err)
+	}
+	expectPipelines("a", "b")
+
+	// Callers may modify the returned list without altering the cache.
+	names, _ := configStorage.ListPipelines(ctx)
+	names[0] = "modified"
+	expectPipelines("a", "b")
+
+	if err := configStorage.DeletePipeline(ctx, "a"); err != nil {
+		t.Fatalf("failed to delete pipeline: %v", err)
+	}
+	expectPipelines("b")
+}
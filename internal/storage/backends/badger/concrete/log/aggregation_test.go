@@ -0,0 +1,199 @@
+package log_test
+
+import (
+	"testing"
+
+	"time"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/langs/filtering"
+)
+
+// TestAggregateIndexedGroupBy guards against the index-only count path
+// disagreeing with a full scan: grouping by an indexed field must yield the same
+// counts either way, including the records lacking the field, which the index
+// cannot point at.
+func TestAggregateIndexedGroupBy(t *testing.T) {
+	ctx, logStorage := newBatchedStorage(t, 2)
+
+	const stream = "test"
+
+	if err := logStorage.ConfigureStream(ctx, stream, models.StreamConfig{
+		IndexedFields: []string{"level"},
+	}); err != nil {
+		t.Fatalf("failed to configure stream: %v", err)
+	}
+
+	for _, fields := range []map[string]string{
+		{"level": "error"},
+		{"level": "info"},
+		{"level": "info"},
+		{"level": "info"},
+		{"message": "no level"},
+	} {
+		if _, err := logStorage.Ingest(ctx, stream, models.NewLogRecord(fields)); err != nil {
+			t.Fatalf("failed to ingest record: %v", err)
+		}
+	}
+
+	from := time.Now().Add(-time.Minute)
+	to := time.Now().Add(time.Minute)
+
+	query := models.AggregationQuery{
+		Aggregations: []models.Aggregation{{Function: models.AGGREGATION_COUNT}},
+		GroupBy:      []string{"level"},
+	}
+
+	// A filter accepting everything forces the scan path.
+	scanFilter, err := filtering.Compile(`true`)
+	if err != nil {
+		t.Fatalf("failed to compile filter: %v", err)
+	}
+
+	for name, filter := range map[string]filtering.Filter{
+		"index": nil,
+		"scan":  scanFilter,
+	} {
+		buckets, err := logStorage.Aggregate(ctx, stream, from, to, filter, nil, query)
+		if err != nil {
+			t.Fatalf("%s: failed to aggregate logs: %v", name, err)
+		}
+
+		counts := map[string]float64{}
+		for _, bucket := range buckets {
+			counts[bucket.Group["level"]] = bucket.Values["count"]
+		}
+
+		expected := map[string]float64{"": 1, "error": 1, "info": 3}
+		if len(counts) != len(expected) {
+			t.Fatalf("%s: expected %v, got %v", name, expected, counts)
+		}
+		for level, count := range expected {
+			if counts[level] != count {
+				t.Fatalf("%s: expected %v, got %v", name, expected, counts)
+			}
+		}
+	}
+}
+
+// TestAggregateHistogramStatistics guards against records landing in the wrong
+// time bucket or non-numeric values skewing the numeric statistics.
+func TestAggregateHistogramStatistics(t *testing.T) {
+	ctx, logStorage := newBatchedStorage(t, 0)
+
+	const stream = "test"
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	for _, entry := range []struct {
+		offset  time.Duration
+		latency string
+		user    string
+	}{
+		{0, "10", "alice"},
+		{10 * time.Second, "30", "bob"},
+		{20 * time.Second, "n/a", "alice"},
+		{time.Minute, "5", "carol"},
+	} {
+		record := &models.LogRecord{
+			Timestamp: base.Add(entry.offset),
+			Fields:    map[string]string{"latency": entry.latency, "user": entry.user},
+		}
+		if _, err := logStorage.Ingest(ctx, stream, record); err != nil {
+			t.Fatalf("failed to ingest record: %v", err)
+		}
+	}
+
+	query := models.AggregationQuery{
+		Aggregations: []models.Aggregation{
+			{Function: models.AGGREGATION_COUNT},
+			{Function: models.AGGREGATION_COUNT_DISTINCT, Field: "user"},
+			{Function: models.AGGREGATION_MIN, Field: "latency"},
+			{Function: models.AGGREGATION_MAX, Field: "latency"},
+			{Function: models.AGGREGATION_AVG, Field: "latency"},
+		},
+		Interval: time.Minute,
+	}
+
+	buckets, err := logStorage.Aggregate(ctx, stream, base, base.Add(2*time.Minute), nil, nil, query)
+	if err != nil {
+		t.Fatalf("failed to aggregate logs: %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("expected 2 time buckets, got %d", len(buckets))
+	}
+
+	first := buckets[0]
+	if first.Timestamp == nil || !first.Timestamp.Equal(base) {
+		t.Fatalf("expected first bucket to start at %v, got %v", base, first.Timestamp)
+	}
+
+	expected := map[string]float64{
+		"count":                3,
+		"count_distinct(user)": 2,
+		"min(latency)":         10,
+		"max(latency)":         30,
+		"avg(latency)":         20,
+	}
+	for name, value := range expected {
+		if first.Values[name] != value {
+			t.Fatalf("expected %s = %v, got %v", name, value, first.Values[name])
+		}
+	}
+
+	if buckets[1].Values["count"] != 1 {
+		t.Fatalf("expected 1 record in the second bucket, got %v", buckets[1].Values["count"])
+	}
+}
+
+// TestAggregateIndexedGroupByBatches guards against the index-only count path
+// losing values or entries across batches, including a value whose encoding
+// extends the encoding of another one ("abc" and "abcd").
+func TestAggregateIndexedGroupByBatches(t *testing.T) {
+	ctx, logStorage := newBatchedStorage(t, 1)
+
+	const stream = "test"
+
+	if err := logStorage.ConfigureStream(ctx, stream, models.StreamConfig{
+		IndexedFields: []string{"tag"},
+	}); err != nil {
+		t.Fatalf("failed to configure stream: %v", err)
+	}
+
+	for _, tag := range []string{"abc", "abcd", "abcd", "b", "abc", "abcd"} {
+		record := models.NewLogRecord(map[string]string{"tag": tag})
+		if _, err := logStorage.Ingest(ctx, stream, record); err != nil {
+			t.Fatalf("failed to ingest record: %v", err)
+		}
+	}
+
+	buckets, err := logStorage.Aggregate(
+		ctx,
+		stream,
+		time.Now().Add(-time.Minute),
+		time.Now().Add(time.Minute),
+		nil,
+		nil,
+		models.AggregationQuery{
+			Aggregations: []models.Aggregation{{Function: models.AGGREGATION_COUNT}},
+			GroupBy:      []string{"tag"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to aggregate logs: %v", err)
+	}
+
+	counts := map[string]float64{}
+	for _, bucket := range buckets {
+		counts[bucket.Group["tag"]] += bucket.Values["count"]
+	}
+
+	expected := map[string]float64{"abc": 2, "abcd": 3, "b": 1}
+	if len(counts) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, counts)
+	}
+	for tag, count := range expected {
+		if counts[tag] != count {
+			t.Fatalf("expected %v, got %v", expected, counts)
+		}
+	}
+}
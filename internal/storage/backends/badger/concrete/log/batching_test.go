@@ -106,7 +106,7 @@ func TestDeleteStreamBatched(t *testing.T) {
 
 	from := time.Now().Add(-time.Minute)
 	to := time.Now().Add(time.Minute)
-	recs, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil)
+	recs, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs: %v", err)
 	}
@@ -143,9 +143,9 @@ func TestConfigureStreamBackfillAndUnindexBatched(t *testing.T) {
 	from := time.Now().Add(-time.Minute)
 	to := time.Now().Add(time.Minute)
 
-	errRecs, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
+	errRecs, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
 		"level": {"error"},
-	})
+	}, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs: %v", err)
 	}
@@ -153,9 +153,9 @@ func TestConfigureStreamBackfillAndUnindexBatched(t *testing.T) {
 		t.Fatalf("expected 3 back-filled error records, got %d", len(errRecs))
 	}
 
-	infoRecs, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
+	infoRecs, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
 		"level": {"info"},
-	})
+	}, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs: %v", err)
 	}
@@ -179,7 +179,7 @@ func TestConfigureStreamBackfillAndUnindexBatched(t *testing.T) {
 	}
 
 	// The records themselves are untouched by (un)indexing.
-	all, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil)
+	all, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs: %v", err)
 	}
@@ -248,7 +248,7 @@ func TestCollectGarbageBatched(t *testing.T) {
 
 	from := time.Now().Add(-time.Minute)
 	to := time.Now().Add(time.Minute)
-	recs, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil)
+	recs, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs: %v", err)
 	}
@@ -269,3 +269,77 @@ func TestCollectGarbageBatched(t *testing.T) {
 		)
 	}
 }
+
+// TestWalkLogsBatched guards against WalkLogs dropping or repeating records at
+// batch boundaries: with a tiny batch size the walk must span several
+// transactions and still visit every matching record exactly once, oldest
+// first.
+func TestWalkLogsBatched(t *testing.T) {
+	ctx, logStorage := newBatchedStorage(t, 3)
+
+	const stream = "test"
+
+	if err := logStorage.ConfigureStream(ctx, stream, models.StreamConfig{
+		IndexedFields: []string{"level"},
+	}); err != nil {
+		t.Fatalf("failed to configure stream: %v", err)
+	}
+
+	base := time.Now().Add(-time.Minute)
+	const n = 10
+	for i := 0; i < n; i++ {
+		level := "info"
+		if i%2 == 0 {
+			level = "error"
+		}
+
+		record := &models.LogRecord{
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Fields: map[string]string{
+				"seq":   fmt.Sprintf("%03d", i),
+				"level": level,
+			},
+		}
+		if _, err := logStorage.Ingest(ctx, stream, record); err != nil {
+			t.Fatalf("failed to ingest record: %v", err)
+		}
+	}
+
+	from := base.Add(-time.Minute)
+	to := time.Now().Add(time.Minute)
+
+	var seen []string
+	err := logStorage.WalkLogs(ctx, stream, from, to, nil, nil, func(rec *models.LogRecord) error {
+		seen = append(seen, rec.Fields["seq"])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk logs: %v", err)
+	}
+	if len(seen) != n {
+		t.Fatalf("expected %d records, got %d (%v)", n, len(seen), seen)
+	}
+	for i, seq := range seen {
+		if seq != fmt.Sprintf("%03d", i) {
+			t.Fatalf("expected records in chronological order, got %v", seen)
+		}
+	}
+
+	// Index constraints are honoured across batches too.
+	errors := 0
+	err = logStorage.WalkLogs(ctx, stream, from, to, nil, map[string][]string{
+		"level": {"error"},
+	}, func(rec *models.LogRecord) error {
+		if rec.Fields["level"] != "error" {
+			t.Fatalf("expected only error records, got level=%q", rec.Fields["level"])
+		}
+		errors++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk logs with indexing: %v", err)
+	}
+	if errors != n/2 {
+		t.Fatalf("expected %d error records, got %d", n/2, errors)
+	}
+}
@@ -62,7 +62,7 @@ func TestFetchLogsIndexingFilter(t *testing.T) {
 	to := time.Now().Add(time.Minute)
 
 	// Sanity: without indexing, every record in the window is returned.
-	all, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil)
+	all, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs: %v", err)
 	}
@@ -71,9 +71,9 @@ func TestFetchLogsIndexingFilter(t *testing.T) {
 	}
 
 	// Filtering on an indexed value must return ONLY the matching record.
-	errorOnly, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
+	errorOnly, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
 		"level": {"error"},
-	})
+	}, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs with indexing: %v", err)
 	}
@@ -85,9 +85,9 @@ func TestFetchLogsIndexingFilter(t *testing.T) {
 	}
 
 	// A value that no record carries must match nothing.
-	none, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
+	none, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
 		"level": {"debug"},
-	})
+	}, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs with indexing: %v", err)
 	}
@@ -202,7 +202,7 @@ func TestIngestOversizedIndexedValue(t *testing.T) {
 	to := time.Now().Add(time.Minute)
 
 	// The record is still stored and queryable by time.
-	all, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil)
+	all, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil, "", 0)
 	if err != nil {
 		t.Fatalf("failed to fetch logs: %v", err)
 	}
@@ -211,9 +211,9 @@ func TestIngestOversizedIndexedValue(t *testing.T) {
 	}
 
 	// Filtering by the oversized value must degrade to no match, not error.
-	filtered, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
+	filtered, _, err := logStorage.FetchLogs(ctx, stream, from, to, nil, map[string][]string{
 		"body": {hugeValue},
-	})
+	}, "", 0)
 	if err != nil {
 		t.Fatalf("filtering by oversized value should not error: %v", err)
 	}
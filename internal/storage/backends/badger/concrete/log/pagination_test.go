@@ -0,0 +1,150 @@
+package log_test
+
+import (
+	"testing"
+
+	"errors"
+	"strconv"
+	"time"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/langs/filtering"
+
+	storage "link-society.com/flowg/internal/storage/interfaces"
+)
+
+// TestFetchLogsPagination guards against pages overlapping or skipping records:
+// walking a stream with a small limit, following each returned cursor, must
+// yield every record exactly once, newest first, and end with an empty cursor.
+func TestFetchLogsPagination(t *testing.T) {
+	ctx, logStorage := newBatchedStorage(t, 0)
+
+	const stream = "test"
+
+	base := time.Now().Add(-time.Minute)
+	for i := range 7 {
+		record := &models.LogRecord{
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Fields:    map[string]string{"n": strconv.Itoa(i)},
+		}
+		if _, err := logStorage.Ingest(ctx, stream, record); err != nil {
+			t.Fatalf("failed to ingest record: %v", err)
+		}
+	}
+
+	from := base.Add(-time.Minute)
+	to := time.Now().Add(time.Minute)
+
+	var (
+		seen   []string
+		cursor string
+		pages  int
+	)
+
+	for {
+		recs, next, err := logStorage.FetchLogs(ctx, stream, from, to, nil, nil, cursor, 3)
+		if err != nil {
+			t.Fatalf("failed to fetch page %d: %v", pages, err)
+		}
+		if len(recs) > 3 {
+			t.Fatalf("page %d: expected at most 3 records, got %d", pages, len(recs))
+		}
+
+		for _, rec := range recs {
+			seen = append(seen, rec.Fields["n"])
+		}
+		pages++
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if pages != 3 {
+		t.Fatalf("expected 3 pages, got %d", pages)
+	}
+
+	expected := []string{"6", "5", "4", "3", "2", "1", "0"}
+	if len(seen) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, seen)
+		}
+	}
+}
+
+// TestFetchLogsPaginationWithFilter guards against the limit being applied
+// before the filter: a page must be filled with matching records only, and the
+// records rejected by the filter must not be counted against the limit.
+func TestFetchLogsPaginationWithFilter(t *testing.T) {
+	ctx, logStorage := newBatchedStorage(t, 0)
+
+	const stream = "test"
+
+	base := time.Now().Add(-time.Minute)
+	for i := range 6 {
+		level := "info"
+		if i%2 == 0 {
+			level = "error"
+		}
+
+		record := &models.LogRecord{
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Fields:    map[string]string{"level": level},
+		}
+		if _, err := logStorage.Ingest(ctx, stream, record); err != nil {
+			t.Fatalf("failed to ingest record: %v", err)
+		}
+	}
+
+	filter, err := filtering.Compile(`level == "error"`)
+	if err != nil {
+		t.Fatalf("failed to compile filter: %v", err)
+	}
+
+	from := base.Add(-time.Minute)
+	to := time.Now().Add(time.Minute)
+
+	recs, next, err := logStorage.FetchLogs(ctx, stream, from, to, filter, nil, "", 2)
+	if err != nil {
+		t.Fatalf("failed to fetch logs: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	for _, rec := range recs {
+		if rec.Fields["level"] != "error" {
+			t.Fatalf("expected only error records, got level=%q", rec.Fields["level"])
+		}
+	}
+	if next == "" {
+		t.Fatal("expected a cursor for the remaining records")
+	}
+
+	recs, _, err = logStorage.FetchLogs(ctx, stream, from, to, filter, nil, next, 2)
+	if err != nil {
+		t.Fatalf("failed to fetch second page: %v", err)
+	}
+	if len(recs) != 1 {
+		t.Fatalf("expected 1 record on the second page, got %d", len(recs))
+	}
+}
+
+// TestFetchLogsInvalidCursor guards against a forged cursor being silently
+// ignored (which would restart the scan from the newest record).
+func TestFetchLogsInvalidCursor(t *testing.T) {
+	ctx, logStorage := newBatchedStorage(t, 0)
+
+	from := time.Now().Add(-time.Minute)
+	to := time.Now().Add(time.Minute)
+
+	_, _, err := logStorage.FetchLogs(ctx, "test", from, to, nil, nil, "not-a-cursor", 10)
+
+	var cursorErr *storage.InvalidCursorError
+	if !errors.As(err, &cursorErr) {
+		t.Fatalf("expected an InvalidCursorError, got %v", err)
+	}
+}
@@ -53,91 +53,80 @@ func (txn *BadgerTx) Get(key kv.Key) (kv.Value, error) {
 // IterKeys implements [kv.QueryTx]. Values are not prefetched.
 func (txn *BadgerTx) IterKeys(prefix kv.Key, keyRange kv.KeyRange) iter.Seq[kv.Key] {
 	return func(yield func(kv.Key) bool) {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchValues = false
-		opts.Prefix = keyToBadgerPrefix(prefix)
-		it := txn.concrete.NewIterator(opts)
-		defer it.Close()
-
-		var (
-			fromPrefix []byte
-			toPrefix   string
-		)
-
-		if keyRange.From != nil {
-			fromPrefix = keyToBadgerPrefix(keyRange.From)
-		}
-		if keyRange.To != nil {
-			toPrefix = string(keyToBadgerPrefix(keyRange.To))
-		}
-
-		if fromPrefix != nil {
-			it.Seek(fromPrefix)
-		} else {
-			it.Rewind()
-		}
-		for it.Valid() {
-			item := &badgerPair{concrete: it.Item()}
-
-			if toPrefix != "" {
-				key := string(item.concrete.Key())
-				if key >= toPrefix && !strings.HasPrefix(key, toPrefix) {
-					break
-				}
-			}
-
-			if !yield(item.Key()) {
-				return
-			}
-
-			it.Next()
-		}
+		txn.iterItems(false, prefix, keyRange, func(item *badgerPair) bool {
+			return yield(item.Key())
+		})
 	}
 }
 
 // IterPairs implements [kv.QueryTx]. Values are prefetched.
 func (txn *BadgerTx) IterPairs(prefix kv.Key, keyRange kv.KeyRange) iter.Seq[kv.Pair] {
 	return func(yield func(kv.Pair) bool) {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchValues = true
-		opts.Prefix = keyToBadgerPrefix(prefix)
-		it := txn.concrete.NewIterator(opts)
-		defer it.Close()
-
-		var (
-			fromPrefix []byte
-			toPrefix   string
-		)
-
-		if keyRange.From != nil {
-			fromPrefix = keyToBadgerPrefix(keyRange.From)
-		}
-		if keyRange.To != nil {
-			toPrefix = string(keyToBadgerPrefix(keyRange.To))
-		}
+		txn.iterItems(true, prefix, keyRange, func(item *badgerPair) bool {
+			return yield(item)
+		})
+	}
+}
 
-		if fromPrefix != nil {
-			it.Seek(fromPrefix)
-		} else {
-			it.Rewind()
-		}
+// iterItems walks the items of the prefix subspace within keyRange, in the
+// direction it asks for, until yield returns false.
+//
+// Walking backwards, the iterator seeks to the last key of the range: a key
+// followed by 0xFF sorts after every key of its subtree, since the encoded
+// segments never contain that byte.
+func (txn *BadgerTx) iterItems(
+	prefetch bool,
+	prefix kv.Key,
+	keyRange kv.KeyRange,
+	yield func(*badgerPair) bool,
+) {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = prefetch
+	opts.Prefix = keyToBadgerPrefix(prefix)
+	opts.Reverse = keyRange.Reverse
+	it := txn.concrete.NewIterator(opts)
+	defer it.Close()
+
+	var (
+		fromPrefix string
+		toPrefix   string
+	)
+
+	if keyRange.From != nil {
+		fromPrefix = string(keyToBadgerPrefix(keyRange.From))
+	}
+	if keyRange.To != nil {
+		toPrefix = string(keyToBadgerPrefix(keyRange.To))
+	}
 
-		for it.Valid() {
-			item := &badgerPair{concrete: it.Item()}
+	switch {
+	case keyRange.Reverse && toPrefix != "":
+		it.Seek(append([]byte(toPrefix), 0xFF))
+	case keyRange.Reverse:
+		it.Seek(append(opts.Prefix, 0xFF))
+	case fromPrefix != "":
+		it.Seek([]byte(fromPrefix))
+	default:
+		it.Rewind()
+	}
 
-			if toPrefix != "" {
-				key := string(item.concrete.Key())
-				if key >= toPrefix && !strings.HasPrefix(key, toPrefix) {
-					break
-				}
-			}
+	for it.Valid() {
+		item := &badgerPair{concrete: it.Item()}
+		key := string(item.concrete.Key())
 
-			if !yield(item) {
-				return
+		if keyRange.Reverse {
+			if fromPrefix != "" && key < fromPrefix {
+				break
 			}
+		} else if toPrefix != "" && key >= toPrefix && !strings.HasPrefix(key, toPrefix) {
+			break
+		}
 
-			it.Next()
+		if !yield(item) {
+			return
 		}
+
+		it.Next()
 	}
 }
 
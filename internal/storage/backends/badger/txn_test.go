@@ -223,3 +223,28 @@ func TestKeyRoundTripWithColonSegment(t *testing.T) {
 		t.Fatalf("expected %v, got %v", key, got[0])
 	}
 }
+
+// TestIterKeysReverse checks that a reverse iteration walks the same inclusive
+// range as a forward one, from its end to its start.
+func TestIterKeysReverse(t *testing.T) {
+	txn := newTestTx(t)
+	seedEntries(t, txn, "001", "002", "003", "004")
+
+	got := iterKeyTimestamps(txn, kv.KeyRange{
+		From:    kv.Key{"entry", "s", "002"},
+		To:      kv.Key{"entry", "s", "003"},
+		Reverse: true,
+	})
+
+	want := []string{"003", "002"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got = iterKeyTimestamps(txn, kv.KeyRange{Reverse: true})
+
+	want = []string{"004", "003", "002", "001"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -154,7 +154,8 @@ func txnIterKvPairs(read fdb.ReadTransaction, sub subspace.Subspace, prefix kv.K
 }
 
 // txnIterPairs walks the key-value pairs contained in the prefix subspace,
-// honoring the optional [kv.KeyRange] bounds and skipping expired entries.
+// honoring the optional [kv.KeyRange] bounds and direction, and skipping
+// expired entries.
 //
 // Iteration stops silently on error, mirroring the error-free iterator
 // contract of [kv.QueryTx].
@@ -173,7 +174,7 @@ func txnIterPairs(read fdb.ReadTransaction, sub subspace.Subspace, prefix kv.Key
 
 		r := fdb.KeyRange{Begin: begin, End: end}
 
-		it := read.GetRange(r, fdb.RangeOptions{}).Iterator()
+		it := read.GetRange(r, fdb.RangeOptions{Reverse: keyRange.Reverse}).Iterator()
 		for it.Advance() {
 			concrete, err := it.Get()
 			if err != nil {
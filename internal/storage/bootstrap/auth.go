@@ -37,6 +37,7 @@ func DefaultRolesAndUsers(ctx context.Context, authStorage storage.AuthStorage,
 				models.SCOPE_WRITE_TRANSFORMERS,
 				models.SCOPE_WRITE_STREAMS,
 				models.SCOPE_WRITE_FORWARDERS,
+				models.SCOPE_WRITE_ALERTS,
 				models.SCOPE_READ_SYSTEM_CONFIGURATION,
 				models.SCOPE_WRITE_SYSTEM_CONFIGURATION,
 				models.SCOPE_READ_AUTH_PROVIDERS,
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"io"
 
@@ -119,6 +120,19 @@ func (s *Storage[QTx, MTx]) ListUserScopes(ctx context.Context, name string) ([]
 	return scopes, err
 }
 
+// ListUserResourceScopes implements [storage.AuthStorage].
+func (s *Storage[QTx, MTx]) ListUserResourceScopes(ctx context.Context, name string) ([]models.ResourceScope, error) {
+	var resourceScopes []models.ResourceScope
+
+	err := s.adapter.View(ctx, func(txn QTx) error {
+		var err error
+		resourceScopes, err = transactions.ListUserResourceScopes(txn, name)
+		return err
+	})
+
+	return resourceScopes, err
+}
+
 // SaveUser implements [storage.AuthStorage].
 func (s *Storage[QTx, MTx]) SaveUser(ctx context.Context, user models.User, password string) error {
 	return s.adapter.Update(ctx, func(txn MTx) error {
@@ -154,48 +168,74 @@ func (s *Storage[QTx, MTx]) VerifyUserPassword(ctx context.Context, name string,
 }
 
 // VerifyUserPermission implements [storage.AuthStorage].
-func (s *Storage[QTx, MTx]) VerifyUserPermission(ctx context.Context, username string, scope models.Scope) (bool, error) {
+func (s *Storage[QTx, MTx]) VerifyUserPermission(
+	ctx context.Context,
+	username string,
+	scope models.Scope,
+	resource models.Resource,
+) (bool, error) {
 	var verified bool
 
 	err := s.adapter.View(ctx, func(txn QTx) error {
 		var err error
-		verified, err = transactions.VerifyUserPermission(txn, username, scope)
+		verified, err = transactions.VerifyUserPermission(txn, username, scope, resource)
 		return err
 	})
 
 	return verified, err
 }
 
+// TOKEN_LAST_USE_RESOLUTION is the precision of the last use time of the
+// personal access tokens: it is only recorded again once that much time has
+// passed, so that verifying a token is not a write on every request.
+const TOKEN_LAST_USE_RESOLUTION = time.Minute
+
 // CreateToken implements [storage.AuthStorage].
-func (s *Storage[QTx, MTx]) CreateToken(ctx context.Context, username string) (string, string, error) {
-	var token string
-	var tokenUUID string
+func (s *Storage[QTx, MTx]) CreateToken(ctx context.Context, username string, token models.Token) (string, *models.Token, error) {
+	var plaintext string
+	var description *models.Token
 
 	err := s.adapter.Update(ctx, func(txn MTx) error {
 		var err error
-		token, tokenUUID, err = transactions.CreateToken(txn, username)
+		plaintext, description, err = transactions.CreateToken(txn, username, token)
 		return err
 	})
 
-	return token, tokenUUID, err
+	return plaintext, description, err
 }
 
 // VerifyToken implements [storage.AuthStorage].
-func (s *Storage[QTx, MTx]) VerifyToken(ctx context.Context, token string) (*models.User, error) {
+func (s *Storage[QTx, MTx]) VerifyToken(ctx context.Context, token string) (*models.User, *models.Token, error) {
 	var user *models.User
+	var description *models.Token
+
+	now := time.Now()
 
 	err := s.adapter.View(ctx, func(txn QTx) error {
 		var err error
-		user, err = transactions.VerifyToken(txn, token)
+		user, description, err = transactions.VerifyToken(txn, token, now)
 		return err
 	})
+	if err != nil || user == nil {
+		return nil, nil, err
+	}
 
-	return user, err
+	lastUsedAt := description.LastUsedAt
+	if description.UUID != "" && (lastUsedAt == nil || now.Sub(*lastUsedAt) >= TOKEN_LAST_USE_RESOLUTION) {
+		err = s.adapter.Update(ctx, func(txn MTx) error {
+			return transactions.TouchToken(txn, user.Name, description.UUID, now)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return user, description, nil
 }
 
 // ListTokens implements [storage.AuthStorage].
-func (s *Storage[QTx, MTx]) ListTokens(ctx context.Context, username string) ([]string, error) {
-	var tokens []string
+func (s *Storage[QTx, MTx]) ListTokens(ctx context.Context, username string) ([]models.Token, error) {
+	var tokens []models.Token
 
 	err := s.adapter.View(ctx, func(txn QTx) error {
 		var err error
@@ -252,3 +292,35 @@ func (s *Storage[QTx, MTx]) DeleteAuthProvider(ctx context.Context, name string)
 		return transactions.DeleteAuthProvider(txn, name)
 	})
 }
+
+// FetchLinkedUser implements [storage.AuthStorage].
+func (s *Storage[QTx, MTx]) FetchLinkedUser(ctx context.Context, provider string, subject string) (string, error) {
+	var username string
+
+	err := s.adapter.View(ctx, func(txn QTx) error {
+		var err error
+		username, err = transactions.FetchLinkedUser(txn, provider, subject)
+		return err
+	})
+
+	return username, err
+}
+
+// CreateLinkedUser implements [storage.AuthStorage].
+func (s *Storage[QTx, MTx]) CreateLinkedUser(
+	ctx context.Context,
+	provider string,
+	subject string,
+	user models.User,
+	password string,
+) (bool, error) {
+	var created bool
+
+	err := s.adapter.Update(ctx, func(txn MTx) error {
+		var err error
+		created, err = transactions.CreateLinkedUser(txn, provider, subject, user, password)
+		return err
+	})
+
+	return created, err
+}
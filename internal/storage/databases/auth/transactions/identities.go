@@ -0,0 +1,95 @@
+package transactions
+
+import (
+	"fmt"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/storage/generic/kv"
+)
+
+// FetchLinkedUser returns the name stored under "identity:<provider>:<subject>",
+// the user an auth provider's subject signs in as, or an empty string when the
+// subject is not linked to any user.
+func FetchLinkedUser(txn kv.QueryTx, provider string, subject string) (string, error) {
+	val, err := txn.Get(kv.Key{"identity", provider, subject})
+	if err != nil {
+		return "", fmt.Errorf("failed to read identity %q of auth provider %q: %w", subject, provider, err)
+	}
+
+	return string(val), nil
+}
+
+// CreateLinkedUser saves a new user, then links it to the subject of an auth
+// provider under "identity:<provider>:<subject>", and records the link under
+// "user:<name>:identity:<provider>" so that it is removed along with the user.
+// Nothing is written, and false is returned, when the user already exists.
+func CreateLinkedUser(
+	txn kv.MutationTx,
+	provider string,
+	subject string,
+	user models.User,
+	password string,
+) (bool, error) {
+	existing, err := FetchUser(txn, user.Name)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		return false, nil
+	}
+
+	if err := SaveUser(txn, user, password); err != nil {
+		return false, err
+	}
+
+	err = txn.Set(kv.Key{"identity", provider, subject}, []byte(user.Name))
+	if err != nil {
+		return false, fmt.Errorf("failed to write identity %q of auth provider %q: %w", subject, provider, err)
+	}
+
+	err = txn.Set(kv.Key{"user", user.Name, "identity", provider}, []byte(subject))
+	if err != nil {
+		return false, fmt.Errorf("failed to write identity of user %q: %w", user.Name, err)
+	}
+
+	return true, nil
+}
+
+// unlinkUser removes the "identity:<provider>:<subject>" links of a user, as
+// recorded under "user:<name>:identity:*".
+func unlinkUser(txn kv.MutationTx, name string) error {
+	var keys []kv.Key
+
+	for pair := range txn.IterPairs(kv.Key{"user", name, "identity"}, kv.KeyRange{}) {
+		provider := pair.Key()[len(pair.Key())-1]
+		keys = append(keys, kv.Key{"identity", provider, string(pair.Value())})
+	}
+
+	for _, key := range keys {
+		if err := txn.Clear(key); err != nil {
+			return fmt.Errorf("failed to clear identity of user %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// unlinkAuthProvider removes every "identity:<provider>:*" link of an auth
+// provider, along with the "user:<name>:identity:<provider>" record of each
+// linked user, so that a provider later saved under the same name cannot sign
+// in as them.
+func unlinkAuthProvider(txn kv.MutationTx, provider string) error {
+	var keys []kv.Key
+
+	for pair := range txn.IterPairs(kv.Key{"identity", provider}, kv.KeyRange{}) {
+		keys = append(keys, pair.Key(), kv.Key{"user", string(pair.Value()), "identity", provider})
+	}
+
+	for _, key := range keys {
+		if err := txn.Clear(key); err != nil {
+			return fmt.Errorf("failed to clear identities of auth provider %q: %w", provider, err)
+		}
+	}
+
+	return nil
+}
@@ -59,7 +59,8 @@ func SaveAuthProvider(txn kv.MutationTx, provider models.AuthProvider) error {
 	return txn.Set(key, marshalled)
 }
 
-// DeleteAuthProvider deletes provider from the database
+// DeleteAuthProvider deletes provider from the database, along with the links
+// of its identities to users
 func DeleteAuthProvider(txn kv.MutationTx, name string) error {
 	providerKey := kv.Key{PROVIDER, name}
 
@@ -67,5 +68,5 @@ func DeleteAuthProvider(txn kv.MutationTx, name string) error {
 		return fmt.Errorf("failed to clear auth provider %q: %w", name, err)
 	}
 
-	return nil
+	return unlinkAuthProvider(txn, name)
 }
@@ -2,6 +2,7 @@ package transactions
 
 import (
 	"fmt"
+	"slices"
 
 	"link-society.com/flowg/internal/models"
 	"link-society.com/flowg/internal/storage/generic/kv"
@@ -27,7 +28,8 @@ func ListRoles(txn kv.QueryTx) ([]models.Role, error) {
 }
 
 // FetchRole reconstructs a single role by collecting its "role:<name>:*" scope
-// keys; the key itself carries the scope, so values are never read.
+// keys; the key itself carries the scope, or the resource scope, so values are
+// never read.
 func FetchRole(txn kv.QueryTx, name string) (*models.Role, error) {
 	role := &models.Role{Name: name}
 
@@ -35,12 +37,18 @@ func FetchRole(txn kv.QueryTx, name string) (*models.Role, error) {
 
 	for key := range txn.IterKeys(prefix, kv.KeyRange{}) {
 		scopeName := key[len(key)-1]
-		scope, err := models.ParseScope(scopeName)
+
+		if scope, err := models.ParseScope(scopeName); err == nil {
+			role.Scopes = append(role.Scopes, scope)
+			continue
+		}
+
+		resourceScope, err := models.ParseResourceScope(scopeName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse scope %q while fetching role %q: %w", scopeName, name, err)
 		}
 
-		role.Scopes = append(role.Scopes, scope)
+		role.ResourceScopes = append(role.ResourceScopes, resourceScope)
 	}
 
 	return role, nil
@@ -48,8 +56,8 @@ func FetchRole(txn kv.QueryTx, name string) (*models.Role, error) {
 
 // SaveRole persists a role and reconciles its scope keys against what is already
 // stored: it writes the "index:role:<name>" marker, then converges the existing
-// "role:<name>:*" keys towards the desired scope set by adding the missing ones
-// and deleting the obsolete ones.
+// "role:<name>:*" keys towards the desired set of scopes and resource scopes by
+// adding the missing ones and deleting the obsolete ones.
 func SaveRole(txn kv.MutationTx, role models.Role) error {
 	key := kv.Key{"index", "role", role.Name}
 	err := txn.Set(key, []byte{})
@@ -57,33 +65,37 @@ func SaveRole(txn kv.MutationTx, role models.Role) error {
 		return fmt.Errorf("failed to write index for role %q: %w", role.Name, err)
 	}
 
-	obsoleteScopes := map[models.Scope]kv.Key{}
+	desiredScopes := make([]string, 0, len(role.Scopes)+len(role.ResourceScopes))
+	for _, scope := range role.Scopes {
+		desiredScopes = append(desiredScopes, string(scope))
+	}
+	for _, resourceScope := range role.ResourceScopes {
+		desiredScopes = append(desiredScopes, string(resourceScope))
+	}
+
+	obsoleteScopes := map[string]kv.Key{}
 
 	// Collect the scopes currently stored for the role, tentatively flagging any
 	// that are no longer part of the desired set as obsolete.
 	for key := range txn.IterKeys(kv.Key{"role", role.Name}, kv.KeyRange{}) {
 		scopeName := key[len(key)-1]
-		scope, err := models.ParseScope(scopeName)
-		if err != nil {
-			return fmt.Errorf("failed to parse scope %q while saving role %q: %w", scopeName, role.Name, err)
-		}
 
-		if !role.HasScope(scope) {
-			obsoleteScopes[scope] = key
+		if !slices.Contains(desiredScopes, scopeName) {
+			obsoleteScopes[scopeName] = key
 		}
 	}
 
 	// Persist the desired scopes: create the ones that are missing and clear the
 	// obsolete flag on the ones that already exist.
-	for _, scope := range role.Scopes {
-		if _, exists := obsoleteScopes[scope]; !exists {
-			key := kv.Key{"role", role.Name, string(scope)}
+	for _, scopeName := range desiredScopes {
+		if _, exists := obsoleteScopes[scopeName]; !exists {
+			key := kv.Key{"role", role.Name, scopeName}
 			err := txn.Set(key, []byte{})
 			if err != nil {
-				return fmt.Errorf("failed to write scope %q for role %q: %w", scope, role.Name, err)
+				return fmt.Errorf("failed to write scope %q for role %q: %w", scopeName, role.Name, err)
 			}
 		} else {
-			delete(obsoleteScopes, scope)
+			delete(obsoleteScopes, scopeName)
 		}
 	}
 
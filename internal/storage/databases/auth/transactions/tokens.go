@@ -1,7 +1,9 @@
 package transactions
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -12,70 +14,199 @@ import (
 	"link-society.com/flowg/internal/utils/secret"
 )
 
-// CreateToken issues a fresh PAT for an existing user. It confirms the user
-// exists through their "index:user:<username>" marker, stores the token's
-// SHA-256 hash under "pat:<username>:<uuid>", and records a reverse index
-// "index:pat:<hash>" -> username so the token can be resolved in O(1). It
-// returns the plaintext token (shown to the caller this one time only) along
-// with its UUID.
-func CreateToken(txn kv.MutationTx, username string) (string, string, error) {
-	token, err := secret.NewSecret("pat", 32)
+// tokenRecord is the value stored under "pat:<username>:<uuid>": the SHA-256
+// hash of the token along with its description. Tokens created before they
+// had a description only store the hash.
+type tokenRecord struct {
+	Hash string `json:"hash"`
+	models.Token
+}
+
+// tokenIndex is the value stored under "index:pat:<hash>". Tokens created
+// before they had a description are indexed by the name of their owner only.
+type tokenIndex struct {
+	Username string `json:"username"`
+	UUID     string `json:"uuid"`
+}
+
+// parseTokenRecord decodes the value stored under "pat:<username>:<uuid>",
+// falling back to a bare hash for tokens without a description: those are
+// unrestricted and never expire.
+func parseTokenRecord(tokenUUID string, val []byte) tokenRecord {
+	var record tokenRecord
+	if err := json.Unmarshal(val, &record); err != nil || record.Hash == "" {
+		record = tokenRecord{Hash: string(val)}
+	}
+
+	record.UUID = tokenUUID
+	if record.Scopes == nil {
+		record.Scopes = []models.Scope{}
+	}
+
+	return record
+}
+
+// parseTokenIndex decodes the value stored under "index:pat:<hash>", falling
+// back to a bare username for tokens without a description.
+func parseTokenIndex(val []byte) tokenIndex {
+	var index tokenIndex
+	if err := json.Unmarshal(val, &index); err != nil || index.Username == "" {
+		index = tokenIndex{Username: string(val)}
+	}
+
+	return index
+}
+
+// CreateToken issues a fresh PAT for an existing user, described by the name,
+// scopes and expiry of the given token. It confirms the user exists through
+// their "index:user:<username>" marker, stores the token's SHA-256 hash and
+// description under "pat:<username>:<uuid>", and records a reverse index
+// "index:pat:<hash>" -> (username, uuid) so the token can be resolved in
+// O(1). It returns the plaintext token (shown to the caller this one time
+// only) along with its description.
+func CreateToken(txn kv.MutationTx, username string, token models.Token) (string, *models.Token, error) {
+	plaintext, err := secret.NewSecret("pat", 32)
 	if err != nil {
-		return "", "", err
+		return "", nil, err
 	}
 
-	tokenHash := hash.HashToken(token)
-	tokenUuid := uuid.New().String()
+	createdAt := time.Now().UTC()
+
+	record := tokenRecord{
+		Hash:  hash.HashToken(plaintext),
+		Token: token,
+	}
+	record.UUID = uuid.New().String()
+	record.CreatedAt = &createdAt
+	record.LastUsedAt = nil
+	if record.Scopes == nil {
+		record.Scopes = []models.Scope{}
+	}
 
 	userKey := kv.Key{"index", "user", username}
 	val, err := txn.Get(userKey)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to read user %q: %w", username, err)
+		return "", nil, fmt.Errorf("failed to read user %q: %w", username, err)
 	}
 	if val == nil {
-		return "", "", fmt.Errorf("user %q not found", username)
+		return "", nil, fmt.Errorf("user %q not found", username)
 	}
 
-	tokenKey := kv.Key{"pat", username, tokenUuid}
-	if err := txn.Set(tokenKey, []byte(tokenHash)); err != nil {
-		return "", "", fmt.Errorf("failed to write token for user %q: %w", username, err)
+	if err := writeTokenRecord(txn, username, record); err != nil {
+		return "", nil, err
 	}
 
-	indexKey := kv.Key{"index", "pat", tokenHash}
-	if err := txn.Set(indexKey, []byte(username)); err != nil {
-		return "", "", fmt.Errorf("failed to index token for user %q: %w", username, err)
+	index, err := json.Marshal(tokenIndex{Username: username, UUID: record.UUID})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal token index for user %q: %w", username, err)
+	}
+
+	indexKey := kv.Key{"index", "pat", record.Hash}
+	if err := txn.Set(indexKey, index); err != nil {
+		return "", nil, fmt.Errorf("failed to index token for user %q: %w", username, err)
 	}
 
-	return token, tokenUuid, nil
+	return plaintext, &record.Token, nil
 }
 
-// VerifyToken resolves a plaintext token back to its owning user in O(1): it
-// hashes the token and looks up "index:pat:<hash>". A missing entry means the
-// token is unknown (and no fallback to a legacy per-token scan is attempted).
-func VerifyToken(txn kv.QueryTx, token string) (*models.User, error) {
-	indexKey := kv.Key{"index", "pat", hash.HashToken(token)}
+// writeTokenRecord stores a token's hash and description under
+// "pat:<username>:<uuid>".
+func writeTokenRecord(txn kv.MutationTx, username string, record tokenRecord) error {
+	val, err := json.Marshal(&record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token %q for user %q: %w", record.UUID, username, err)
+	}
+
+	tokenKey := kv.Key{"pat", username, record.UUID}
+	if err := txn.Set(tokenKey, val); err != nil {
+		return fmt.Errorf("failed to write token %q for user %q: %w", record.UUID, username, err)
+	}
+
+	return nil
+}
+
+// VerifyToken resolves a plaintext token back to its owning user and its
+// description in O(1): it hashes the token and looks up "index:pat:<hash>". A
+// missing entry means the token is unknown (and no fallback to a legacy
+// per-token scan is attempted), and a token expired at the given time is
+// treated the same way.
+func VerifyToken(txn kv.QueryTx, token string, now time.Time) (*models.User, *models.Token, error) {
+	tokenHash := hash.HashToken(token)
+	indexKey := kv.Key{"index", "pat", tokenHash}
 
 	val, err := txn.Get(indexKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to verify token: %w", err)
+		return nil, nil, fmt.Errorf("failed to verify token: %w", err)
 	}
 	if val == nil {
-		return nil, nil
+		return nil, nil, nil
+	}
+
+	index := parseTokenIndex(val)
+	description := &models.Token{Scopes: []models.Scope{}}
+
+	if index.UUID != "" {
+		tokenKey := kv.Key{"pat", index.Username, index.UUID}
+		val, err := txn.Get(tokenKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read token %q for user %q: %w", index.UUID, index.Username, err)
+		}
+		if val == nil {
+			return nil, nil, nil
+		}
+
+		record := parseTokenRecord(index.UUID, val)
+		if record.Hash != tokenHash {
+			return nil, nil, nil
+		}
+
+		description = &record.Token
+	}
+
+	if description.IsExpired(now) {
+		return nil, nil, nil
 	}
 
-	return FetchUser(txn, string(val))
+	user, err := FetchUser(txn, index.Username)
+	if err != nil || user == nil {
+		return nil, nil, err
+	}
+
+	return user, description, nil
+}
+
+// TouchToken records the time a token was last used at. Unknown tokens are
+// ignored, as they may have been revoked meanwhile.
+func TouchToken(txn kv.MutationTx, username string, tokenUUID string, usedAt time.Time) error {
+	tokenKey := kv.Key{"pat", username, tokenUUID}
+
+	val, err := txn.Get(tokenKey)
+	if err != nil {
+		return fmt.Errorf("failed to read token %q for user %q: %w", tokenUUID, username, err)
+	}
+	if val == nil {
+		return nil
+	}
+
+	usedAt = usedAt.UTC()
+	record := parseTokenRecord(tokenUUID, val)
+	record.LastUsedAt = &usedAt
+
+	return writeTokenRecord(txn, username, record)
 }
 
-// ListTokens returns the UUIDs of every PAT owned by a user by scanning the
-// "pat:<username>:" prefix. Only the keys are needed, so values are not fetched.
-func ListTokens(txn kv.QueryTx, username string) []string {
-	tokenUUIDs := []string{}
+// ListTokens returns the description of every PAT owned by a user by scanning
+// the "pat:<username>:" prefix.
+func ListTokens(txn kv.QueryTx, username string) []models.Token {
+	tokens := []models.Token{}
 
-	for key := range txn.IterKeys(kv.Key{"pat", username}, kv.KeyRange{}) {
-		tokenUUIDs = append(tokenUUIDs, key[len(key)-1])
+	for pair := range txn.IterPairs(kv.Key{"pat", username}, kv.KeyRange{}) {
+		key := pair.Key()
+		record := parseTokenRecord(key[len(key)-1], pair.Value())
+		tokens = append(tokens, record.Token)
 	}
 
-	return tokenUUIDs
+	return tokens
 }
 
 // DeleteToken removes a single PAT, identified by its UUID, from a user, along
@@ -83,13 +214,13 @@ func ListTokens(txn kv.QueryTx, username string) []string {
 func DeleteToken(txn kv.MutationTx, username string, tokenUUID string) error {
 	tokenKey := kv.Key{"pat", username, tokenUUID}
 
-	tokenHash, err := txn.Get(tokenKey)
+	val, err := txn.Get(tokenKey)
 	if err != nil {
 		return fmt.Errorf("failed to read token %q for user %q: %w", tokenUUID, username, err)
 	}
 
-	if tokenHash != nil {
-		indexKey := kv.Key{"index", "pat", string(tokenHash)}
+	if val != nil {
+		indexKey := kv.Key{"index", "pat", parseTokenRecord(tokenUUID, val).Hash}
 		if err := txn.Clear(indexKey); err != nil {
 			return fmt.Errorf("failed to clear token index %q for user %q: %w", tokenUUID, username, err)
 		}
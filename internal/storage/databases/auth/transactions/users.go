@@ -2,6 +2,7 @@ package transactions
 
 import (
 	"fmt"
+	"slices"
 
 	"link-society.com/flowg/internal/models"
 	"link-society.com/flowg/internal/storage/generic/kv"
@@ -117,10 +118,16 @@ func PatchUserRoles(txn kv.MutationTx, user models.User) error {
 	return nil
 }
 
-// DeleteUser removes everything tied to a user: all "user:<name>:*" keys (roles
-// and password), all of their "pat:<name>:*" tokens (and each token's
-// "index:pat:<hash>" reverse-index entry), and the "index:user:<name>" marker.
+// DeleteUser removes everything tied to a user: all "user:<name>:*" keys (roles,
+// password and auth provider identities, along with their
+// "identity:<provider>:<subject>" links), all of their "pat:<name>:*" tokens
+// (and each token's "index:pat:<hash>" reverse-index entry), and the
+// "index:user:<name>" marker.
 func DeleteUser(txn kv.MutationTx, name string) error {
+	if err := unlinkUser(txn, name); err != nil {
+		return err
+	}
+
 	keys := make([]kv.Key, 0)
 
 	for key := range txn.IterKeys(kv.Key{"user", name}, kv.KeyRange{}) {
@@ -128,10 +135,12 @@ func DeleteUser(txn kv.MutationTx, name string) error {
 	}
 
 	for pair := range txn.IterPairs(kv.Key{"pat", name}, kv.KeyRange{}) {
-		keys = append(keys, pair.Key())
+		key := pair.Key()
+		keys = append(keys, key)
 
-		if tokenHash := pair.Value(); tokenHash != nil {
-			keys = append(keys, kv.Key{"index", "pat", string(tokenHash)})
+		if val := pair.Value(); val != nil {
+			record := parseTokenRecord(key[len(key)-1], val)
+			keys = append(keys, kv.Key{"index", "pat", record.Hash})
 		}
 	}
 
@@ -168,68 +177,79 @@ func VerifyUserPassword(txn kv.QueryTx, name string, password string) (bool, err
 	return isValid, nil
 }
 
-// VerifyUserPermission reports whether a user holds a given scope. It walks the
-// user's roles ("user:<name>:role:*") and, for each, the scopes it grants
-// ("role:<role>:*"), returning true on a direct match or when the user holds the
-// matching write scope for a requested read scope (write implies read).
-func VerifyUserPermission(txn kv.QueryTx, name string, scope models.Scope) (bool, error) {
-	roles := make([]string, 0)
-
-	for key := range txn.IterKeys(kv.Key{"user", name, "role"}, kv.KeyRange{}) {
-		roleName := key[len(key)-1]
-		roles = append(roles, roleName)
-	}
-
-	for _, roleName := range roles {
+// VerifyUserPermission reports whether a user holds a given scope on a given
+// resource. It walks the user's roles ("user:<name>:role:*") and, for each, the
+// scopes it grants ("role:<role>:*"), returning true when one of them implies
+// the requested scope (write implies read), or when one of its resource scopes
+// does so on a resource matching its pattern.
+func VerifyUserPermission(txn kv.QueryTx, name string, scope models.Scope, resource models.Resource) (bool, error) {
+	for _, roleName := range fetchUserRoleNames(txn, name) {
 		for key := range txn.IterKeys(kv.Key{"role", roleName}, kv.KeyRange{}) {
 			scopeName := key[len(key)-1]
-			roleScope, err := models.ParseScope(scopeName)
+
+			if roleScope, err := models.ParseScope(scopeName); err == nil {
+				if roleScope.Implies(scope) {
+					return true, nil
+				}
+				continue
+			}
+
+			resourceScope, err := models.ParseResourceScope(scopeName)
 			if err != nil {
 				return false, fmt.Errorf("failed to parse scope %q for user %q: %w", scopeName, name, err)
 			}
 
-			switch {
-			case scope == roleScope:
+			if resourceScope.Grants(scope, resource) {
 				return true, nil
+			}
+		}
+	}
 
-			case scope == models.SCOPE_READ_PIPELINES && roleScope == models.SCOPE_WRITE_PIPELINES:
-				return true, nil
+	return false, nil
+}
 
-			case scope == models.SCOPE_READ_TRANSFORMERS && roleScope == models.SCOPE_WRITE_TRANSFORMERS:
-				return true, nil
+// ListUserResourceScopes returns the de-duplicated set of resource scopes a
+// user holds through their roles.
+func ListUserResourceScopes(txn kv.QueryTx, username string) ([]models.ResourceScope, error) {
+	resourceScopes := []models.ResourceScope{}
 
-			case scope == models.SCOPE_READ_STREAMS && roleScope == models.SCOPE_WRITE_STREAMS:
-				return true, nil
+	for _, roleName := range fetchUserRoleNames(txn, username) {
+		for key := range txn.IterKeys(kv.Key{"role", roleName}, kv.KeyRange{}) {
+			scopeName := key[len(key)-1]
 
-			case scope == models.SCOPE_READ_FORWARDERS && roleScope == models.SCOPE_WRITE_FORWARDERS:
-				return true, nil
+			if _, err := models.ParseScope(scopeName); err == nil {
+				continue
+			}
 
-			case scope == models.SCOPE_READ_ACLS && roleScope == models.SCOPE_WRITE_ACLS:
-				return true, nil
+			resourceScope, err := models.ParseResourceScope(scopeName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse scope %q for user %q: %w", scopeName, username, err)
+			}
+
+			if !slices.Contains(resourceScopes, resourceScope) {
+				resourceScopes = append(resourceScopes, resourceScope)
 			}
 		}
 	}
 
-	return false, nil
+	return resourceScopes, nil
 }
 
 // ListUserScopes returns the de-duplicated set of scopes a user effectively
-// holds, resolved through their roles. Each write scope additionally pulls in
-// its corresponding read scope.
+// holds on every resource, resolved through their roles. Each write scope
+// additionally pulls in its corresponding read scope. Resource scopes are left
+// out, see [ListUserResourceScopes].
 func ListUserScopes(txn kv.QueryTx, username string) ([]models.Scope, error) {
 	scopeMap := map[models.Scope]struct{}{}
-	roles := make([]string, 0)
-
-	for key := range txn.IterKeys(kv.Key{"user", username, "role"}, kv.KeyRange{}) {
-		roleName := key[len(key)-1]
-		roles = append(roles, roleName)
-	}
 
-	for _, roleName := range roles {
+	for _, roleName := range fetchUserRoleNames(txn, username) {
 		for key := range txn.IterKeys(kv.Key{"role", roleName}, kv.KeyRange{}) {
 			scopeName := key[len(key)-1]
 			roleScope, err := models.ParseScope(scopeName)
 			if err != nil {
+				if _, err := models.ParseResourceScope(scopeName); err == nil {
+					continue
+				}
 				return nil, err
 			}
 
@@ -250,6 +270,10 @@ func ListUserScopes(txn kv.QueryTx, username string) ([]models.Scope, error) {
 				scopeMap[models.SCOPE_READ_FORWARDERS] = struct{}{}
 				scopeMap[models.SCOPE_WRITE_FORWARDERS] = struct{}{}
 
+			case models.SCOPE_WRITE_ALERTS:
+				scopeMap[models.SCOPE_READ_ALERTS] = struct{}{}
+				scopeMap[models.SCOPE_WRITE_ALERTS] = struct{}{}
+
 			case models.SCOPE_WRITE_ACLS:
 				scopeMap[models.SCOPE_READ_ACLS] = struct{}{}
 				scopeMap[models.SCOPE_WRITE_ACLS] = struct{}{}
@@ -268,6 +292,19 @@ func ListUserScopes(txn kv.QueryTx, username string) ([]models.Scope, error) {
 	return scopes, nil
 }
 
+// fetchUserRoleNames lists the roles of a user from their
+// "user:<name>:role:" keys.
+func fetchUserRoleNames(txn kv.QueryTx, username string) []string {
+	roles := []string{}
+
+	for key := range txn.IterKeys(kv.Key{"user", username, "role"}, kv.KeyRange{}) {
+		roleName := key[len(key)-1]
+		roles = append(roles, roleName)
+	}
+
+	return roles
+}
+
 // fetchUsernames lists existing usernames from their "index:user:" markers.
 func fetchUsernames(txn kv.QueryTx) ([]string, error) {
 	usernames := []string{}
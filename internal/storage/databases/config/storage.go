@@ -12,6 +12,10 @@ import (
 
 	"io"
 	"net"
+	"path"
+	"slices"
+
+	"github.com/google/uuid"
 
 	"link-society.com/flowg/internal/models"
 
@@ -25,17 +29,30 @@ const (
 	transformerItemType = "transformer"
 	pipelineItemType    = "pipeline"
 	forwarderItemType   = "forwarder"
+	alertItemType       = "alert"
 	systemItemType      = "system"
 )
 
+// alertHistorySize is the number of firings kept in the history of each alert
+// rule; older ones are dropped as new ones are recorded.
+const alertHistorySize = 100
+
+// deadLetterQueueSize is the number of records kept in the dead-letter queue of
+// each forwarder; older ones are dropped as new ones are queued.
+const deadLetterQueueSize = 10000
+
 // Storage is a backend-agnostic implementation of [storage.ConfigStorage]. It
 // runs the config transactions from the transactions subpackage on top of any
-// [kv.Adapter] and caches the decoded system configuration in memory.
+// [kv.Adapter] and caches the decoded system configuration and the list of
+// pipelines in memory.
 type Storage[QTx kv.QueryTx, MTx kv.MutationTx] struct {
 	adapter kv.Adapter[QTx, MTx]
 
 	lock                  *sync.Mutex
 	configurationInstance atomic.Pointer[models.SystemConfiguration]
+
+	pipelinesLock     *sync.Mutex
+	pipelinesInstance atomic.Pointer[[]string]
 }
 
 var _ storage.ConfigStorage = (*Storage[kv.QueryTx, kv.MutationTx])(nil)
@@ -44,8 +61,9 @@ var _ storage.ConfigStorage = (*Storage[kv.QueryTx, kv.MutationTx])(nil)
 // given key-value adapter.
 func NewStorage[QTx kv.QueryTx, MTx kv.MutationTx](adapter kv.Adapter[QTx, MTx]) *Storage[QTx, MTx] {
 	return &Storage[QTx, MTx]{
-		adapter: adapter,
-		lock:    &sync.Mutex{},
+		adapter:       adapter,
+		lock:          &sync.Mutex{},
+		pipelinesLock: &sync.Mutex{},
 	}
 }
 
@@ -56,6 +74,9 @@ func (s *Storage[QTx, MTx]) Dump(ctx context.Context, w io.Writer, version uint6
 
 // Load implements [storage.ConfigStorage].
 func (s *Storage[QTx, MTx]) Load(ctx context.Context, r io.Reader) error {
+	defer s.configurationInstance.Store(nil)
+	defer s.invalidatePipelines()
+
 	return s.adapter.Restore(ctx, r)
 }
 
@@ -90,8 +111,28 @@ func (s *Storage[QTx, MTx]) DeleteTransformer(ctx context.Context, name string)
 }
 
 // ListPipelines implements [storage.ConfigStorage].
+//
+// The list is cached until a pipeline is written or deleted, as it is read for
+// every syslog message.
 func (s *Storage[QTx, MTx]) ListPipelines(ctx context.Context) ([]string, error) {
-	return s.listItems(ctx, pipelineItemType)
+	if names := s.pipelinesInstance.Load(); names != nil {
+		return slices.Clone(*names), nil
+	}
+
+	s.pipelinesLock.Lock()
+	defer s.pipelinesLock.Unlock()
+
+	if names := s.pipelinesInstance.Load(); names != nil {
+		return slices.Clone(*names), nil
+	}
+
+	names, err := s.listItems(ctx, pipelineItemType)
+	if err != nil {
+		return nil, err
+	}
+
+	s.pipelinesInstance.Store(&names)
+	return slices.Clone(names), nil
 }
 
 // ReadPipeline implements [storage.ConfigStorage].
@@ -126,19 +167,32 @@ func (s *Storage[QTx, MTx]) WritePipeline(ctx context.Context, name string, flow
 		return fmt.Errorf("failed to marshal flow graph: %w", err)
 	}
 
+	defer s.invalidatePipelines()
 	return s.writeItem(ctx, pipelineItemType, name, content)
 }
 
 // WriteRawPipeline implements [storage.ConfigStorage].
 func (s *Storage[QTx, MTx]) WriteRawPipeline(ctx context.Context, name string, content string) error {
+	defer s.invalidatePipelines()
 	return s.writeItem(ctx, pipelineItemType, name, []byte(content))
 }
 
 // DeletePipeline implements [storage.ConfigStorage].
 func (s *Storage[QTx, MTx]) DeletePipeline(ctx context.Context, name string) error {
+	defer s.invalidatePipelines()
 	return s.deleteItem(ctx, pipelineItemType, name)
 }
 
+// invalidatePipelines drops the cached list of pipelines, once the write that
+// changed it is done. Holding the lock ensures that a concurrent ListPipelines
+// cannot cache a list read before the write.
+func (s *Storage[QTx, MTx]) invalidatePipelines() {
+	s.pipelinesLock.Lock()
+	defer s.pipelinesLock.Unlock()
+
+	s.pipelinesInstance.Store(nil)
+}
+
 // ListForwarders implements [storage.ConfigStorage].
 func (s *Storage[QTx, MTx]) ListForwarders(ctx context.Context) ([]string, error) {
 	return s.listItems(ctx, forwarderItemType)
@@ -182,7 +236,147 @@ func (s *Storage[QTx, MTx]) WriteForwarder(ctx context.Context, name string, for
 
 // DeleteForwarder implements [storage.ConfigStorage].
 func (s *Storage[QTx, MTx]) DeleteForwarder(ctx context.Context, name string) error {
-	return s.deleteItem(ctx, forwarderItemType, name)
+	return s.adapter.Update(ctx, func(txn MTx) error {
+		if err := transactions.DeleteItem(txn, forwarderItemType, name); err != nil {
+			return err
+		}
+
+		return transactions.DeleteDeadLetters(txn, name)
+	})
+}
+
+// ListDeadLetters implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) ListDeadLetters(ctx context.Context, forwarder string, after string, limit int) ([]models.DeadLetter, error) {
+	var contents [][]byte
+
+	err := s.adapter.View(ctx, func(txn QTx) error {
+		var err error
+		contents, err = transactions.ListDeadLetters(txn, forwarder, after, limit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]models.DeadLetter, 0, len(contents))
+	for _, content := range contents {
+		var letter models.DeadLetter
+		if err := json.Unmarshal(content, &letter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+
+	return letters, nil
+}
+
+// PushDeadLetter implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) PushDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	letter.ID = fmt.Sprintf("%020d-%s", letter.Timestamp.UnixMilli(), uuid.NewString())
+
+	content, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	return s.adapter.Update(ctx, func(txn MTx) error {
+		return transactions.AppendDeadLetter(txn, letter.Forwarder, letter.ID, content, deadLetterQueueSize)
+	})
+}
+
+// DeleteDeadLetter implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) DeleteDeadLetter(ctx context.Context, forwarder string, id string) error {
+	return s.adapter.Update(ctx, func(txn MTx) error {
+		return transactions.DeleteDeadLetter(txn, forwarder, id)
+	})
+}
+
+// PurgeDeadLetters implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) PurgeDeadLetters(ctx context.Context, forwarder string) error {
+	return s.adapter.Update(ctx, func(txn MTx) error {
+		return transactions.DeleteDeadLetters(txn, forwarder)
+	})
+}
+
+// ListAlerts implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) ListAlerts(ctx context.Context) ([]string, error) {
+	return s.listItems(ctx, alertItemType)
+}
+
+// ReadAlert implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) ReadAlert(ctx context.Context, name string) (*models.AlertRule, error) {
+	content, err := s.readItem(ctx, alertItemType, name)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, nil
+	}
+
+	rule := &models.AlertRule{}
+	if err := json.Unmarshal(content, rule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert: %w", err)
+	}
+
+	return rule, nil
+}
+
+// WriteAlert implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) WriteAlert(ctx context.Context, name string, rule *models.AlertRule) error {
+	content, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	return s.writeItem(ctx, alertItemType, name, content)
+}
+
+// DeleteAlert implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) DeleteAlert(ctx context.Context, name string) error {
+	return s.adapter.Update(ctx, func(txn MTx) error {
+		if err := transactions.DeleteItem(txn, alertItemType, name); err != nil {
+			return err
+		}
+
+		return transactions.DeleteAlertEvents(txn, name)
+	})
+}
+
+// ListAlertEvents implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) ListAlertEvents(ctx context.Context, name string) ([]models.AlertEvent, error) {
+	var contents [][]byte
+
+	err := s.adapter.View(ctx, func(txn QTx) error {
+		var err error
+		contents, err = transactions.ListAlertEvents(txn, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.AlertEvent, 0, len(contents))
+	for _, content := range contents {
+		var event models.AlertEvent
+		if err := json.Unmarshal(content, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alert event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// RecordAlertEvent implements [storage.ConfigStorage].
+func (s *Storage[QTx, MTx]) RecordAlertEvent(ctx context.Context, event models.AlertEvent) error {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert event: %w", err)
+	}
+
+	return s.adapter.Update(ctx, func(txn MTx) error {
+		return transactions.AppendAlertEvent(txn, event.Alert, event.Timestamp, content, alertHistorySize)
+	})
 }
 
 // HasSystemConfig implements [storage.ConfigStorage].
@@ -240,6 +434,32 @@ func (s *Storage[QTx, MTx]) WriteSystemConfig(ctx context.Context, config *model
 		}
 	}
 
+	for _, subject := range config.SyslogAllowedSubjects {
+		if _, err := path.Match(subject, ""); err != nil || subject == "" {
+			return fmt.Errorf("invalid syslog allow subject: %s", subject)
+		}
+	}
+
+	for i, route := range config.SyslogRoutes {
+		if route.Pipeline == "" {
+			return fmt.Errorf("invalid syslog route %d: missing pipeline", i)
+		}
+
+		for _, origin := range route.Origins {
+			if strings.Contains(origin, "/") {
+				if _, _, err := net.ParseCIDR(origin); err != nil {
+					return fmt.Errorf("invalid syslog route %d: invalid origin: %s", i, origin)
+				}
+			} else if net.ParseIP(origin) == nil {
+				return fmt.Errorf("invalid syslog route %d: invalid origin: %s", i, origin)
+			}
+		}
+	}
+
+	if config.TimestampPolicy.MaxPast < 0 || config.TimestampPolicy.MaxFuture < 0 {
+		return fmt.Errorf("invalid timestamp policy: bounds must not be negative")
+	}
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
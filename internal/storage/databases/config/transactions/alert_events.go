@@ -0,0 +1,79 @@
+package transactions
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"link-society.com/flowg/internal/storage/generic/kv"
+)
+
+// alertEventItemType is the namespace of the firing history of alert rules.
+const alertEventItemType = "alert-event"
+
+// AppendAlertEvent stores content as the newest event of an alert's history,
+// under "alert-event:<alert>:<unix millis>:<uuid>", then drops the oldest events
+// so that at most keep of them remain.
+func AppendAlertEvent(
+	txn kv.MutationTx,
+	alert string,
+	timestamp time.Time,
+	content []byte,
+	keep int,
+) error {
+	key := kv.Key{
+		alertEventItemType,
+		alert,
+		fmt.Sprintf("%020d", timestamp.UnixMilli()),
+		uuid.NewString(),
+	}
+
+	if err := txn.Set(key, content); err != nil {
+		return fmt.Errorf("failed to write event of alert %q: %w", alert, err)
+	}
+
+	var keys []kv.Key
+	for key := range txn.IterKeys(kv.Key{alertEventItemType, alert}, kv.KeyRange{}) {
+		keys = append(keys, key)
+	}
+
+	for len(keys) > keep {
+		if err := txn.Clear(keys[0]); err != nil {
+			return fmt.Errorf("failed to prune events of alert %q: %w", alert, err)
+		}
+		keys = keys[1:]
+	}
+
+	return nil
+}
+
+// ListAlertEvents returns the raw events of an alert's history, newest first.
+func ListAlertEvents(txn kv.QueryTx, alert string) ([][]byte, error) {
+	var events [][]byte
+
+	for pair := range txn.IterPairs(kv.Key{alertEventItemType, alert}, kv.KeyRange{}) {
+		events = append(events, pair.Value())
+	}
+
+	slices.Reverse(events)
+
+	return events, nil
+}
+
+// DeleteAlertEvents removes the whole history of an alert.
+func DeleteAlertEvents(txn kv.MutationTx, alert string) error {
+	var keys []kv.Key
+	for key := range txn.IterKeys(kv.Key{alertEventItemType, alert}, kv.KeyRange{}) {
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		if err := txn.Clear(key); err != nil {
+			return fmt.Errorf("failed to delete events of alert %q: %w", alert, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package transactions
+
+import (
+	"fmt"
+	"strconv"
+
+	"link-society.com/flowg/internal/storage/generic/kv"
+)
+
+// deadLetterItemType is the namespace of the forwarders' dead-letter queues.
+const deadLetterItemType = "dead-letter"
+
+// deadLetterCountItemType is the namespace of the lengths of the forwarders'
+// dead-letter queues, kept so that pushing does not have to count the queue.
+const deadLetterCountItemType = "dead-letter-count"
+
+// AppendDeadLetter stores content at the tail of a forwarder's dead-letter
+// queue, under "dead-letter:<forwarder>:<id>", then drops the oldest entries so
+// that at most keep of them remain. Identifiers must sort in queue order.
+//
+// The length of the queue is tracked under "dead-letter-count:<forwarder>", so
+// only the entries being dropped are read.
+func AppendDeadLetter(
+	txn kv.MutationTx,
+	forwarder string,
+	id string,
+	content []byte,
+	keep int,
+) error {
+	count, err := countDeadLetters(txn, forwarder)
+	if err != nil {
+		return err
+	}
+
+	key := kv.Key{deadLetterItemType, forwarder, id}
+
+	if err := txn.Set(key, content); err != nil {
+		return fmt.Errorf("failed to write dead letter of forwarder %q: %w", forwarder, err)
+	}
+	count++
+
+	if count > keep {
+		var keys []kv.Key
+		for key := range txn.IterKeys(kv.Key{deadLetterItemType, forwarder}, kv.KeyRange{}) {
+			keys = append(keys, key)
+			if len(keys) == count-keep {
+				break
+			}
+		}
+
+		for _, key := range keys {
+			if err := txn.Clear(key); err != nil {
+				return fmt.Errorf("failed to prune dead letters of forwarder %q: %w", forwarder, err)
+			}
+		}
+		count -= len(keys)
+	}
+
+	return writeDeadLetterCount(txn, forwarder, count)
+}
+
+// ListDeadLetters returns the raw entries of a forwarder's dead-letter queue,
+// oldest first, starting after the entry identified by after (from the head of
+// the queue if empty). At most limit entries are returned, or all of them if
+// limit is not positive.
+func ListDeadLetters(txn kv.QueryTx, forwarder string, after string, limit int) ([][]byte, error) {
+	var (
+		letters  [][]byte
+		keyRange kv.KeyRange
+	)
+
+	if after != "" {
+		keyRange.From = kv.Key{deadLetterItemType, forwarder, after}
+	}
+
+	for pair := range txn.IterPairs(kv.Key{deadLetterItemType, forwarder}, keyRange) {
+		if limit > 0 && len(letters) == limit {
+			break
+		}
+
+		// skip the cursor itself
+		if after != "" && pair.Key()[len(pair.Key())-1] == after {
+			continue
+		}
+
+		letters = append(letters, pair.Value())
+	}
+
+	return letters, nil
+}
+
+// DeleteDeadLetter removes one entry from a forwarder's dead-letter queue.
+func DeleteDeadLetter(txn kv.MutationTx, forwarder string, id string) error {
+	key := kv.Key{deadLetterItemType, forwarder, id}
+
+	content, err := txn.Get(key)
+	if err != nil {
+		return fmt.Errorf("failed to read dead letter of forwarder %q: %w", forwarder, err)
+	}
+	if content == nil {
+		return nil
+	}
+
+	count, err := countDeadLetters(txn, forwarder)
+	if err != nil {
+		return err
+	}
+
+	if err := txn.Clear(key); err != nil {
+		return fmt.Errorf("failed to delete dead letter of forwarder %q: %w", forwarder, err)
+	}
+
+	return writeDeadLetterCount(txn, forwarder, count-1)
+}
+
+// DeleteDeadLetters empties a forwarder's dead-letter queue.
+func DeleteDeadLetters(txn kv.MutationTx, forwarder string) error {
+	var keys []kv.Key
+	for key := range txn.IterKeys(kv.Key{deadLetterItemType, forwarder}, kv.KeyRange{}) {
+		keys = append(keys, key)
+	}
+
+	keys = append(keys, kv.Key{deadLetterCountItemType, forwarder})
+
+	for _, key := range keys {
+		if err := txn.Clear(key); err != nil {
+			return fmt.Errorf("failed to delete dead letters of forwarder %q: %w", forwarder, err)
+		}
+	}
+
+	return nil
+}
+
+// countDeadLetters returns the length of a forwarder's dead-letter queue.
+// Queues written before their length was tracked are counted once.
+func countDeadLetters(txn kv.QueryTx, forwarder string) (int, error) {
+	content, err := txn.Get(kv.Key{deadLetterCountItemType, forwarder})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read dead letter count of forwarder %q: %w", forwarder, err)
+	}
+
+	if content == nil {
+		count := 0
+		for range txn.IterKeys(kv.Key{deadLetterItemType, forwarder}, kv.KeyRange{}) {
+			count++
+		}
+
+		return count, nil
+	}
+
+	count, err := strconv.Atoi(string(content))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse dead letter count of forwarder %q: %w", forwarder, err)
+	}
+
+	return count, nil
+}
+
+// writeDeadLetterCount stores the length of a forwarder's dead-letter queue.
+func writeDeadLetterCount(txn kv.MutationTx, forwarder string, count int) error {
+	err := txn.Set(kv.Key{deadLetterCountItemType, forwarder}, []byte(strconv.Itoa(count)))
+	if err != nil {
+		return fmt.Errorf("failed to write dead letter count of forwarder %q: %w", forwarder, err)
+	}
+
+	return nil
+}
@@ -3,10 +3,14 @@ package log
 import (
 	"fmt"
 
+	"encoding/base64"
+	"strings"
+
 	"github.com/google/uuid"
 
 	"link-society.com/flowg/internal/models"
 	"link-society.com/flowg/internal/storage/generic/kv"
+	storage "link-society.com/flowg/internal/storage/interfaces"
 )
 
 // newDbKey builds the time-ordered storage key for the record in a stream:
@@ -21,3 +25,39 @@ func newDbKey(stream string, logRecord *models.LogRecord) kv.Key {
 		uuid.New().String(),
 	}
 }
+
+// encodeCursor turns an entry key into the opaque pagination cursor handed to
+// clients: the URL-safe base64 encoding of "<unix-millis>:<uuid>". The stream
+// is left out since the caller supplies it again with the next page request.
+func encodeCursor(key kv.Key) string {
+	if len(key) != 4 {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(key[2] + ":" + key[3]))
+}
+
+// decodeCursor rebuilds the entry key of the given stream that a cursor
+// produced by [encodeCursor] points at. An empty cursor decodes to a nil key,
+// meaning "start from the newest entry".
+func decodeCursor(stream string, cursor string) (kv.Key, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, &storage.InvalidCursorError{Cursor: cursor}
+	}
+
+	timestamp, id, found := strings.Cut(string(payload), ":")
+	if !found || len(timestamp) != 20 || strings.Trim(timestamp, "0123456789") != "" {
+		return nil, &storage.InvalidCursorError{Cursor: cursor}
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, &storage.InvalidCursorError{Cursor: cursor}
+	}
+
+	return kv.Key{"entry", stream, timestamp, id}, nil
+}
@@ -2,8 +2,11 @@ package log
 
 import (
 	"context"
+	"fmt"
 	"io"
 
+	"encoding/base64"
+	"strconv"
 	"time"
 
 	"link-society.com/flowg/internal/models"
@@ -327,17 +330,252 @@ func (s *Storage[QTx, MTx]) FetchLogs(
 	from, to time.Time,
 	filter filtering.Filter,
 	indexing map[string][]string,
-) ([]models.LogRecord, error) {
-	var results []models.LogRecord
+	cursor string,
+	limit int,
+) ([]models.LogRecord, string, error) {
+	after, err := decodeCursor(stream, cursor)
+	if err != nil {
+		return nil, "", err
+	}
 
-	err := s.adapter.View(ctx, func(txn QTx) error {
+	var (
+		results []models.LogRecord
+		next    kv.Key
+	)
+
+	err = s.adapter.View(ctx, func(txn QTx) error {
 		var err error
-		results, err = transactions.FetchLogs(txn, stream, from, to, filter, indexing)
+		results, next, err = transactions.FetchLogs(txn, stream, from, to, filter, indexing, after, limit)
 		return err
 	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return results, encodeCursor(next), nil
+}
+
+// WalkLogs implements [storage.LogStorage].
+func (s *Storage[QTx, MTx]) WalkLogs(
+	ctx context.Context,
+	stream string,
+	from, to time.Time,
+	filter filtering.Filter,
+	indexing map[string][]string,
+	fn func(*models.LogRecord) error,
+) error {
+	var cursor kv.Key
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var (
+			records []models.LogRecord
+			next    kv.Key
+			scanned int
+		)
+
+		err := s.adapter.View(ctx, func(txn QTx) error {
+			var err error
+			records, next, scanned, err = transactions.ScanLogs(
+				txn, stream, from, to, filter, indexing, cursor, s.batchSize,
+			)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		// The callback runs outside of the transaction, so a slow consumer never
+		// keeps it open past the backend's time limits.
+		for i := range records {
+			if err := fn(&records[i]); err != nil {
+				return err
+			}
+		}
+
+		if scanned < s.batchSize {
+			return nil
+		}
+
+		cursor = next
+	}
+}
+
+// Aggregate implements [storage.LogStorage].
+//
+// A count-only query without filter, grouped by a single indexed field, is
+// answered from the field's inverted index without decoding any record. Every
+// other query walks the matching records in bounded batches.
+func (s *Storage[QTx, MTx]) Aggregate(
+	ctx context.Context,
+	stream string,
+	from, to time.Time,
+	filter filtering.Filter,
+	indexing map[string][]string,
+	query models.AggregationQuery,
+) ([]models.AggregationBucket, error) {
+	aggregator := transactions.NewAggregator(query)
+
+	if filter == nil && len(query.GroupBy) == 1 && query.IsCountOnly() {
+		field := query.GroupBy[0]
+
+		var indexed bool
+		err := s.adapter.View(ctx, func(txn QTx) error {
+			config, err := transactions.FetchStreamConfig(txn, stream)
+			indexed = config.IsFieldIndexed(field)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if indexed {
+			err := s.countByIndexedField(ctx, stream, field, from, to, indexing, aggregator)
+			if err != nil {
+				return nil, err
+			}
+
+			return aggregator.Buckets(), nil
+		}
+	}
+
+	err := s.WalkLogs(ctx, stream, from, to, filter, indexing, func(record *models.LogRecord) error {
+		aggregator.Add(record)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return results, nil
+	return aggregator.Buckets(), nil
+}
+
+// countByIndexedField feeds the aggregator with the number of entries of a
+// stream within [from, to], per value of an indexed field, by reading keys
+// only. Entries lacking the field, which the index cannot point at, are counted
+// under the empty value.
+//
+// The entries are first counted per timestamp over the time window, then each
+// distinct value's index keys are counted over that same window; whatever the
+// index does not account for is the count of entries lacking the field. Like
+// [Storage.WalkLogs], every pass is split into transactions examining at most
+// batchSize keys.
+func (s *Storage[QTx, MTx]) countByIndexedField(
+	ctx context.Context,
+	stream string,
+	field string,
+	from, to time.Time,
+	indexing map[string][]string,
+	aggregator *transactions.Aggregator,
+) error {
+	missing, err := s.countBatches(ctx, func(txn QTx, cursor kv.Key) (map[string]int64, kv.Key, int, error) {
+		return transactions.CountEntriesBatch(txn, stream, from, to, indexing, cursor, s.batchSize)
+	})
+	if err != nil {
+		return err
+	}
+
+	after := ""
+	for {
+		var encodedValues []string
+
+		err := s.adapter.View(ctx, func(txn QTx) error {
+			encodedValues = transactions.ListIndexedValuesBatch(txn, stream, field, after, s.batchSize)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, encodedValue := range encodedValues {
+			value, err := base64.StdEncoding.DecodeString(encodedValue)
+			if err != nil {
+				return fmt.Errorf(
+					"could not decode base64 value '%s' for field '%s': %w",
+					encodedValue, field, err,
+				)
+			}
+
+			counts, err := s.countBatches(ctx, func(txn QTx, cursor kv.Key) (map[string]int64, kv.Key, int, error) {
+				return transactions.CountIndexedValueBatch(
+					txn, stream, field, encodedValue, from, to, indexing, cursor, s.batchSize,
+				)
+			})
+			if err != nil {
+				return err
+			}
+
+			for segment, n := range counts {
+				aggregator.AddCount(parseTimestampSegment(segment), []string{string(value)}, n)
+				missing[segment] -= n
+			}
+		}
+
+		if len(encodedValues) < s.batchSize {
+			break
+		}
+
+		after = encodedValues[len(encodedValues)-1]
+	}
+
+	for segment, n := range missing {
+		if n > 0 {
+			aggregator.AddCount(parseTimestampSegment(segment), []string{""}, n)
+		}
+	}
+
+	return nil
+}
+
+// countBatches sums the per-timestamp counts of successive batches, each run in
+// its own transaction and resuming after the last key of the previous one,
+// until a batch examines fewer than batchSize keys.
+func (s *Storage[QTx, MTx]) countBatches(
+	ctx context.Context,
+	batch func(txn QTx, cursor kv.Key) (map[string]int64, kv.Key, int, error),
+) (map[string]int64, error) {
+	totals := map[string]int64{}
+
+	var cursor kv.Key
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var (
+			counts  map[string]int64
+			next    kv.Key
+			scanned int
+		)
+
+		err := s.adapter.View(ctx, func(txn QTx) error {
+			var err error
+			counts, next, scanned, err = batch(txn, cursor)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for segment, n := range counts {
+			totals[segment] += n
+		}
+
+		if scanned < s.batchSize {
+			return totals, nil
+		}
+
+		cursor = next
+	}
+}
+
+// parseTimestampSegment decodes the zero-padded unix-millis segment of an entry
+// key back into a time.
+func parseTimestampSegment(segment string) time.Time {
+	millis, _ := strconv.ParseInt(segment, 10, 64)
+	return time.UnixMilli(millis)
 }
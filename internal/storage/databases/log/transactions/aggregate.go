@@ -0,0 +1,345 @@
+package transactions
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/storage/generic/kv"
+)
+
+// Aggregator accumulates log records into the buckets of an
+// [models.AggregationQuery]. Records are fed one at a time, so a whole time
+// window can be summarized across successive transactions while only the
+// per-bucket statistics are kept in memory.
+type Aggregator struct {
+	query   models.AggregationQuery
+	buckets map[string]*aggregationState
+}
+
+// aggregationState holds the running statistics of one bucket, with one slot
+// per requested aggregation.
+type aggregationState struct {
+	timestamp *time.Time
+	group     []string
+
+	count    int64
+	distinct []map[string]struct{}
+	sum      []float64
+	min      []float64
+	max      []float64
+	numeric  []int64
+}
+
+// NewAggregator returns an empty [Aggregator] for the given query.
+func NewAggregator(query models.AggregationQuery) *Aggregator {
+	return &Aggregator{
+		query:   query,
+		buckets: map[string]*aggregationState{},
+	}
+}
+
+// Add accounts for one record in the bucket of its group and time interval.
+func (a *Aggregator) Add(record *models.LogRecord) {
+	group := make([]string, len(a.query.GroupBy))
+	for i, field := range a.query.GroupBy {
+		group[i] = record.Fields[field]
+	}
+
+	state := a.bucket(record.Timestamp, group)
+	state.count++
+
+	for i, aggregation := range a.query.Aggregations {
+		switch aggregation.Function {
+		case models.AGGREGATION_COUNT_DISTINCT:
+			state.distinct[i][record.Fields[aggregation.Field]] = struct{}{}
+
+		case models.AGGREGATION_MIN, models.AGGREGATION_MAX, models.AGGREGATION_AVG:
+			value, exists := record.Fields[aggregation.Field]
+			if !exists {
+				continue
+			}
+
+			number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || math.IsNaN(number) {
+				continue
+			}
+
+			state.sum[i] += number
+			state.min[i] = math.Min(state.min[i], number)
+			state.max[i] = math.Max(state.max[i], number)
+			state.numeric[i]++
+		}
+	}
+}
+
+// AddCount accounts for n records of the given group at the given time without
+// decoding them. It only makes sense for count-only queries (see
+// [models.AggregationQuery.IsCountOnly]).
+func (a *Aggregator) AddCount(timestamp time.Time, group []string, n int64) {
+	a.bucket(timestamp, group).count += n
+}
+
+// Buckets returns the accumulated statistics, ordered by time interval then by
+// group values.
+func (a *Aggregator) Buckets() []models.AggregationBucket {
+	states := make([]*aggregationState, 0, len(a.buckets))
+	for _, state := range a.buckets {
+		states = append(states, state)
+	}
+
+	slices.SortFunc(states, func(x, y *aggregationState) int {
+		if x.timestamp != nil && y.timestamp != nil {
+			if c := x.timestamp.Compare(*y.timestamp); c != 0 {
+				return c
+			}
+		}
+
+		return slices.Compare(x.group, y.group)
+	})
+
+	results := make([]models.AggregationBucket, 0, len(states))
+
+	for _, state := range states {
+		bucket := models.AggregationBucket{
+			Timestamp: state.timestamp,
+			Group:     make(map[string]string, len(a.query.GroupBy)),
+			Values:    make(map[string]float64, len(a.query.Aggregations)),
+		}
+
+		for i, field := range a.query.GroupBy {
+			bucket.Group[field] = state.group[i]
+		}
+
+		for i, aggregation := range a.query.Aggregations {
+			name := aggregation.String()
+
+			switch aggregation.Function {
+			case models.AGGREGATION_COUNT:
+				bucket.Values[name] = float64(state.count)
+
+			case models.AGGREGATION_COUNT_DISTINCT:
+				bucket.Values[name] = float64(len(state.distinct[i]))
+
+			case models.AGGREGATION_MIN:
+				if state.numeric[i] > 0 {
+					bucket.Values[name] = state.min[i]
+				}
+
+			case models.AGGREGATION_MAX:
+				if state.numeric[i] > 0 {
+					bucket.Values[name] = state.max[i]
+				}
+
+			case models.AGGREGATION_AVG:
+				if state.numeric[i] > 0 {
+					bucket.Values[name] = state.sum[i] / float64(state.numeric[i])
+				}
+			}
+		}
+
+		results = append(results, bucket)
+	}
+
+	return results
+}
+
+// bucket returns the state of the bucket covering the given group and time,
+// creating it on first use.
+func (a *Aggregator) bucket(timestamp time.Time, group []string) *aggregationState {
+	var start *time.Time
+	if a.query.Interval > 0 {
+		truncated := timestamp.UTC().Truncate(a.query.Interval)
+		start = &truncated
+	}
+
+	id := strings.Join(group, "\x00")
+	if start != nil {
+		id = strconv.FormatInt(start.UnixNano(), 10) + "\x00" + id
+	}
+
+	state, exists := a.buckets[id]
+	if !exists {
+		n := len(a.query.Aggregations)
+		state = &aggregationState{
+			timestamp: start,
+			group:     group,
+			distinct:  make([]map[string]struct{}, n),
+			sum:       make([]float64, n),
+			min:       make([]float64, n),
+			max:       make([]float64, n),
+			numeric:   make([]int64, n),
+		}
+
+		for i := range n {
+			state.distinct[i] = map[string]struct{}{}
+			state.min[i] = math.Inf(1)
+			state.max[i] = math.Inf(-1)
+		}
+
+		a.buckets[id] = state
+	}
+
+	return state
+}
+
+// CountEntriesBatch counts the entries of a stream within [from, to] that
+// satisfy the requested indexed-field constraints, per timestamp segment of
+// their key, by reading keys only. It resumes strictly after cursor (nil to
+// start at from) and examines at most limit entries.
+//
+// It returns the counts, the last entry key it examined — to be passed as
+// cursor on the next call — and how many entries it examined. An examined count
+// below limit means the time window has been fully counted.
+func CountEntriesBatch(
+	txn kv.QueryTx,
+	stream string,
+	from, to time.Time,
+	indexing map[string][]string,
+	cursor kv.Key,
+	limit int,
+) (map[string]int64, kv.Key, int, error) {
+	prefix := kv.Key{"entry", stream}
+	keyRange := kv.KeyRange{
+		From: kv.Key{"entry", stream, fmt.Sprintf("%020d", from.UnixMilli())},
+		To:   kv.Key{"entry", stream, fmt.Sprintf("%020d", to.UnixMilli())},
+	}
+
+	return countKeysBatch(txn, stream, prefix, keyRange, indexing, cursor, limit)
+}
+
+// ListIndexedValuesBatch returns at most limit of the distinct base64-encoded
+// values an indexed field takes in a stream, in key order, starting strictly
+// after the value after ("" to start from the first one).
+//
+// Rather than walking every index key, it seeks past the index keys of each
+// value it finds, so it costs a single read per value. The seek relies on a
+// "\xff" segment sorting after the "entry" segment that follows the value in
+// the index keys, and base64 never producing that byte.
+//
+// Fewer than limit values means there are no more of them.
+func ListIndexedValuesBatch(
+	txn kv.QueryTx,
+	stream string,
+	field string,
+	after string,
+	limit int,
+) []string {
+	prefix := kv.Key{"index", stream, "field", field}
+	values := []string{}
+
+	for len(values) < limit {
+		var keyRange kv.KeyRange
+		if after != "" {
+			keyRange.From = append(slices.Clone(prefix), after, "\xff")
+		}
+
+		found := false
+		for key := range txn.IterKeys(prefix, keyRange) {
+			if len(key) <= len(prefix) {
+				continue
+			}
+
+			after = key[len(prefix)]
+			values = append(values, after)
+			found = true
+			break
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return values
+}
+
+// CountIndexedValueBatch counts the entries of a stream within [from, to]
+// carrying the given base64-encoded value of an indexed field and satisfying
+// the requested indexed-field constraints, per timestamp segment of their key.
+// It walks the value's index keys only, resuming strictly after cursor (nil to
+// start at from) and examining at most limit of them.
+//
+// Like [CountEntriesBatch], it returns the counts, the last index key it
+// examined and how many it examined.
+func CountIndexedValueBatch(
+	txn kv.QueryTx,
+	stream string,
+	field string,
+	encodedValue string,
+	from, to time.Time,
+	indexing map[string][]string,
+	cursor kv.Key,
+	limit int,
+) (map[string]int64, kv.Key, int, error) {
+	prefix := kv.Key{"index", stream, "field", field, encodedValue}
+	keyRange := kv.KeyRange{
+		From: append(slices.Clone(prefix), "entry", stream, fmt.Sprintf("%020d", from.UnixMilli())),
+		To:   append(slices.Clone(prefix), "entry", stream, fmt.Sprintf("%020d", to.UnixMilli())),
+	}
+
+	return countKeysBatch(txn, stream, prefix, keyRange, indexing, cursor, limit)
+}
+
+// countKeysBatch counts per timestamp segment the keys of keyRange ending with
+// an entry key that satisfies the requested indexed-field constraints, resuming
+// strictly after cursor and examining at most limit keys.
+func countKeysBatch(
+	txn kv.QueryTx,
+	stream string,
+	prefix kv.Key,
+	keyRange kv.KeyRange,
+	indexing map[string][]string,
+	cursor kv.Key,
+	limit int,
+) (map[string]int64, kv.Key, int, error) {
+	encodedIndexing := encodeIndexingMap(indexing)
+
+	counts := map[string]int64{}
+
+	var last kv.Key
+	scanned := 0
+
+	if cursor != nil {
+		keyRange.From = cursor
+	}
+
+	for key := range txn.IterKeys(prefix, keyRange) {
+		// KeyRange.From is inclusive on some backends and exclusive on others;
+		// skip the cursor itself so a batch always makes forward progress.
+		if cursor != nil && keysEqual(key, cursor) {
+			continue
+		}
+
+		if scanned >= limit {
+			break
+		}
+
+		last = key
+		scanned++
+
+		if len(key) < 4 {
+			continue
+		}
+
+		entryKey := key[len(key)-4:]
+		if entryKey[0] != "entry" {
+			continue
+		}
+
+		matched, err := matchesIndexingForKey(txn, stream, entryKey, encodedIndexing)
+		if err != nil {
+			return nil, last, scanned, err
+		}
+
+		if matched {
+			counts[entryKey[2]]++
+		}
+	}
+
+	return counts, last, scanned, nil
+}
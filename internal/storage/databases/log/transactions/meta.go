@@ -65,6 +65,40 @@ func FetchStreamFields(txn kv.QueryTx, stream string) []string {
 	return fields
 }
 
+// FetchStreamConfig returns a stream's configuration without creating it, the
+// default (zero-value) config standing in for a stream that has none yet. Demo
+// mode short-circuits to the fixed config.
+func FetchStreamConfig(txn kv.QueryTx, stream string) (models.StreamConfig, error) {
+	if featureflags.GetDemoMode() {
+		return demoStreamConfig, nil
+	}
+
+	var streamConfig models.StreamConfig
+
+	val, err := txn.Get(kv.Key{"stream", "config", stream})
+	if err != nil {
+		return models.StreamConfig{}, fmt.Errorf(
+			"could not fetch stream config '%s': %w",
+			stream, err,
+		)
+	}
+
+	if len(val) > 0 {
+		if err := json.Unmarshal(val, &streamConfig); err != nil {
+			return models.StreamConfig{}, fmt.Errorf(
+				"could not unmarshal stream config '%s': %w",
+				stream, err,
+			)
+		}
+	}
+
+	if streamConfig.IndexedFields == nil {
+		streamConfig.IndexedFields = []string{}
+	}
+
+	return streamConfig, nil
+}
+
 // GetOrCreateStreamConfig returns a stream's configuration, lazily creating an
 // empty (default) config the first time a stream is referenced so later writes
 // have something to update. Demo mode short-circuits to the fixed config.
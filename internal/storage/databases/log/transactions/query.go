@@ -3,7 +3,6 @@ package transactions
 import (
 	"fmt"
 
-	"sort"
 	"time"
 
 	"encoding/json"
@@ -16,93 +15,181 @@ import (
 )
 
 // FetchLogs returns the records of a stream between two timestamps, newest
-// first. It gathers the candidate keys in the time window, narrows them to those
-// present in the requested field indexes, then decodes each surviving record and
-// keeps the ones the filter accepts.
+// first. It walks the entry keys of the time window backwards, skipping those
+// absent from the requested field indexes and decoding each surviving record to
+// keep the ones the filter accepts.
+//
+// When cursor is not nil, only entries strictly older than that key are
+// considered, so a previous page's last key resumes the scan right after it.
+// When limit is positive, the walk stops once limit records are found, and the
+// key of the last one is returned as the cursor of the next page; a nil cursor
+// means the window holds no further entries. The work done for a page is thus
+// bounded by the entries it skips and returns, whatever the size of the window.
 func FetchLogs(
 	txn kv.QueryTx,
 	stream string,
 	from, to time.Time,
 	filter filtering.Filter,
 	indexing map[string][]string,
-) ([]models.LogRecord, error) {
+	cursor kv.Key,
+	limit int,
+) ([]models.LogRecord, kv.Key, error) {
 	results := []models.LogRecord{}
 
-	keys := fetchKeysByTime(txn, stream, from, to)
-	sort.Sort(sort.Reverse(kv.KeySlice(keys)))
+	var last kv.Key
+
+	streamPrefix := kv.Key{"entry", stream}
+	fromPrefix := kv.Key{"entry", stream, fmt.Sprintf("%020d", from.UnixMilli())}
+	toPrefix := kv.Key{"entry", stream, fmt.Sprintf("%020d", to.UnixMilli())}
+
+	if cursor != nil && len(cursor) > 2 && cursor[2] < toPrefix[2] {
+		toPrefix = kv.Key{"entry", stream, cursor[2]}
+	}
 
-	var keysForIndex []kv.Key
 	encodedIndexing := encodeIndexingMap(indexing)
 
-	for _, key := range keys {
+	keyRange := kv.KeyRange{From: fromPrefix, To: toPrefix, Reverse: true}
+	for pair := range txn.IterPairs(streamPrefix, keyRange) {
+		key := pair.Key()
+
+		// Entries sharing the cursor's timestamp may sort on either side of it.
+		if cursor != nil && !keyBefore(key, cursor) {
+			continue
+		}
+
+		if limit > 0 && len(results) >= limit {
+			// Older entries remain past this page: resume right after the last
+			// record returned. Only handing out a cursor in that case lets
+			// callers recognize the last page without an extra round-trip.
+			return results, last, nil
+		}
+
 		matched, err := matchesIndexingForKey(txn, stream, key, encodedIndexing)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		if matched {
-			keysForIndex = append(keysForIndex, key)
+		if !matched {
+			continue
 		}
-	}
 
-	for _, key := range keysForIndex {
-		entry, err := fetchRecord(txn, stream, key)
-		if err != nil {
-			return nil, err
+		var entry models.LogRecord
+		if err := json.Unmarshal(pair.Value(), &entry); err != nil {
+			return nil, nil, fmt.Errorf(
+				"could not unmarshal log entry '%s': %w",
+				strings.Join(key, ":"), err,
+			)
 		}
 
-		if filter == nil {
-			results = append(results, entry)
-		} else {
+		if filter != nil {
 			matches, err := filter.Evaluate(&entry)
 			if err != nil {
-				return nil, fmt.Errorf(
+				return nil, nil, fmt.Errorf(
 					"failed to evaluate filter for log entry '%s': %w",
 					strings.Join(key, ":"), err,
 				)
 			}
 
-			if matches {
-				results = append(results, entry)
+			if !matches {
+				continue
 			}
 		}
+
+		results = append(results, entry)
+		last = key
 	}
 
-	return results, nil
+	return results, nil, nil
+}
+
+// keyBefore reports whether key a sorts strictly before key b.
+func keyBefore(a, b kv.Key) bool {
+	return kv.KeySlice{a, b}.Less(0, 1)
 }
 
-// fetchKeysByTime collects the entry keys whose embedded timestamp falls in
-// [from, to]. Because the timestamp is zero-padded in the key, it can seek
-// straight to the lower bound and stop as soon as a key sorts past the upper
-// bound instead of scanning the whole stream.
-func fetchKeysByTime(txn kv.QueryTx, stream string, from, to time.Time) []kv.Key {
-	keys := []kv.Key{}
+// ScanLogs reads the records of a stream between two timestamps, oldest first,
+// resuming strictly after cursor (nil to start at from). It examines at most
+// limit entries, skipping those absent from the requested field indexes or
+// rejected by the filter, and decodes each one straight from the iterated pair.
+//
+// It returns the matching records, the last entry key it examined — to be
+// passed as cursor on the next call — and how many entries it examined. An
+// examined count below limit means the time window has been fully scanned.
+// Splitting the work this way lets a whole window be walked in bounded
+// transactions without ever holding it in memory.
+func ScanLogs(
+	txn kv.QueryTx,
+	stream string,
+	from, to time.Time,
+	filter filtering.Filter,
+	indexing map[string][]string,
+	cursor kv.Key,
+	limit int,
+) ([]models.LogRecord, kv.Key, int, error) {
+	results := []models.LogRecord{}
+
+	var last kv.Key
+	scanned := 0
 
 	streamPrefix := kv.Key{"entry", stream}
 	fromPrefix := kv.Key{"entry", stream, fmt.Sprintf("%020d", from.UnixMilli())}
 	toPrefix := kv.Key{"entry", stream, fmt.Sprintf("%020d", to.UnixMilli())}
 
-	for key := range txn.IterKeys(streamPrefix, kv.KeyRange{From: fromPrefix, To: toPrefix}) {
-		keys = append(keys, key)
+	if cursor != nil {
+		fromPrefix = cursor
 	}
 
-	return keys
-}
+	encodedIndexing := encodeIndexingMap(indexing)
 
-// fetchRecord loads and JSON-decodes a single log record by its key.
-func fetchRecord(txn kv.QueryTx, stream string, key kv.Key) (models.LogRecord, error) {
-	val, err := txn.Get(key)
-	if err != nil {
-		return models.LogRecord{}, fmt.Errorf(
-			"could not fetch log entry '%s' from stream '%s': %w",
-			strings.Join(key, ":"), stream, err,
-		)
-	}
+	for pair := range txn.IterPairs(streamPrefix, kv.KeyRange{From: fromPrefix, To: toPrefix}) {
+		key := pair.Key()
+
+		// KeyRange.From is inclusive on some backends and exclusive on others;
+		// skip the cursor itself so a batch always makes forward progress.
+		if cursor != nil && keysEqual(key, cursor) {
+			continue
+		}
+
+		if scanned >= limit {
+			break
+		}
+
+		last = key
+		scanned++
+
+		matched, err := matchesIndexingForKey(txn, stream, key, encodedIndexing)
+		if err != nil {
+			return nil, last, scanned, err
+		}
+
+		if !matched {
+			continue
+		}
+
+		var entry models.LogRecord
+		if err := json.Unmarshal(pair.Value(), &entry); err != nil {
+			return nil, last, scanned, fmt.Errorf(
+				"could not unmarshal log entry '%s': %w",
+				strings.Join(key, ":"), err,
+			)
+		}
+
+		if filter != nil {
+			matches, err := filter.Evaluate(&entry)
+			if err != nil {
+				return nil, last, scanned, fmt.Errorf(
+					"failed to evaluate filter for log entry '%s': %w",
+					strings.Join(key, ":"), err,
+				)
+			}
+
+			if !matches {
+				continue
+			}
+		}
 
-	var record models.LogRecord
-	if err := json.Unmarshal(val, &record); err != nil {
-		return models.LogRecord{}, fmt.Errorf("could not unmarshal log entry '%s': %w", strings.Join(key, ":"), err)
+		results = append(results, entry)
 	}
 
-	return record, nil
+	return results, last, scanned, nil
 }
@@ -11,6 +11,8 @@ type KeyRange struct {
 	From Key
 	// The end of the range (inclusive), ignored if nil
 	To Key
+	// Iterate from the end of the range to its start
+	Reverse bool
 }
 
 // Represents a sortable sequence of keys
@@ -33,6 +33,9 @@ type AuthStorage interface {
 	// ListUserScopes returns the permission scopes granted to the named user
 	// through its roles.
 	ListUserScopes(ctx context.Context, name string) ([]models.Scope, error)
+	// ListUserResourceScopes returns the scopes granted to the named user on
+	// some resources only, through its roles.
+	ListUserResourceScopes(ctx context.Context, name string) ([]models.ResourceScope, error)
 	// SaveUser creates or replaces a user, setting the given password.
 	SaveUser(ctx context.Context, user models.User, password string) error
 	// PatchUserRoles updates only the role assignments of an existing user.
@@ -44,18 +47,21 @@ type AuthStorage interface {
 	// credentials of the named user.
 	VerifyUserPassword(ctx context.Context, name string, password string) (bool, error)
 	// VerifyUserPermission reports whether the named user has been granted the
-	// given permission scope.
-	VerifyUserPermission(ctx context.Context, username string, scope models.Scope) (bool, error)
+	// given permission scope, on every resource or on the given one. An empty
+	// resource only matches scopes granted on every resource.
+	VerifyUserPermission(ctx context.Context, username string, scope models.Scope, resource models.Resource) (bool, error)
 
-	// CreateToken mints a new personal access token for the named user and
-	// returns the clear-text token together with its UUID.
-	CreateToken(ctx context.Context, username string) (string, string, error)
+	// CreateToken mints a new personal access token for the named user, with
+	// the name, scopes and expiry of the given token, and returns the
+	// clear-text token together with its description.
+	CreateToken(ctx context.Context, username string, token models.Token) (string, *models.Token, error)
 	// VerifyToken resolves a clear-text personal access token to its owning
-	// user, or returns an error if the token is unknown.
-	VerifyToken(ctx context.Context, token string) (*models.User, error)
-	// ListTokens returns the UUIDs of the personal access tokens owned by the
-	// named user.
-	ListTokens(ctx context.Context, username string) ([]string, error)
+	// user and its description, recording its use. Both are nil if the token
+	// is unknown or expired.
+	VerifyToken(ctx context.Context, token string) (*models.User, *models.Token, error)
+	// ListTokens returns the description of the personal access tokens owned
+	// by the named user.
+	ListTokens(ctx context.Context, username string) ([]models.Token, error)
 	// DeleteToken revokes the personal access token identified by tokenUUID for
 	// the named user.
 	DeleteToken(ctx context.Context, username string, tokenUUID string) error
@@ -67,6 +73,15 @@ type AuthStorage interface {
 	FetchAuthProvider(ctx context.Context, name string) (*models.AuthProvider, error)
 	// SaveAuthProvider creates or replaces an auth provider.
 	SaveAuthProvider(ctx context.Context, provider models.AuthProvider) error
-	// DeleteAuthProvider removes the auth provider with the given name.
+	// DeleteAuthProvider removes the auth provider with the given name, along
+	// with the links of its identities to users.
 	DeleteAuthProvider(ctx context.Context, name string) error
+
+	// FetchLinkedUser returns the name of the user linked to the given subject
+	// of an auth provider, or an empty string if no user is.
+	FetchLinkedUser(ctx context.Context, provider string, subject string) (string, error)
+	// CreateLinkedUser creates a user, setting the given password, and links it
+	// to the given subject of an auth provider. It returns false, without
+	// changing anything, if a user with the same name already exists.
+	CreateLinkedUser(ctx context.Context, provider string, subject string, user models.User, password string) (bool, error)
 }
@@ -7,7 +7,8 @@ import (
 )
 
 // ConfigStorage is the contract for persisting FlowG's configuration objects:
-// transformers, pipelines, forwarders, and the global system configuration.
+// transformers, pipelines, forwarders, alert rules and their firing history, and
+// the global system configuration.
 //
 // It embeds [Streamable] so the whole store can be backed up and restored as a
 // stream. Implementations live under internal/storage/backends.
@@ -45,8 +46,39 @@ type ConfigStorage interface {
 	ReadForwarder(ctx context.Context, name string) (*models.ForwarderV2, error)
 	// WriteForwarder creates or replaces the named forwarder.
 	WriteForwarder(ctx context.Context, name string, forwarder *models.ForwarderV2) error
-	// DeleteForwarder removes the named forwarder.
+	// DeleteForwarder removes the named forwarder together with its dead-letter
+	// queue.
 	DeleteForwarder(ctx context.Context, name string) error
+	// ListDeadLetters returns the records in the named forwarder's dead-letter
+	// queue, oldest first, starting after the record whose ID is after (from the
+	// head of the queue if empty). At most limit records are returned, or all of
+	// them if limit is not positive.
+	ListDeadLetters(ctx context.Context, forwarder string, after string, limit int) ([]models.DeadLetter, error)
+	// PushDeadLetter appends a record to the dead-letter queue of the forwarder
+	// it names, assigning its ID and dropping the oldest records once the queue
+	// is full.
+	PushDeadLetter(ctx context.Context, letter models.DeadLetter) error
+	// DeleteDeadLetter removes one record from the named forwarder's
+	// dead-letter queue.
+	DeleteDeadLetter(ctx context.Context, forwarder string, id string) error
+	// PurgeDeadLetters empties the named forwarder's dead-letter queue.
+	PurgeDeadLetters(ctx context.Context, forwarder string) error
+
+	// ListAlerts returns the names of every stored alert rule.
+	ListAlerts(ctx context.Context) ([]string, error)
+	// ReadAlert returns the named alert rule, or nil if no rule with that name
+	// exists.
+	ReadAlert(ctx context.Context, name string) (*models.AlertRule, error)
+	// WriteAlert creates or replaces the named alert rule.
+	WriteAlert(ctx context.Context, name string, rule *models.AlertRule) error
+	// DeleteAlert removes the named alert rule together with its firing history.
+	DeleteAlert(ctx context.Context, name string) error
+	// ListAlertEvents returns the recorded firings of the named alert rule,
+	// newest first.
+	ListAlertEvents(ctx context.Context, name string) ([]models.AlertEvent, error)
+	// RecordAlertEvent appends a firing to the history of the alert rule it
+	// names, dropping the oldest firings once the history is full.
+	RecordAlertEvent(ctx context.Context, event models.AlertEvent) error
 
 	// HasSystemConfig reports whether a system configuration has been stored.
 	HasSystemConfig(ctx context.Context) (bool, error)
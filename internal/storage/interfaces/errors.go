@@ -0,0 +1,15 @@
+package interfaces
+
+import "fmt"
+
+// InvalidCursorError is returned by [LogStorage.FetchLogs] when the pagination
+// cursor it was given was not produced by a previous call.
+type InvalidCursorError struct {
+	Cursor string
+}
+
+var _ error = (*InvalidCursorError)(nil)
+
+func (e *InvalidCursorError) Error() string {
+	return fmt.Sprintf("invalid cursor: %q", e.Cursor)
+}
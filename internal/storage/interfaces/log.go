@@ -47,12 +47,48 @@ type LogStorage interface {
 	Ingest(ctx context.Context, stream string, logRecord *models.LogRecord) (kv.Key, error)
 
 	// FetchLogs returns the records of the named stream within the [from, to]
-	// time range that satisfy the given filter and indexing constraints.
+	// time range that satisfy the given filter and indexing constraints, newest
+	// first.
+	//
+	// Results are paginated: a positive limit caps the number of records
+	// returned, and the returned cursor, when not empty, is passed back to fetch
+	// the next (older) page. An empty cursor starts from the newest record. A
+	// cursor that cannot be decoded yields an [InvalidCursorError].
 	FetchLogs(
 		ctx context.Context,
 		stream string,
 		from, to time.Time,
 		filter filtering.Filter,
 		indexing map[string][]string,
-	) ([]models.LogRecord, error)
+		cursor string,
+		limit int,
+	) ([]models.LogRecord, string, error)
+
+	// WalkLogs calls fn for every record of the named stream within the
+	// [from, to] time range that satisfies the given filter and indexing
+	// constraints, oldest first.
+	//
+	// Records are read in bounded batches, so the whole range never has to fit
+	// in memory. An error returned by fn stops the walk and is returned as is.
+	WalkLogs(
+		ctx context.Context,
+		stream string,
+		from, to time.Time,
+		filter filtering.Filter,
+		indexing map[string][]string,
+		fn func(*models.LogRecord) error,
+	) error
+
+	// Aggregate summarizes the records of the named stream within the
+	// [from, to] time range that satisfy the given filter and indexing
+	// constraints, computing the query's statistics per group and, for
+	// histograms, per time interval.
+	Aggregate(
+		ctx context.Context,
+		stream string,
+		from, to time.Time,
+		filter filtering.Filter,
+		indexing map[string][]string,
+		query models.AggregationQuery,
+	) ([]models.AggregationBucket, error)
 }
@@ -67,6 +67,11 @@ func (m *MockAuthStorage) ListUserScopes(ctx context.Context, name string) ([]mo
 	return args.Get(0).([]models.Scope), args.Error(1)
 }
 
+func (m *MockAuthStorage) ListUserResourceScopes(ctx context.Context, name string) ([]models.ResourceScope, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]models.ResourceScope), args.Error(1)
+}
+
 func (m *MockAuthStorage) SaveUser(ctx context.Context, user models.User, password string) error {
 	args := m.Called(ctx, user, password)
 	return args.Error(0)
@@ -87,24 +92,24 @@ func (m *MockAuthStorage) VerifyUserPassword(ctx context.Context, name string, p
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockAuthStorage) VerifyUserPermission(ctx context.Context, username string, scope models.Scope) (bool, error) {
-	args := m.Called(ctx, username, scope)
+func (m *MockAuthStorage) VerifyUserPermission(ctx context.Context, username string, scope models.Scope, resource models.Resource) (bool, error) {
+	args := m.Called(ctx, username, scope, resource)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockAuthStorage) CreateToken(ctx context.Context, username string) (string, string, error) {
-	args := m.Called(ctx, username)
-	return args.String(0), args.String(1), args.Error(2)
+func (m *MockAuthStorage) CreateToken(ctx context.Context, username string, token models.Token) (string, *models.Token, error) {
+	args := m.Called(ctx, username, token)
+	return args.String(0), args.Get(1).(*models.Token), args.Error(2)
 }
 
-func (m *MockAuthStorage) VerifyToken(ctx context.Context, token string) (*models.User, error) {
+func (m *MockAuthStorage) VerifyToken(ctx context.Context, token string) (*models.User, *models.Token, error) {
 	args := m.Called(ctx, token)
-	return args.Get(0).(*models.User), args.Error(1)
+	return args.Get(0).(*models.User), args.Get(1).(*models.Token), args.Error(2)
 }
 
-func (m *MockAuthStorage) ListTokens(ctx context.Context, username string) ([]string, error) {
+func (m *MockAuthStorage) ListTokens(ctx context.Context, username string) ([]models.Token, error) {
 	args := m.Called(ctx, username)
-	return args.Get(0).([]string), args.Error(1)
+	return args.Get(0).([]models.Token), args.Error(1)
 }
 
 func (m *MockAuthStorage) DeleteToken(ctx context.Context, username string, tokenUUID string) error {
@@ -131,3 +136,19 @@ func (m *MockAuthStorage) DeleteAuthProvider(ctx context.Context, name string) e
 	args := m.Called(ctx, name)
 	return args.Error(0)
 }
+
+func (m *MockAuthStorage) FetchLinkedUser(ctx context.Context, provider string, subject string) (string, error) {
+	args := m.Called(ctx, provider, subject)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthStorage) CreateLinkedUser(
+	ctx context.Context,
+	provider string,
+	subject string,
+	user models.User,
+	password string,
+) (bool, error) {
+	args := m.Called(ctx, provider, subject, user, password)
+	return args.Bool(0), args.Error(1)
+}
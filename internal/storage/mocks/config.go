@@ -101,6 +101,56 @@ func (m *MockConfigStorage) DeleteForwarder(ctx context.Context, name string) er
 	return args.Error(0)
 }
 
+func (m *MockConfigStorage) ListDeadLetters(ctx context.Context, forwarder string, after string, limit int) ([]models.DeadLetter, error) {
+	args := m.Called(ctx, forwarder, after, limit)
+	return args.Get(0).([]models.DeadLetter), args.Error(1)
+}
+
+func (m *MockConfigStorage) PushDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	args := m.Called(ctx, letter)
+	return args.Error(0)
+}
+
+func (m *MockConfigStorage) DeleteDeadLetter(ctx context.Context, forwarder string, id string) error {
+	args := m.Called(ctx, forwarder, id)
+	return args.Error(0)
+}
+
+func (m *MockConfigStorage) PurgeDeadLetters(ctx context.Context, forwarder string) error {
+	args := m.Called(ctx, forwarder)
+	return args.Error(0)
+}
+
+func (m *MockConfigStorage) ListAlerts(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockConfigStorage) ReadAlert(ctx context.Context, name string) (*models.AlertRule, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(*models.AlertRule), args.Error(1)
+}
+
+func (m *MockConfigStorage) WriteAlert(ctx context.Context, name string, rule *models.AlertRule) error {
+	args := m.Called(ctx, name, rule)
+	return args.Error(0)
+}
+
+func (m *MockConfigStorage) DeleteAlert(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockConfigStorage) ListAlertEvents(ctx context.Context, name string) ([]models.AlertEvent, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]models.AlertEvent), args.Error(1)
+}
+
+func (m *MockConfigStorage) RecordAlertEvent(ctx context.Context, event models.AlertEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
 func (m *MockConfigStorage) HasSystemConfig(ctx context.Context) (bool, error) {
 	args := m.Called(ctx)
 	return args.Bool(0), args.Error(1)
@@ -108,7 +158,7 @@ func (m *MockConfigStorage) HasSystemConfig(ctx context.Context) (bool, error) {
 
 func (m *MockConfigStorage) ReadSystemConfig(ctx context.Context) (*models.SystemConfiguration, error) {
 	args := m.Called(ctx)
-	return args.Get(0).(*models.SystemConfiguration), args.Error(0)
+	return args.Get(0).(*models.SystemConfiguration), args.Error(1)
 }
 
 func (m *MockConfigStorage) WriteSystemConfig(ctx context.Context, config *models.SystemConfiguration) error {
@@ -105,7 +105,17 @@ func (m *MockLogStorage) Ingest(ctx context.Context, stream string, logRecord *m
 	return args.Get(0).(kv.Key), args.Error(1)
 }
 
-func (m *MockLogStorage) FetchLogs(ctx context.Context, stream string, from time.Time, to time.Time, filter filtering.Filter, indexing map[string][]string) ([]models.LogRecord, error) {
-	args := m.Called(ctx, stream, from, to, filter, indexing)
-	return args.Get(0).([]models.LogRecord), args.Error(1)
+func (m *MockLogStorage) FetchLogs(ctx context.Context, stream string, from time.Time, to time.Time, filter filtering.Filter, indexing map[string][]string, cursor string, limit int) ([]models.LogRecord, string, error) {
+	args := m.Called(ctx, stream, from, to, filter, indexing, cursor, limit)
+	return args.Get(0).([]models.LogRecord), args.String(1), args.Error(2)
+}
+
+func (m *MockLogStorage) WalkLogs(ctx context.Context, stream string, from time.Time, to time.Time, filter filtering.Filter, indexing map[string][]string, fn func(*models.LogRecord) error) error {
+	args := m.Called(ctx, stream, from, to, filter, indexing, fn)
+	return args.Error(0)
+}
+
+func (m *MockLogStorage) Aggregate(ctx context.Context, stream string, from time.Time, to time.Time, filter filtering.Filter, indexing map[string][]string, query models.AggregationQuery) ([]models.AggregationBucket, error) {
+	args := m.Called(ctx, stream, from, to, filter, indexing, query)
+	return args.Get(0).([]models.AggregationBucket), args.Error(1)
 }
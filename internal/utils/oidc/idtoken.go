@@ -0,0 +1,192 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Identity is the user an ID token was issued for.
+type Identity struct {
+	// Subject is the identifier of the user at the issuer.
+	Subject string
+	// Username is the user's preferred username, falling back to the email
+	// address if it is verified, then to the subject.
+	Username string
+	Email    string
+	// EmailVerified tells whether the issuer verified the email address.
+	EmailVerified bool
+	// Groups lists the values of the provider's groups claim.
+	Groups []string
+}
+
+// jsonWebKey is a public key of a JWK Set (RFC 7517), either RSA or EC.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+
+	N string `json:"n"`
+	E string `json:"e"`
+
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verifyIDToken checks the signature of an ID token against the issuer's JWK
+// Set, as well as its issuer, audience, expiration and nonce (OpenID Connect
+// Core 1.0, section 3.1.3.7).
+func (rp *RelyingParty) verifyIDToken(ctx context.Context, idToken string, nonce string) (*Identity, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := getJSON(ctx, rp.client, rp.metadata.JwksURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWK Set: %w", err)
+	}
+
+	token, err := jwt.Parse(
+		idToken,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+
+			for _, key := range jwks.Keys {
+				if key.Use != "" && key.Use != "sig" {
+					continue
+				}
+
+				if kid != "" && key.Kid != kid {
+					continue
+				}
+
+				return key.publicKey()
+			}
+
+			return nil, fmt.Errorf("no key found for kid %q", kid)
+		},
+		jwt.WithValidMethods([]string{
+			"RS256", "RS384", "RS512",
+			"PS256", "PS384", "PS512",
+			"ES256", "ES384", "ES512",
+		}),
+		jwt.WithIssuer(rp.config.Issuer),
+		jwt.WithAudience(rp.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("invalid ID token claims")
+	}
+
+	if claimNonce, _ := claims["nonce"].(string); claimNonce != nonce {
+		return nil, fmt.Errorf("ID token nonce mismatch")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("ID token has no subject")
+	}
+
+	identity := &Identity{Subject: subject}
+	identity.Email, _ = claims["email"].(string)
+	identity.EmailVerified, _ = claims["email_verified"].(bool)
+
+	identity.Username, _ = claims["preferred_username"].(string)
+	if identity.Username == "" && identity.EmailVerified {
+		identity.Username = identity.Email
+	}
+	if identity.Username == "" {
+		identity.Username = subject
+	}
+
+	groupsClaim := rp.config.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = DEFAULT_GROUPS_CLAIM
+	}
+
+	switch groups := claims[groupsClaim].(type) {
+	case string:
+		identity.Groups = []string{groups}
+
+	case []any:
+		for _, group := range groups {
+			if group, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, group)
+			}
+		}
+	}
+
+	return identity, nil
+}
+
+// publicKey decodes the RSA or EC public key of a JWK.
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve %q", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC coordinate: %w", err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC coordinate: %w", err)
+		}
+
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, fmt.Errorf("invalid EC coordinates")
+		}
+
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,209 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"net/http"
+	"net/url"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// DEFAULT_SCOPES are the scopes requested when the provider does not
+// configure its own.
+var DEFAULT_SCOPES = []string{"openid", "profile", "email"}
+
+// DEFAULT_GROUPS_CLAIM is the ID token claim listing the groups of the user
+// when the provider does not configure its own.
+const DEFAULT_GROUPS_CLAIM = "groups"
+
+// ProviderMetadata is the subset of the OpenID Provider metadata (see
+// OpenID Connect Discovery 1.0, section 3) the relying party relies on.
+type ProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// RelyingParty runs the authorization code flow, with PKCE, against an
+// OpenID Provider.
+type RelyingParty struct {
+	config      *models.AuthProviderOidc
+	redirectURI string
+	metadata    ProviderMetadata
+	client      *http.Client
+}
+
+// NewRelyingParty discovers the endpoints of the provider's issuer. The
+// redirect URI is the callback the provider sends the user back to.
+func NewRelyingParty(
+	ctx context.Context,
+	client *http.Client,
+	config *models.AuthProviderOidc,
+	redirectURI string,
+) (*RelyingParty, error) {
+	discoveryURL := strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration"
+
+	var metadata ProviderMetadata
+	if err := getJSON(ctx, client, discoveryURL, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to discover issuer %q: %w", config.Issuer, err)
+	}
+
+	if metadata.Issuer != config.Issuer {
+		return nil, fmt.Errorf(
+			"issuer mismatch: expected %q, discovered %q",
+			config.Issuer,
+			metadata.Issuer,
+		)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksURI == "" {
+		return nil, fmt.Errorf("issuer %q does not advertise the required endpoints", config.Issuer)
+	}
+
+	return &RelyingParty{
+		config:      config,
+		redirectURI: redirectURI,
+		metadata:    metadata,
+		client:      client,
+	}, nil
+}
+
+// AuthCodeURL returns the URL of the authorization endpoint the user is sent
+// to. The state and nonce must be checked back on the callback, and the
+// verifier (see [NewVerifier]) presented when exchanging the code.
+func (rp *RelyingParty) AuthCodeURL(state string, nonce string, verifier string) string {
+	scopes := rp.config.Scopes
+	if len(scopes) == 0 {
+		scopes = DEFAULT_SCOPES
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {rp.config.ClientID},
+		"redirect_uri":          {rp.redirectURI},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(rp.metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return rp.metadata.AuthorizationEndpoint + sep + params.Encode()
+}
+
+// Exchange redeems an authorization code at the token endpoint, and returns
+// the identity of the user, read from the verified ID token.
+func (rp *RelyingParty) Exchange(
+	ctx context.Context,
+	code string,
+	verifier string,
+	nonce string,
+) (*Identity, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {rp.redirectURI},
+		"client_id":     {rp.config.ClientID},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		rp.metadata.TokenEndpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(
+		url.QueryEscape(rp.config.ClientID),
+		url.QueryEscape(rp.config.ClientSecret),
+	)
+
+	resp, err := rp.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || tokens.Error != "" {
+		return nil, fmt.Errorf(
+			"token request failed with status %d: %s %s",
+			resp.StatusCode,
+			tokens.Error,
+			tokens.ErrorDescription,
+		)
+	}
+
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("token response has no ID token")
+	}
+
+	return rp.verifyIDToken(ctx, tokens.IDToken, nonce)
+}
+
+// NewVerifier returns a random PKCE code verifier (RFC 7636, section 4.1),
+// also suitable as a state or nonce value.
+func NewVerifier() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("failed to generate verifier: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package otlp
 
 import (
 	collectlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	otlplogmodels "go.opentelemetry.io/proto/otlp/logs/v1"
 
 	"link-society.com/flowg/internal/models"
 )
@@ -10,11 +11,35 @@ func convertToLogRecords(message *collectlogs.ExportLogsServiceRequest) []*model
 	var logRecords []*models.LogRecord
 
 	for _, resourceLogs := range message.GetResourceLogs() {
-		for _, scopeLogs := range resourceLogs.GetScopeLogs() {
-			for _, logRecord := range scopeLogs.GetLogRecords() {
-				logRecordModel := newLogRecord(logRecord)
-				logRecords = append(logRecords, logRecordModel)
+		logRecords = append(logRecords, ConvertResourceLogs(resourceLogs)...)
+	}
+
+	return logRecords
+}
+
+// ConvertResourceLogs flattens the log records emitted by a single resource,
+// across all of its instrumentation scopes, into FlowG log records. Each record
+// carries the attributes of its resource and scope, so that it can be
+// correlated with the traces and metrics of the same resource.
+func ConvertResourceLogs(resourceLogs *otlplogmodels.ResourceLogs) []*models.LogRecord {
+	var logRecords []*models.LogRecord
+
+	resourceFields := newResourceFields(resourceLogs.GetResource())
+
+	for _, scopeLogs := range resourceLogs.GetScopeLogs() {
+		scopeFields := newScopeFields(scopeLogs.GetScope())
+
+		for _, logRecord := range scopeLogs.GetLogRecords() {
+			logRecordModel := newLogRecord(logRecord)
+
+			for key, value := range resourceFields {
+				logRecordModel.Fields[key] = value
+			}
+			for key, value := range scopeFields {
+				logRecordModel.Fields[key] = value
 			}
+
+			logRecords = append(logRecords, logRecordModel)
 		}
 	}
 
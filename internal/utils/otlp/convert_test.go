@@ -0,0 +1,119 @@
+package otlp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	otlpcommonmodels "go.opentelemetry.io/proto/otlp/common/v1"
+	otlplogmodels "go.opentelemetry.io/proto/otlp/logs/v1"
+	otlpresourcemodels "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"link-society.com/flowg/internal/utils/otlp"
+)
+
+func stringValue(value string) *otlpcommonmodels.AnyValue {
+	return &otlpcommonmodels.AnyValue{
+		Value: &otlpcommonmodels.AnyValue_StringValue{StringValue: value},
+	}
+}
+
+func TestConvertResourceLogsKeepsResourceAndScope(t *testing.T) {
+	resourceLogs := &otlplogmodels.ResourceLogs{
+		Resource: &otlpresourcemodels.Resource{
+			Attributes: []*otlpcommonmodels.KeyValue{
+				{Key: "service.name", Value: stringValue("checkout")},
+				{
+					Key: "process.command_args",
+					Value: &otlpcommonmodels.AnyValue{
+						Value: &otlpcommonmodels.AnyValue_ArrayValue{
+							ArrayValue: &otlpcommonmodels.ArrayValue{
+								Values: []*otlpcommonmodels.AnyValue{
+									stringValue("checkout"),
+									stringValue("--verbose"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		ScopeLogs: []*otlplogmodels.ScopeLogs{
+			{
+				Scope: &otlpcommonmodels.InstrumentationScope{
+					Name:    "github.com/acme/logger",
+					Version: "1.2.3",
+					Attributes: []*otlpcommonmodels.KeyValue{
+						{Key: "component", Value: stringValue("http")},
+					},
+				},
+				LogRecords: []*otlplogmodels.LogRecord{
+					{Body: stringValue("hello")},
+				},
+			},
+		},
+	}
+
+	records := otlp.ConvertResourceLogs(resourceLogs)
+	require.Len(t, records, 1)
+
+	fields := records[0].Fields
+	assert.Equal(t, "hello", fields["body"])
+	assert.Equal(t, "checkout", fields["resource.service.name"])
+	assert.Equal(t, "[checkout --verbose]", fields["resource.process.command_args"])
+	assert.Equal(t, "github.com/acme/logger", fields["scope.name"])
+	assert.Equal(t, "1.2.3", fields["scope.version"])
+	assert.Equal(t, "http", fields["scope.attr.component"])
+}
+
+func TestConvertResourceLogsTimestamp(t *testing.T) {
+	eventTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	observedTime := eventTime.Add(time.Second)
+
+	testCases := []struct {
+		name     string
+		record   *otlplogmodels.LogRecord
+		expected time.Time
+	}{
+		{
+			name: "event time",
+			record: &otlplogmodels.LogRecord{
+				TimeUnixNano:         uint64(eventTime.UnixNano()),
+				ObservedTimeUnixNano: uint64(observedTime.UnixNano()),
+			},
+			expected: eventTime,
+		},
+		{
+			name: "observed time",
+			record: &otlplogmodels.LogRecord{
+				ObservedTimeUnixNano: uint64(observedTime.UnixNano()),
+			},
+			expected: observedTime,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			records := otlp.ConvertResourceLogs(&otlplogmodels.ResourceLogs{
+				ScopeLogs: []*otlplogmodels.ScopeLogs{
+					{LogRecords: []*otlplogmodels.LogRecord{tc.record}},
+				},
+			})
+			require.Len(t, records, 1)
+			assert.True(t, tc.expected.Equal(records[0].Timestamp))
+		})
+	}
+
+	t.Run("fallback to now", func(t *testing.T) {
+		before := time.Now()
+		records := otlp.ConvertResourceLogs(&otlplogmodels.ResourceLogs{
+			ScopeLogs: []*otlplogmodels.ScopeLogs{
+				{LogRecords: []*otlplogmodels.LogRecord{{}}},
+			},
+		})
+		require.Len(t, records, 1)
+		assert.False(t, records[0].Timestamp.Before(before))
+	})
+}
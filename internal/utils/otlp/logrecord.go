@@ -7,6 +7,7 @@ import (
 
 	otlpcommonmodels "go.opentelemetry.io/proto/otlp/common/v1"
 	otlplogmodels "go.opentelemetry.io/proto/otlp/logs/v1"
+	otlpresourcemodels "go.opentelemetry.io/proto/otlp/resource/v1"
 
 	"link-society.com/flowg/internal/models"
 )
@@ -33,14 +34,68 @@ func newLogRecord(logRecord *otlplogmodels.LogRecord) *models.LogRecord {
 	}
 
 	return &models.LogRecord{
-		Timestamp: time.Now(),
+		Timestamp: logRecordTimestamp(logRecord),
 		Fields:    fields,
 	}
 }
 
+// logRecordTimestamp returns the time the event occurred, falling back to the
+// time it was observed by the collector, then to the current time when the
+// exporter set neither.
+func logRecordTimestamp(logRecord *otlplogmodels.LogRecord) time.Time {
+	switch {
+	case logRecord.TimeUnixNano != 0:
+		return time.Unix(0, int64(logRecord.TimeUnixNano))
+
+	case logRecord.ObservedTimeUnixNano != 0:
+		return time.Unix(0, int64(logRecord.ObservedTimeUnixNano))
+
+	default:
+		return time.Now()
+	}
+}
+
+// newResourceFields flattens the attributes of the resource emitting a batch of
+// log records, prefixing each with "resource.".
+func newResourceFields(resource *otlpresourcemodels.Resource) map[string]string {
+	fields := map[string]string{}
+
+	for _, attribute := range resource.GetAttributes() {
+		fieldName := fmt.Sprintf("resource.%s", attribute.Key)
+		fields[fieldName] = otlpValueParser(attribute.Value)
+	}
+
+	return fields
+}
+
+// newScopeFields flattens the instrumentation scope of a batch of log records:
+// its name and version as "scope.name" and "scope.version", and each of its
+// attributes prefixed with "scope.attr.".
+func newScopeFields(scope *otlpcommonmodels.InstrumentationScope) map[string]string {
+	fields := map[string]string{}
+
+	if scope.GetName() != "" {
+		fields["scope.name"] = scope.GetName()
+	}
+	if scope.GetVersion() != "" {
+		fields["scope.version"] = scope.GetVersion()
+	}
+
+	for _, attribute := range scope.GetAttributes() {
+		fieldName := fmt.Sprintf("scope.attr.%s", attribute.Key)
+		fields[fieldName] = otlpValueParser(attribute.Value)
+	}
+
+	return fields
+}
+
 // otlpValueParser renders an arbitrary OTLP AnyValue as a string so it can live
-// in a LogRecord's flat field map.
+// in a LogRecord's flat field map. An unset value renders as an empty string.
 func otlpValueParser(v *otlpcommonmodels.AnyValue) string {
+	if v == nil {
+		return ""
+	}
+
 	switch v.Value.(type) {
 	case *otlpcommonmodels.AnyValue_StringValue:
 		return v.GetStringValue()
@@ -51,7 +106,7 @@ func otlpValueParser(v *otlpcommonmodels.AnyValue) string {
 	case *otlpcommonmodels.AnyValue_DoubleValue:
 		return fmt.Sprintf("%f", v.GetDoubleValue())
 	case *otlpcommonmodels.AnyValue_ArrayValue:
-		items := make([]string, 0, len(v.GetArrayValue().Values))
+		items := make([]string, len(v.GetArrayValue().Values))
 
 		for i, item := range v.GetArrayValue().Values {
 			items[i] = otlpValueParser(item)
@@ -60,7 +115,7 @@ func otlpValueParser(v *otlpcommonmodels.AnyValue) string {
 		return fmt.Sprintf("[%s]", strings.Join(items, " "))
 
 	case *otlpcommonmodels.AnyValue_KvlistValue:
-		items := make([]string, 0, len(v.GetKvlistValue().Values))
+		items := make([]string, len(v.GetKvlistValue().Values))
 
 		for i, item := range v.GetKvlistValue().Values {
 			items[i] = fmt.Sprintf("%s:%s", item.Key, otlpValueParser(item.Value))
@@ -0,0 +1,122 @@
+package timestamp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"link-society.com/flowg/internal/models"
+)
+
+// Well-known timestamp formats accepted by [Parse]. Any other format is used as
+// a Go time layout (see [time.Layout]).
+const (
+	// FORMAT_AUTO detects RFC 3339 dates and Unix epochs, guessing the unit of
+	// the latter from its magnitude.
+	FORMAT_AUTO = "auto"
+	// FORMAT_RFC3339 parses RFC 3339 dates, with optional fractional seconds.
+	FORMAT_RFC3339 = "rfc3339"
+	// FORMAT_UNIX parses Unix epochs in seconds, with optional fractional part.
+	FORMAT_UNIX = "unix"
+	// FORMAT_UNIX_MS parses Unix epochs in milliseconds.
+	FORMAT_UNIX_MS = "unix_ms"
+	// FORMAT_UNIX_NS parses Unix epochs in nanoseconds.
+	FORMAT_UNIX_NS = "unix_ns"
+)
+
+// Parse decodes a client-supplied timestamp according to format. An empty
+// format is equivalent to [FORMAT_AUTO].
+func Parse(value string, format string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	var (
+		ts  time.Time
+		err error
+	)
+
+	switch format {
+	case "", FORMAT_AUTO:
+		ts, err = time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			ts, err = parseEpoch(value, 0)
+		}
+
+	case FORMAT_RFC3339:
+		ts, err = time.Parse(time.RFC3339Nano, value)
+
+	case FORMAT_UNIX:
+		ts, err = parseEpoch(value, time.Second)
+
+	case FORMAT_UNIX_MS:
+		ts, err = parseEpoch(value, time.Millisecond)
+
+	case FORMAT_UNIX_NS:
+		ts, err = parseEpoch(value, time.Nanosecond)
+
+	default:
+		ts, err = time.Parse(format, value)
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", value, err)
+	}
+
+	return ts, nil
+}
+
+// ApplyPolicy returns ts if it is within the bounds of policy relative to now,
+// or now otherwise.
+func ApplyPolicy(policy models.TimestampPolicy, ts time.Time, now time.Time) time.Time {
+	if policy.MaxPast > 0 && ts.Before(now.Add(-time.Duration(policy.MaxPast)*time.Second)) {
+		return now
+	}
+
+	if policy.MaxFuture > 0 && ts.After(now.Add(time.Duration(policy.MaxFuture)*time.Second)) {
+		return now
+	}
+
+	return ts
+}
+
+// parseEpoch decodes a Unix epoch expressed in unit. A zero unit is guessed
+// from the magnitude of the epoch: values that would be past year 5000 in a
+// given unit are assumed to be in the next finer one.
+func parseEpoch(value string, unit time.Duration) (time.Time, error) {
+	epoch, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("not a number")
+	}
+
+	if math.IsNaN(epoch) || math.IsInf(epoch, 0) {
+		return time.Time{}, fmt.Errorf("not a finite number")
+	}
+
+	if unit == 0 {
+		switch magnitude := math.Abs(epoch); {
+		case magnitude < 1e11:
+			unit = time.Second
+		case magnitude < 1e14:
+			unit = time.Millisecond
+		case magnitude < 1e17:
+			unit = time.Microsecond
+		default:
+			unit = time.Nanosecond
+		}
+	}
+
+	// Integers are parsed exactly, as a float64 cannot hold every nanosecond
+	// epoch.
+	if integer, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if unit == time.Nanosecond {
+			return time.Unix(0, integer), nil
+		}
+
+		perSecond := int64(time.Second / unit)
+		return time.Unix(integer/perSecond, (integer%perSecond)*int64(unit)), nil
+	}
+
+	seconds, fraction := math.Modf(epoch * float64(unit) / float64(time.Second))
+	return time.Unix(int64(seconds), int64(fraction*float64(time.Second))), nil
+}
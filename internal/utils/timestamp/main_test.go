@@ -0,0 +1,68 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"link-society.com/flowg/internal/models"
+	"link-society.com/flowg/internal/utils/timestamp"
+)
+
+func TestParse(t *testing.T) {
+	expected := time.Date(2024, 5, 1, 12, 30, 45, 123000000, time.UTC)
+
+	testCases := []struct {
+		name   string
+		value  string
+		format string
+	}{
+		{name: "auto rfc3339", value: "2024-05-01T12:30:45.123Z", format: ""},
+		{name: "auto rfc3339 with offset", value: "2024-05-01T14:30:45.123+02:00", format: timestamp.FORMAT_AUTO},
+		{name: "auto seconds", value: "1714566645.123", format: timestamp.FORMAT_AUTO},
+		{name: "auto milliseconds", value: "1714566645123", format: timestamp.FORMAT_AUTO},
+		{name: "auto microseconds", value: "1714566645123000", format: timestamp.FORMAT_AUTO},
+		{name: "auto nanoseconds", value: "1714566645123000000", format: timestamp.FORMAT_AUTO},
+		{name: "rfc3339", value: "2024-05-01T12:30:45.123Z", format: timestamp.FORMAT_RFC3339},
+		{name: "unix", value: "1714566645.123", format: timestamp.FORMAT_UNIX},
+		{name: "unix_ms", value: "1714566645123", format: timestamp.FORMAT_UNIX_MS},
+		{name: "unix_ns", value: "1714566645123000000", format: timestamp.FORMAT_UNIX_NS},
+		{name: "layout", value: "01/May/2024:12:30:45.123 +0000", format: "02/Jan/2006:15:04:05.000 -0700"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, err := timestamp.Parse(tc.value, tc.format)
+			require.NoError(t, err)
+			assert.WithinDuration(t, expected, ts, time.Microsecond)
+		})
+	}
+}
+
+func TestParseRejectsInvalidValues(t *testing.T) {
+	for _, format := range []string{
+		timestamp.FORMAT_AUTO,
+		timestamp.FORMAT_RFC3339,
+		timestamp.FORMAT_UNIX,
+		timestamp.FORMAT_UNIX_MS,
+		"2006-01-02",
+	} {
+		_, err := timestamp.Parse("yesterday", format)
+		assert.Error(t, err, format)
+	}
+}
+
+func TestApplyPolicy(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	policy := models.TimestampPolicy{MaxPast: 3600, MaxFuture: 60}
+
+	assert.Equal(t, now.Add(-time.Minute), timestamp.ApplyPolicy(policy, now.Add(-time.Minute), now))
+	assert.Equal(t, now.Add(30*time.Second), timestamp.ApplyPolicy(policy, now.Add(30*time.Second), now))
+	assert.Equal(t, now, timestamp.ApplyPolicy(policy, now.Add(-2*time.Hour), now))
+	assert.Equal(t, now, timestamp.ApplyPolicy(policy, now.Add(time.Hour), now))
+
+	unbounded := models.TimestampPolicy{}
+	assert.Equal(t, now.Add(-24*time.Hour), timestamp.ApplyPolicy(unbounded, now.Add(-24*time.Hour), now))
+}
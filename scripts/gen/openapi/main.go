@@ -15,6 +15,7 @@ import (
 
 	storage "link-society.com/flowg/internal/storage/interfaces"
 
+	"link-society.com/flowg/internal/engines/alerts"
 	"link-society.com/flowg/internal/engines/lognotify"
 	"link-society.com/flowg/internal/engines/pipelines"
 )
@@ -32,6 +33,7 @@ func main() {
 		fx.Provide(func() storage.LogStorage { return nil }),
 		fx.Provide(func() lognotify.LogNotifier { return nil }),
 		fx.Provide(func() pipelines.Runner { return nil }),
+		fx.Provide(func() alerts.Manager { return nil }),
 
 		api.Module("openapi-handler"),
 
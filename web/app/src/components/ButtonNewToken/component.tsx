@@ -1,16 +1,15 @@
 import { useTranslation } from 'react-i18next'
 
 import Button from '@mui/material/Button'
-import CircularProgress from '@mui/material/CircularProgress'
 
 import AddIcon from '@mui/icons-material/Add'
 
-import * as tokenApi from '@/lib/api/operations/token'
-
 import { useApiOperation } from '@/lib/hooks/api'
 import { useDialogs } from '@/lib/hooks/dialogs'
 import { useNotify } from '@/lib/hooks/notify'
 
+import TokenInfoModel from '@/lib/models/TokenInfoModel'
+
 import DialogNewToken from '@/components/DialogNewToken/component'
 
 import { ButtonNewTokenProps } from './types'
@@ -20,11 +19,12 @@ const ButtonNewToken = ({ onTokenCreated }: ButtonNewTokenProps) => {
   const dialogs = useDialogs()
   const notify = useNotify()
 
-  const [handleClick, loading] = useApiOperation(async () => {
-    const resp = await tokenApi.createToken()
-    await dialogs.open(DialogNewToken, resp)
-    onTokenCreated(resp.token_uuid)
-    notify.success(t('components.buttonNewToken.notifications.created'))
+  const [handleClick] = useApiOperation(async () => {
+    const token = (await dialogs.open(DialogNewToken)) as TokenInfoModel | null
+    if (token !== null) {
+      onTokenCreated(token)
+      notify.success(t('components.buttonNewToken.notifications.created'))
+    }
   }, [onTokenCreated])
 
   return (
@@ -33,10 +33,9 @@ const ButtonNewToken = ({ onTokenCreated }: ButtonNewTokenProps) => {
       variant="contained"
       size="small"
       color="secondary"
-      disabled={loading}
       onClick={() => handleClick()}
     >
-      {loading ? <CircularProgress color="inherit" size={24} /> : <AddIcon />}
+      <AddIcon />
     </Button>
   )
 }
@@ -1,3 +1,5 @@
+import TokenInfoModel from '@/lib/models/TokenInfoModel'
+
 export type ButtonNewTokenProps = Readonly<{
-  onTokenCreated: (uuid: string) => void
+  onTokenCreated: (token: TokenInfoModel) => void
 }>
@@ -35,17 +35,25 @@ const DialogNewRole = ({
   const dialogs = useDialogs()
   const [name, setName] = useState('')
   const [scopes, setScopes] = useState<string[]>([])
-  const dirty = name.trim() !== '' || scopes.length > 0
+  const [resourceScopes, setResourceScopes] = useState('')
+  const dirty =
+    name.trim() !== '' || scopes.length > 0 || resourceScopes.trim() !== ''
 
   const [onSubmit, loading] = useApiOperation(async () => {
     const role = {
       name,
       scopes,
+      // One permission restricted to some resources per line, e.g.
+      // "read_streams:payments-*".
+      resource_scopes: resourceScopes
+        .split('\n')
+        .map((line) => line.trim())
+        .filter((line) => line !== ''),
     }
 
     await aclApi.saveRole(role)
     onClose(role)
-  }, [name, scopes, onClose])
+  }, [name, scopes, resourceScopes, onClose])
 
   const handleClose = async () => {
     if (dirty) {
@@ -112,6 +120,18 @@ const DialogNewRole = ({
               onChoiceUpdate={(choices) => setScopes([...choices])}
             />
           </FieldStack>
+
+          <FormTextField
+            id="input:admin.roles.modal.resource_scopes"
+            label={t('components.dialogNewRole.resourceScopesLabel')}
+            helperText={t('components.dialogNewRole.resourceScopesHint')}
+            value={resourceScopes}
+            onChange={(e) => setResourceScopes(e.target.value)}
+            type="text"
+            variant="outlined"
+            multiline
+            minRows={2}
+          />
         </FormStack>
       </DialogContent>
       <DialogActions>
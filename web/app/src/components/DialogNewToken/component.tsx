@@ -1,89 +1,230 @@
+import React, { useState } from 'react'
 import { useTranslation } from 'react-i18next'
 
 import Button from '@mui/material/Button'
+import Chip from '@mui/material/Chip'
+import CircularProgress from '@mui/material/CircularProgress'
 import Dialog from '@mui/material/Dialog'
 import DialogActions from '@mui/material/DialogActions'
 import DialogContent from '@mui/material/DialogContent'
 import DialogTitle from '@mui/material/DialogTitle'
+import MenuItem from '@mui/material/MenuItem'
 import TextField from '@mui/material/TextField'
+import Tooltip from '@mui/material/Tooltip'
 import Typography from '@mui/material/Typography'
 
+import CancelIcon from '@mui/icons-material/Cancel'
 import KeyIcon from '@mui/icons-material/Key'
 import LabelIcon from '@mui/icons-material/Label'
+import SaveIcon from '@mui/icons-material/Save'
+
+import * as tokenApi from '@/lib/api/operations/token'
+
+import { useApiOperation } from '@/lib/hooks/api'
 
 import { DialogProps } from '@/lib/models/Dialog'
+import TokenInfoModel from '@/lib/models/TokenInfoModel'
+import TokenModel from '@/lib/models/TokenModel'
+import { ScopeLabels, Scopes } from '@/lib/models/Scopes'
+
+import InputTransferList from '@/components/InputTransferList/component'
+
+import {
+  FieldLabel,
+  FieldRow,
+  FieldStack,
+  FormStack,
+  FormTextField,
+} from './styles'
 
-import { FieldRow } from './styles'
+// Lifetimes offered for new tokens, in days (0 for a token that never
+// expires).
+const EXPIRY_DAYS = [0, 7, 30, 90, 365]
 
 const DialogNewToken = ({
   open,
-  payload,
   onClose,
-}: DialogProps<
-  {
-    token: string
-    token_uuid: string
-  },
-  void
->) => {
+}: DialogProps<void, TokenInfoModel | null>) => {
   const { t } = useTranslation()
+  const [name, setName] = useState('')
+  const [scopes, setScopes] = useState<string[]>([])
+  const [expiryDays, setExpiryDays] = useState(0)
+  const [created, setCreated] = useState<TokenModel | null>(null)
+
+  const [onSubmit, loading] = useApiOperation(async () => {
+    const expiresAt =
+      expiryDays > 0
+        ? new Date(Date.now() + expiryDays * 24 * 60 * 60 * 1000)
+        : null
+
+    setCreated(await tokenApi.createToken(name, scopes, expiresAt))
+  }, [name, scopes, expiryDays])
+
+  // Once created, the token is shown only once: closing via backdrop click or
+  // Escape is too easy to trigger by accident, so require the explicit "Done"
+  // click. MUI only invokes Dialog's onClose for those two reasons, so a
+  // no-op here blocks both while leaving the "Done" button free to call
+  // onClose.
+  const handleClose = () => {
+    if (created === null && !loading) {
+      onClose(null)
+    }
+  }
+
+  if (created !== null) {
+    return (
+      <Dialog maxWidth="sm" fullWidth open={open} onClose={handleClose}>
+        <DialogTitle>{t('components.dialogNewToken.title')}</DialogTitle>
+        <DialogContent>
+          <FieldRow>
+            <LabelIcon />
+            <TextField
+              id="input:account.tokens.modal.token_uuid"
+              label={t('components.dialogNewToken.tokenUuidLabel')}
+              type="text"
+              value={created.token_uuid}
+              variant="standard"
+              fullWidth
+              slotProps={{
+                input: {
+                  readOnly: true,
+                },
+              }}
+            />
+          </FieldRow>
 
-  // This token is shown only once: closing via backdrop click or Escape is
-  // too easy to trigger by accident, so require the explicit "Done" click.
-  // MUI only invokes Dialog's onClose for those two reasons, so a no-op
-  // here blocks both while leaving the "Done" button free to call onClose.
-  const handleClose = () => {}
+          <FieldRow>
+            <KeyIcon />
+            <TextField
+              id="input:account.tokens.modal.token"
+              label={t('components.dialogNewToken.tokenLabel')}
+              value={created.token}
+              type="text"
+              variant="standard"
+              fullWidth
+              slotProps={{
+                input: {
+                  readOnly: true,
+                },
+              }}
+            />
+          </FieldRow>
+
+          <Typography variant="text">
+            {t('components.dialogNewToken.hint')}
+          </Typography>
+        </DialogContent>
+        <DialogActions>
+          <Button
+            id="btn:account.tokens.modal.done"
+            variant="contained"
+            color="secondary"
+            onClick={() => onClose(created.token_info)}
+          >
+            {t('components.dialogNewToken.done')}
+          </Button>
+        </DialogActions>
+      </Dialog>
+    )
+  }
 
   return (
-    <Dialog maxWidth="sm" fullWidth open={open} onClose={handleClose}>
-      <DialogTitle>{t('components.dialogNewToken.title')}</DialogTitle>
+    <Dialog
+      maxWidth="sm"
+      fullWidth
+      open={open}
+      onClose={handleClose}
+      slotProps={{
+        paper: {
+          component: 'form',
+          onSubmit: (e: React.SubmitEvent<HTMLFormElement>) => {
+            e.preventDefault()
+            onSubmit()
+          },
+        },
+      }}
+    >
+      <DialogTitle>{t('components.dialogNewToken.createTitle')}</DialogTitle>
       <DialogContent>
-        <FieldRow>
-          <LabelIcon />
-          <TextField
-            id="input:account.tokens.modal.token_uuid"
-            label={t('components.dialogNewToken.tokenUuidLabel')}
+        <FormStack>
+          <FormTextField
+            id="input:account.tokens.modal.name"
+            label={t('components.dialogNewToken.nameLabel')}
+            value={name}
+            onChange={(e) => setName(e.target.value)}
             type="text"
-            value={payload.token_uuid}
-            variant="standard"
-            fullWidth
-            slotProps={{
-              input: {
-                readOnly: true,
-              },
-            }}
+            variant="outlined"
+            required
           />
-        </FieldRow>
 
-        <FieldRow>
-          <KeyIcon />
           <TextField
-            id="input:account.tokens.modal.token"
-            label={t('components.dialogNewToken.tokenLabel')}
-            value={payload.token}
-            type="text"
-            variant="standard"
-            fullWidth
-            slotProps={{
-              input: {
-                readOnly: true,
-              },
-            }}
-          />
-        </FieldRow>
+            id="input:account.tokens.modal.expiry"
+            label={t('components.dialogNewToken.expiryLabel')}
+            value={expiryDays}
+            onChange={(e) => setExpiryDays(Number(e.target.value))}
+            variant="outlined"
+            select
+          >
+            {EXPIRY_DAYS.map((days) => (
+              <MenuItem key={days} value={days}>
+                {days > 0
+                  ? t('components.dialogNewToken.expiryDays', { days })
+                  : t('components.dialogNewToken.expiryNever')}
+              </MenuItem>
+            ))}
+          </TextField>
 
-        <Typography variant="text">
-          {t('components.dialogNewToken.hint')}
-        </Typography>
+          <FieldStack id="field:account.tokens.modal.scopes">
+            <FieldLabel variant="text">
+              {t('components.dialogNewToken.scopesLabel')}
+            </FieldLabel>
+            <Typography variant="text">
+              {t('components.dialogNewToken.scopesHint')}
+            </Typography>
+            <InputTransferList<string>
+              choices={Scopes}
+              getItemId={(v) => v}
+              renderItem={(v) => (
+                <Tooltip
+                  title={ScopeLabels[v as keyof typeof ScopeLabels] ?? '#ERR#'}
+                  placement="right-start"
+                >
+                  <Chip
+                    label={
+                      ScopeLabels[v as keyof typeof ScopeLabels] ?? '#ERR#'
+                    }
+                    size="small"
+                  />
+                </Tooltip>
+              )}
+              onChoiceUpdate={(choices) => setScopes([...choices])}
+            />
+          </FieldStack>
+        </FormStack>
       </DialogContent>
       <DialogActions>
         <Button
-          id="btn:account.tokens.modal.done"
+          id="btn:account.tokens.modal.cancel"
+          variant="contained"
+          startIcon={<CancelIcon />}
+          onClick={() => onClose(null)}
+          disabled={loading}
+        >
+          {t('common.actions.cancel')}
+        </Button>
+        <Button
+          id="btn:account.tokens.modal.submit"
           variant="contained"
           color="secondary"
-          onClick={() => onClose()}
+          startIcon={!loading && <SaveIcon />}
+          disabled={loading || name.trim() === ''}
+          type="submit"
         >
-          {t('components.dialogNewToken.done')}
+          {loading ? (
+            <CircularProgress color="inherit" size={24} />
+          ) : (
+            <>{t('common.actions.save')}</>
+          )}
         </Button>
       </DialogActions>
     </Dialog>
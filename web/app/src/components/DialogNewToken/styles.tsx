@@ -1,4 +1,6 @@
 import Box from '@mui/material/Box'
+import TextField from '@mui/material/TextField'
+import Typography from '@mui/material/Typography'
 import { styled } from '@mui/material/styles'
 
 export const FieldRow = styled(Box)(({ theme }) => ({
@@ -12,3 +14,25 @@ export const FieldRow = styled(Box)(({ theme }) => ({
     marginBottom: theme.spacing(0.5),
   },
 }))
+
+export const FormStack = styled(Box)(({ theme }) => ({
+  display: 'flex',
+  flexDirection: 'column',
+  alignItems: 'stretch',
+  gap: theme.spacing(1.5),
+}))
+
+export const FieldStack = styled(Box)(({ theme }) => ({
+  display: 'flex',
+  flexDirection: 'column',
+  alignItems: 'stretch',
+  gap: theme.spacing(1),
+}))
+
+export const FieldLabel = styled(Typography)({
+  fontWeight: 600,
+})
+
+export const FormTextField = styled(TextField)(({ theme }) => ({
+  marginTop: theme.spacing(2),
+}))
@@ -18,6 +18,14 @@ const NodeTraceTabPanel = ({ trace, index, value }: NodeTraceTabPanelProps) => {
           </TraceCode>
         </TraceSection>
       )}
+      {trace.branch && (
+        <TraceSection>
+          <TraceLabel>
+            {t('components.nodeTraceTabPanel.branchLabel')}
+          </TraceLabel>
+          <TraceCode variant="outlined">{trace.branch}</TraceCode>
+        </TraceSection>
+      )}
       <TraceRow>
         {trace.input && (
           <TraceSection>
@@ -102,6 +102,27 @@ const PermissionDisplay = () => {
           />
         </FormGroup>
 
+        <FormGroup>
+          <FormControlLabel
+            label={
+              <PermissionLabel>
+                {t('components.permissionDisplay.viewAlerts')}
+              </PermissionLabel>
+            }
+            disabled
+            control={<Checkbox checked={permissions.can_view_alerts} />}
+          />
+          <FormControlLabel
+            label={
+              <PermissionLabel>
+                {t('components.permissionDisplay.editAlerts')}
+              </PermissionLabel>
+            }
+            disabled
+            control={<Checkbox checked={permissions.can_edit_alerts} />}
+          />
+        </FormGroup>
+
         <FormGroup>
           <FormControlLabel
             label={
@@ -38,6 +38,7 @@ import PipelineModel from '@/lib/models/PipelineModel'
 import { PipelineTrace } from '@/lib/models/PipelineTrace.ts'
 
 import PipelineEditorHooksProvider from '@/components/PipelineEditorHooksProvider/component'
+import PipelineNodeCase from '@/components/PipelineNodeCase/component'
 import PipelineNodeForwarder from '@/components/PipelineNodeForwarder/component'
 import PipelineNodeMetric from '@/components/PipelineNodeMetric/component'
 import PipelineNodePipeline from '@/components/PipelineNodePipeline/component'
@@ -91,6 +92,7 @@ export const PipelineEditorFlow: React.FC<PipelineEditorFlowProps> = ({
       source: PipelineNodeSource,
       transform: PipelineNodeTransformer,
       switch: PipelineNodeSwitch,
+      case: PipelineNodeCase,
       pipeline: PipelineNodePipeline,
       forwarder: PipelineNodeForwarder,
       router: PipelineNodeRouter,
@@ -191,6 +193,7 @@ export const PipelineEditorFlow: React.FC<PipelineEditorFlowProps> = ({
         forwarder: 'forwarder',
         pipeline: 'pipeline',
         switch: 'switch',
+        case: 'case',
         metric: 'metric',
       }
       const itemType = event.dataTransfer.getData(
@@ -232,6 +235,10 @@ export const PipelineEditorFlow: React.FC<PipelineEditorFlowProps> = ({
           case 'pipeline':
             newNode.data = { pipeline: event.dataTransfer.getData('item') }
             break
+
+          case 'case':
+            newNode.data = { conditions: '[""]' }
+            break
         }
 
         return [...nds, newNode]
@@ -287,6 +294,17 @@ export const PipelineEditorFlow: React.FC<PipelineEditorFlowProps> = ({
                   }}
                 />
 
+                <SwitchNodeChip
+                  icon={<DeviceHubIcon />}
+                  label={t('components.pipelineEditorFlow.caseNodeLabel')}
+                  variant="outlined"
+                  draggable
+                  onDragStart={(evt) => {
+                    evt.dataTransfer.setData('item-type', 'case')
+                    evt.dataTransfer.effectAllowed = 'move'
+                  }}
+                />
+
                 <MetricNodeChip
                   icon={<BarChartIcon />}
                   label={t('components.pipelineEditorFlow.metricNodeLabel')}
@@ -0,0 +1,176 @@
+import React, { useEffect, useState } from 'react'
+import { useTranslation } from 'react-i18next'
+
+import Button from '@mui/material/Button'
+import IconButton from '@mui/material/IconButton'
+import TextField from '@mui/material/TextField'
+import Typography from '@mui/material/Typography'
+
+import AddIcon from '@mui/icons-material/Add'
+import CloseIcon from '@mui/icons-material/Close'
+import DeviceHubIcon from '@mui/icons-material/DeviceHub'
+
+import {
+  Handle,
+  NodeProps,
+  Position,
+  useReactFlow,
+  useUpdateNodeInternals,
+} from '@xyflow/react'
+
+import { usePipelineEditorHooks } from '@/lib/hooks/pipeline-editor'
+
+import PipelineDeleteNodeButton from '@/components/PipelineDeleteNodeButton/component'
+import PipelineTraceNodeButton from '@/components/PipelineTraceNodeButton/component'
+import PipelineTraceNodeIndicator from '@/components/PipelineTraceNodeIndicator/component'
+
+import {
+  BranchRow,
+  NodeBody,
+  NodeIcon,
+  NodeRoot,
+  ToolbarRow,
+  handleStyle,
+} from './styles'
+import { PipelineNodeCaseData } from './types'
+
+const parseConditions = (conditions: string | undefined): string[] => {
+  try {
+    const parsed = JSON.parse(conditions ?? '[]')
+    return Array.isArray(parsed) ? parsed : []
+  } catch {
+    return []
+  }
+}
+
+const PipelineNodeCase = ({
+  id,
+  data,
+  selected,
+}: NodeProps<PipelineNodeCaseData>) => {
+  const { t } = useTranslation()
+  const { setNodes } = usePipelineEditorHooks()
+  const { setEdges } = useReactFlow()
+  const updateNodeInternals = useUpdateNodeInternals()
+
+  const [conditions, setConditions] = useState(() =>
+    parseConditions(data.conditions)
+  )
+
+  useEffect(() => {
+    setNodes((prevNodes) => {
+      const newNodes = [...prevNodes]
+
+      for (const node of newNodes) {
+        if (node.id === id) {
+          node.data = { conditions: JSON.stringify(conditions) }
+        }
+      }
+
+      return newNodes
+    })
+    updateNodeInternals(id)
+  }, [id, conditions])
+
+  const onChange = (index: number, value: string) => {
+    setConditions((prev) => prev.map((c, i) => (i === index ? value : c)))
+  }
+
+  const onAdd = () => {
+    setConditions((prev) => [...prev, ''])
+  }
+
+  // Outputs are named after the position of their condition, so only the last
+  // one can be removed without rewiring the others.
+  const onRemoveLast = () => {
+    const handle = String(conditions.length - 1)
+    setEdges((prev) =>
+      prev.filter((edge) => edge.source !== id || edge.sourceHandle !== handle)
+    )
+    setConditions((prev) => prev.slice(0, -1))
+  }
+
+  return (
+    <>
+      {selected && (
+        <ToolbarRow>
+          <PipelineDeleteNodeButton nodeId={id} />
+          {data.traces && <PipelineTraceNodeButton traces={data.traces} />}
+        </ToolbarRow>
+      )}
+
+      <Handle type="target" position={Position.Left} style={handleStyle} />
+      <NodeRoot>
+        <NodeIcon>
+          <DeviceHubIcon />
+        </NodeIcon>
+        <NodeBody className="nodrag">
+          {conditions.map((condition, index) => (
+            <BranchRow key={index}>
+              <TextField
+                label={t('components.pipelineNodeCase.conditionLabel', {
+                  index,
+                })}
+                type="text"
+                size="small"
+                value={condition}
+                onChange={(evt: React.ChangeEvent<HTMLInputElement>) =>
+                  onChange(index, evt.target.value)
+                }
+                slotProps={{
+                  input: {
+                    sx: { fontFamily: 'monospace' },
+                  },
+                }}
+                variant="outlined"
+              />
+              {index === conditions.length - 1 && (
+                <IconButton
+                  size="small"
+                  onClick={onRemoveLast}
+                  title={t('components.pipelineNodeCase.removeCondition')}
+                >
+                  <CloseIcon fontSize="small" />
+                </IconButton>
+              )}
+              <Handle
+                type="source"
+                id={String(index)}
+                position={Position.Right}
+                style={handleStyle}
+              />
+            </BranchRow>
+          ))}
+
+          <Button size="small" startIcon={<AddIcon />} onClick={onAdd}>
+            {t('components.pipelineNodeCase.addCondition')}
+          </Button>
+
+          <BranchRow>
+            <Typography variant="text">
+              {t('components.pipelineNodeCase.defaultLabel')}
+            </Typography>
+            <Handle
+              type="source"
+              id="default"
+              position={Position.Right}
+              style={handleStyle}
+            />
+          </BranchRow>
+        </NodeBody>
+      </NodeRoot>
+
+      <PipelineTraceNodeIndicator
+        status={
+          data.traces
+            ? data.traces.some((trace) => trace.error)
+              ? 'error'
+              : 'success'
+            : null
+        }
+      />
+    </>
+  )
+}
+
+export default PipelineNodeCase
@@ -0,0 +1,56 @@
+import Box from '@mui/material/Box'
+import { styled } from '@mui/material/styles'
+
+import { NodeToolbar } from '@xyflow/react'
+
+export const ToolbarRow = styled(NodeToolbar)(({ theme }) => ({
+  display: 'flex',
+  flexDirection: 'row',
+  alignItems: 'center',
+  gap: theme.spacing(1),
+}))
+
+export const NodeRoot = styled(Box)(({ theme }) => ({
+  width: 320,
+  display: 'flex',
+  flexDirection: 'row',
+  alignItems: 'stretch',
+  gap: theme.spacing(1),
+  backgroundColor: theme.palette.background.paper,
+  border: `4px solid ${theme.tokens.colors.nodeSwitchBorder}`,
+  boxShadow: theme.tokens.shadows.nodeElevated,
+  transition: theme.tokens.transitions.shadow,
+  '&:hover': {
+    boxShadow: theme.tokens.shadows.nodeElevatedHover,
+  },
+}))
+
+export const NodeIcon = styled(Box)(({ theme }) => ({
+  backgroundColor: theme.tokens.colors.nodeSwitchBg,
+  color: theme.tokens.colors.primaryContrast,
+  padding: theme.spacing(1.5),
+  display: 'flex',
+  flexDirection: 'row',
+  alignItems: 'center',
+}))
+
+export const NodeBody = styled(Box)(({ theme }) => ({
+  flex: 1,
+  padding: theme.spacing(1.5),
+  display: 'flex',
+  flexDirection: 'column',
+  alignItems: 'stretch',
+  gap: theme.spacing(1),
+}))
+
+export const BranchRow = styled(Box)(({ theme }) => ({
+  position: 'relative',
+  display: 'flex',
+  flexDirection: 'row',
+  alignItems: 'center',
+  gap: theme.spacing(0.5),
+  marginRight: theme.spacing(-1.5),
+  paddingRight: theme.spacing(1.5),
+}))
+
+export const handleStyle = { width: 12, height: 12 }
@@ -0,0 +1,8 @@
+import { Node } from '@xyflow/react'
+
+import { NodeTrace } from '@/lib/models/PipelineTrace.ts'
+
+export type PipelineNodeCaseData = Node<{
+  conditions: string
+  traces: NodeTrace[] | null
+}>
@@ -74,6 +74,13 @@ const PipelineNodeSwitch = ({
         </NodeBody>
       </NodeRoot>
       <Handle type="source" position={Position.Right} style={handleStyle} />
+      <Handle
+        type="source"
+        id="else"
+        position={Position.Bottom}
+        style={handleStyle}
+        title={t('components.pipelineNodeSwitch.elseHandle')}
+      />
 
       <PipelineTraceNodeIndicator
         status={
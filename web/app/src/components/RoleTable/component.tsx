@@ -31,7 +31,7 @@ import {
 } from './styles'
 import { RoleTableProps } from './types'
 
-type ScopesCellProps = CustomCellRendererProps<string[]>
+type ScopesCellProps = CustomCellRendererProps<RoleModel, string[]>
 
 const ScopesCell = (props: ScopesCellProps) => (
   <ScopesCellRoot>
@@ -42,6 +42,14 @@ const ScopesCell = (props: ScopesCellProps) => (
         size="small"
       />
     ))}
+    {(props.data?.resource_scopes ?? []).map((resourceScope) => (
+      <Chip
+        key={resourceScope}
+        label={resourceScope}
+        size="small"
+        variant="outlined"
+      />
+    ))}
   </ScopesCellRoot>
 )
 
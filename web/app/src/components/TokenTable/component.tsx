@@ -5,7 +5,7 @@ import Typography from '@mui/material/Typography'
 
 import KeyIcon from '@mui/icons-material/Key'
 
-import { ColDef } from 'ag-grid-community'
+import { ColDef, ValueFormatterParams } from 'ag-grid-community'
 import { AgGridReact, CustomCellRendererProps } from 'ag-grid-react'
 
 import * as tokenApi from '@/lib/api/operations/token'
@@ -13,6 +13,9 @@ import * as tokenApi from '@/lib/api/operations/token'
 import { useApiOperation } from '@/lib/hooks/api'
 import { useNotify } from '@/lib/hooks/notify'
 
+import { ScopeLabels } from '@/lib/models/Scopes'
+import TokenInfoModel from '@/lib/models/TokenInfoModel'
+
 import ButtonNewToken from '@/components/ButtonNewToken/component'
 import TableActionsCell from '@/components/TableActionsCell/component'
 
@@ -36,12 +39,16 @@ const TokenTable = ({ tokens }: TokenTableProps) => {
   const { t } = useTranslation()
   const notify = useNotify()
 
+  const formatDate =
+    (fallback: string) => (params: ValueFormatterParams<RowType>) =>
+      params.value ? new Date(params.value).toLocaleString() : fallback
+
   const gridRef = useRef<AgGridReact<RowType>>(null!)
 
   const onNewToken = useCallback(
-    (token: string) => {
+    (token: TokenInfoModel) => {
       gridRef.current.api.applyTransaction({
-        add: [{ token }],
+        add: [token],
       })
     },
     [gridRef]
@@ -49,9 +56,9 @@ const TokenTable = ({ tokens }: TokenTableProps) => {
 
   const [onDelete, loading] = useApiOperation(
     async (data: RowType) => {
-      await tokenApi.deleteToken(data.token)
+      await tokenApi.deleteToken(data.uuid)
 
-      const rowNode = gridRef.current.api.getRowNode(data.token)
+      const rowNode = gridRef.current.api.getRowNode(data.uuid)
       if (rowNode !== undefined && rowNode.data !== undefined) {
         gridRef.current.api.applyTransaction({
           remove: [rowNode.data],
@@ -63,16 +70,50 @@ const TokenTable = ({ tokens }: TokenTableProps) => {
     [gridRef]
   )
 
-  const [rowData] = useState<RowType[]>(tokens.map((token) => ({ token })))
+  const [rowData] = useState<RowType[]>(tokens)
   const [columnDefs] = useState<ColDef<RowType>[]>([
     {
-      headerName: t('components.tokenTable.columns.token'),
-      field: 'token',
+      headerName: t('components.tokenTable.columns.name'),
+      field: 'name',
+      suppressMovable: true,
+      sortable: true,
+      flex: 1,
+    },
+    {
+      headerName: t('components.tokenTable.columns.uuid'),
+      field: 'uuid',
       cellRenderer: TokenCell,
       suppressMovable: true,
       sortable: false,
       flex: 1,
     },
+    {
+      headerName: t('components.tokenTable.columns.scopes'),
+      field: 'scopes',
+      valueFormatter: (params: ValueFormatterParams<RowType, string[]>) =>
+        params.value && params.value.length > 0
+          ? params.value
+              .map((scope) => ScopeLabels[scope as keyof typeof ScopeLabels])
+              .join(', ')
+          : t('components.tokenTable.allScopes'),
+      suppressMovable: true,
+      sortable: false,
+      flex: 1,
+    },
+    {
+      headerName: t('components.tokenTable.columns.expiresAt'),
+      field: 'expires_at',
+      valueFormatter: formatDate(t('components.tokenTable.never')),
+      suppressMovable: true,
+      sortable: true,
+    },
+    {
+      headerName: t('components.tokenTable.columns.lastUsedAt'),
+      field: 'last_used_at',
+      valueFormatter: formatDate(t('components.tokenTable.never')),
+      suppressMovable: true,
+      sortable: true,
+    },
     {
       headerName: t('common.tableColumns.actions'),
       headerClass: 'flowg-actions-header',
@@ -110,7 +151,7 @@ const TokenTable = ({ tokens }: TokenTableProps) => {
           rowData={rowData}
           columnDefs={columnDefs}
           enableCellTextSelection
-          getRowId={({ data }) => data.token}
+          getRowId={({ data }) => data.uuid}
         />
       </TokenTableCardContent>
     </TokenTableCard>
@@ -1,5 +1,7 @@
-export type RowType = { token: string }
+import TokenInfoModel from '@/lib/models/TokenInfoModel'
+
+export type RowType = TokenInfoModel
 
 export type TokenTableProps = Readonly<{
-  tokens: string[]
+  tokens: TokenInfoModel[]
 }>
@@ -58,6 +58,7 @@ export const listRoles = async (): Promise<RoleModel[]> => {
 export const saveRole = async (role: RoleModel): Promise<void> => {
   type SaveRoleRequest = {
     scopes: string[]
+    resource_scopes: string[]
   }
 
   type SaveRoleResponse = {
@@ -68,6 +69,7 @@ export const saveRole = async (role: RoleModel): Promise<void> => {
     path: `/api/v1/roles/${role.name}`,
     body: {
       scopes: role.scopes,
+      resource_scopes: role.resource_scopes ?? [],
     },
   })
 }
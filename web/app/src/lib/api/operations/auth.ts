@@ -1,5 +1,6 @@
 import * as request from '@/lib/api/request'
 
+import AuthProviderInfoModel from '@/lib/models/AuthProviderInfoModel'
 import PermissionsModel from '@/lib/models/PermissionsModel'
 import ProfileModel from '@/lib/models/ProfileModel'
 import UserModel from '@/lib/models/UserModel'
@@ -43,6 +44,27 @@ export const login = async (
   }
 }
 
+export const listAuthProviders = async (): Promise<
+  AuthProviderInfoModel[]
+> => {
+  type ListAuthProvidersResponse = {
+    success: boolean
+    auth_providers: AuthProviderInfoModel[]
+  }
+
+  const { body } = await request.GET<ListAuthProvidersResponse>({
+    path: '/api/v1/auth-providers/',
+  })
+  return body.auth_providers
+}
+
+export const providerLoginUrl = (provider: string): string =>
+  `${request.getBasePath()}/api/v1/auth/${encodeURIComponent(provider)}/login`
+
+export const loginWithToken = (token: string): void => {
+  localStorage.setItem('token', token)
+}
+
 export const logout = async (): Promise<void> => {
   localStorage.removeItem('token')
 }
@@ -2,19 +2,27 @@ import * as request from '@/lib/api/request'
 
 import LogEntryModel from '@/lib/models/LogEntryModel'
 
+export type QueryLogsPage = {
+  records: LogEntryModel[]
+  nextCursor?: string
+}
+
 export const queryLogs = async (
   stream: string,
   from: Date,
   to: Date,
   filter?: string,
-  indexing?: Record<string, Array<string>>
-): Promise<LogEntryModel[]> => {
+  indexing?: Record<string, Array<string>>,
+  limit?: number,
+  cursor?: string
+): Promise<QueryLogsPage> => {
   type QueryLogsResponse = {
     success: boolean
     records: Array<{
       timestamp: string
       fields: Record<string, string>
     }>
+    next_cursor?: string
   }
 
   const searchParams: {
@@ -22,6 +30,8 @@ export const queryLogs = async (
     to: string
     filter?: string
     indexing?: string
+    limit?: string
+    cursor?: string
   } = {
     from: from.toISOString(),
     to: to.toISOString(),
@@ -35,15 +45,26 @@ export const queryLogs = async (
     searchParams.indexing = JSON.stringify(indexing)
   }
 
+  if (limit !== undefined) {
+    searchParams.limit = limit.toString()
+  }
+
+  if (cursor !== undefined) {
+    searchParams.cursor = cursor
+  }
+
   const { body } = await request.GET<QueryLogsResponse>({
     path: `/api/v1/streams/${stream}/logs`,
     searchParams: new URLSearchParams(searchParams),
   })
 
-  return body.records.map(({ timestamp, fields }) => ({
-    timestamp: new Date(timestamp),
-    fields,
-  }))
+  return {
+    records: body.records.map(({ timestamp, fields }) => ({
+      timestamp: new Date(timestamp),
+      fields,
+    })),
+    nextCursor: body.next_cursor,
+  }
 }
 
 export const watchLogs = (
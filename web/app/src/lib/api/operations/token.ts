@@ -1,30 +1,54 @@
 import * as request from '@/lib/api/request'
 
+import TokenInfoModel from '@/lib/models/TokenInfoModel'
 import TokenModel from '@/lib/models/TokenModel'
 
-export const listTokens = async (): Promise<string[]> => {
+export const listTokens = async (): Promise<TokenInfoModel[]> => {
   type ListTokensResponse = {
     success: boolean
     token_uuids: string[]
+    tokens: TokenInfoModel[]
   }
   const { body } = await request.GET<ListTokensResponse>({
     path: '/api/v1/tokens',
   })
-  return body.token_uuids
+  return body.tokens
 }
 
-export const createToken = async (): Promise<TokenModel> => {
+export const createToken = async (
+  name: string,
+  scopes: string[],
+  expiresAt: Date | null
+): Promise<TokenModel> => {
+  type CreateTokenRequest = {
+    name: string
+    scopes: string[]
+    expires_at?: string
+  }
+
   type CreateTokenResponse = {
     success: boolean
     token: string
     token_uuid: string
+    token_info: TokenInfoModel
   }
 
-  const { body } = await request.POST<unknown, CreateTokenResponse>({
+  const { body } = await request.POST<
+    CreateTokenRequest,
+    CreateTokenResponse
+  >({
     path: '/api/v1/token',
-    body: {},
+    body: {
+      name,
+      scopes,
+      expires_at: expiresAt?.toISOString(),
+    },
   })
-  return { token: body.token, token_uuid: body.token_uuid }
+  return {
+    token: body.token,
+    token_uuid: body.token_uuid,
+    token_info: body.token_info,
+  }
 }
 
 export const deleteToken = async (tokenUuid: string): Promise<void> => {
@@ -3,7 +3,7 @@ import { EventSourcePlus } from 'event-source-plus'
 import * as errors from '@/lib/api/errors'
 import { ApiErrorResponse } from '@/lib/api/response'
 
-const getBasePath = (): string => {
+export const getBasePath = (): string => {
   const basePath = document
     .getElementsByTagName('base')[0]
     ?.getAttribute('href')
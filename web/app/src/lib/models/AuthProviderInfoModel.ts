@@ -0,0 +1,6 @@
+type AuthProviderInfoModel = {
+  name: string
+  display_name: string
+}
+
+export default AuthProviderInfoModel
@@ -1,7 +1,22 @@
 import ForwarderConfigModel from '@/lib/models/ForwarderConfigModel'
 
+export type ForwarderRetryPolicyModel = {
+  max_attempts: number
+  initial_backoff?: number
+  max_backoff?: number
+  retryable_status_codes?: number[]
+}
+
+export type ForwarderBatchPolicyModel = {
+  max_size: number
+  flush_interval: number
+  queue_size?: number
+}
+
 type ForwarderModel = {
   config: ForwarderConfigModel
+  retry?: ForwarderRetryPolicyModel
+  batch?: ForwarderBatchPolicyModel
 }
 
 export default ForwarderModel
@@ -11,6 +11,9 @@ type PermissionsModel = {
   can_view_forwarders: boolean
   can_edit_forwarders: boolean
 
+  can_view_alerts: boolean
+  can_edit_alerts: boolean
+
   can_view_acls: boolean
   can_edit_acls: boolean
 
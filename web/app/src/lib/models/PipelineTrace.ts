@@ -7,4 +7,5 @@ export type NodeTrace = {
   input?: Record<string, string>
   output?: Array<Record<string, string>>
   error?: string
+  branch?: string
 }
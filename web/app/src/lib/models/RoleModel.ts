@@ -1,6 +1,7 @@
 type RoleModel = {
   name: string
   scopes: string[]
+  resource_scopes?: string[]
 }
 
 export default RoleModel
@@ -7,6 +7,8 @@ export const ScopeLabels = {
   write_streams: 'View & Edit Streams',
   read_forwarders: 'View Forwarders',
   write_forwarders: 'View & Edit Forwarders',
+  read_alerts: 'View Alerts',
+  write_alerts: 'View & Edit Alerts',
   read_acls: 'View ACLs',
   write_acls: 'View & Edit ACLs',
   send_logs: 'Send Logs',
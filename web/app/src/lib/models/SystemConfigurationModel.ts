@@ -1,6 +1,22 @@
 type SystemConfigurationModel = {
   syslog_allowed_origins: string[]
+  syslog_allowed_subjects: string[]
+  syslog_routes: SyslogRouteModel[]
   default_roles: string[]
+  timestamp_policy: TimestampPolicyModel
+  audit_forwarder?: string
+}
+
+export type SyslogRouteModel = {
+  pipeline: string
+  listeners?: string[]
+  origins?: string[]
+  app_names?: string[]
+}
+
+export type TimestampPolicyModel = {
+  max_past: number
+  max_future: number
 }
 
 export default SystemConfigurationModel
@@ -0,0 +1,10 @@
+type TokenInfoModel = {
+  uuid: string
+  name: string
+  scopes: string[]
+  created_at?: string
+  expires_at?: string
+  last_used_at?: string
+}
+
+export default TokenInfoModel
@@ -1,6 +1,9 @@
+import TokenInfoModel from '@/lib/models/TokenInfoModel'
+
 type AuthTokenModel = {
   token: string
   token_uuid: string
+  token_info: TokenInfoModel
 }
 
 export default AuthTokenModel
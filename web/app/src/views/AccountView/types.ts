@@ -1,3 +1,5 @@
+import TokenInfoModel from '@/lib/models/TokenInfoModel'
+
 export type LoaderData = {
-  tokens: string[]
+  tokens: TokenInfoModel[]
 }
@@ -1,6 +1,6 @@
 import { Typography } from '@mui/material'
 
-import { useState } from 'react'
+import { useEffect, useState } from 'react'
 import { useTranslation } from 'react-i18next'
 import { useNavigate } from 'react-router'
 
@@ -16,6 +16,8 @@ import LoginIcon from '@mui/icons-material/Login'
 import { UnauthenticatedError } from '@/lib/api/errors'
 import * as authApi from '@/lib/api/operations/auth'
 
+import AuthProviderInfoModel from '@/lib/models/AuthProviderInfoModel'
+
 import { useApiOperation } from '@/lib/hooks/api'
 import { useFeatureFlags } from '@/lib/hooks/featureflags'
 import { useNotify } from '@/lib/hooks/notify'
@@ -36,6 +38,33 @@ const LoginView = () => {
 
   const [username, setUsername] = useState('')
   const [password, setPassword] = useState('')
+  const [authProviders, setAuthProviders] = useState<AuthProviderInfoModel[]>(
+    []
+  )
+
+  useEffect(() => {
+    // Auth providers redirect back here with the outcome of the sign-in in
+    // the fragment of the URL.
+    const fragment = new URLSearchParams(window.location.hash.slice(1))
+    window.history.replaceState(null, '', window.location.pathname)
+
+    const token = fragment.get('token')
+    if (token !== null) {
+      authApi.loginWithToken(token)
+      navigate(buildUrl('/'))
+      return
+    }
+
+    const error = fragment.get('error')
+    if (error !== null) {
+      notify.error(t('pages.login.providerFailed', { error }))
+    }
+
+    authApi
+      .listAuthProviders()
+      .then(setAuthProviders)
+      .catch((err) => console.error(err))
+  }, [])
 
   const [handleLogin, loading] = useApiOperation(async () => {
     try {
@@ -130,6 +159,27 @@ const LoginView = () => {
               <>{t('pages.login.submit')}</>
             )}
           </Button>
+
+          {authProviders.length > 0 && (
+            <>
+              <Divider />
+
+              {authProviders.map((authProvider) => (
+                <Button
+                  key={authProvider.name}
+                  id={`btn:login.provider.${authProvider.name}`}
+                  variant="outlined"
+                  color="secondary"
+                  fullWidth
+                  href={authApi.providerLoginUrl(authProvider.name)}
+                >
+                  {t('pages.login.signInWith', {
+                    provider: authProvider.display_name,
+                  })}
+                </Button>
+              ))}
+            </>
+          )}
         </form>
       </LoginViewCard>
     </LoginViewContainer>
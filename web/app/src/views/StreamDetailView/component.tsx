@@ -50,6 +50,8 @@ export const loader: LoaderFunction = loginRequired(async ({ params }) => {
   return { streams, currentStream: params.stream, fields, indices }
 })
 
+const QUERY_PAGE_SIZE = 1000
+
 const StreamDetailView = () => {
   const { t } = useTranslation()
   const notify = useNotify()
@@ -102,16 +104,36 @@ const StreamDetailView = () => {
 
   const [fetchLogs, loading] = useApiOperation(
     async (filter: string, from: Date, to: Date, live: boolean) => {
-      const logs = await logApi.queryLogs(
+      let page = await logApi.queryLogs(
         currentStream,
         from,
         to,
         filter === '' ? undefined : filter,
-        selectedIndices
+        selectedIndices,
+        QUERY_PAGE_SIZE
       )
-      setRowData(logs)
-      setChartData(logs)
+      setRowData(page.records)
+      setChartData(page.records)
       setTimeWindow({ from, to })
+
+      // Older pages are appended as they arrive, so the first results show up
+      // without waiting for the whole time window to be loaded.
+      while (page.nextCursor !== undefined) {
+        page = await logApi.queryLogs(
+          currentStream,
+          from,
+          to,
+          filter === '' ? undefined : filter,
+          selectedIndices,
+          QUERY_PAGE_SIZE,
+          page.nextCursor
+        )
+
+        const records = page.records
+        logTableRef.current?.appendRows(records)
+        setChartData((prev) => [...prev, ...records])
+      }
+
       setWatcher({ enabled: live, filter })
     },
     [currentStream, setRowData, selectedIndices]
@@ -3,6 +3,7 @@ import { useTranslation } from 'react-i18next'
 import { LoaderFunction, useLoaderData } from 'react-router'
 
 import Button from '@mui/material/Button'
+import TextField from '@mui/material/TextField'
 import Typography from '@mui/material/Typography'
 
 import {
@@ -72,6 +73,23 @@ const SystemConfiguration = () => {
           </SystemConfigurationCardContent>
         </SystemConfigurationCard>
 
+        <SystemConfigurationCard>
+          <SystemConfigurationCardHeader>
+            <SystemConfigurationCardTitle variant="titleSm">
+              {t('pages.systemConfiguration.allowedSyslogSubjects')}
+            </SystemConfigurationCardTitle>
+          </SystemConfigurationCardHeader>
+          <SystemConfigurationCardContent>
+            <ListEdit
+              id="editor.config.syslog_allowed_subjects"
+              list={config.syslog_allowed_subjects ?? []}
+              setList={(list) =>
+                setConfig({ ...config, syslog_allowed_subjects: list })
+              }
+            />
+          </SystemConfigurationCardContent>
+        </SystemConfigurationCard>
+
         <SystemConfigurationCard>
           <SystemConfigurationCardHeader>
             <SystemConfigurationCardTitle variant="titleSm">
@@ -87,6 +105,74 @@ const SystemConfiguration = () => {
           </SystemConfigurationCardContent>
         </SystemConfigurationCard>
 
+        <SystemConfigurationCard>
+          <SystemConfigurationCardHeader>
+            <SystemConfigurationCardTitle variant="titleSm">
+              {t('pages.systemConfiguration.timestampPolicy.title')}
+            </SystemConfigurationCardTitle>
+          </SystemConfigurationCardHeader>
+          <SystemConfigurationCardContent>
+            <TextField
+              id="input:config.timestamp_policy.max_past"
+              label={t('pages.systemConfiguration.timestampPolicy.maxPast')}
+              helperText={t('pages.systemConfiguration.timestampPolicy.help')}
+              type="number"
+              value={config.timestamp_policy?.max_past ?? 0}
+              onChange={(e) =>
+                setConfig({
+                  ...config,
+                  timestamp_policy: {
+                    max_past: Math.max(0, Number(e.target.value)),
+                    max_future: config.timestamp_policy?.max_future ?? 0,
+                  },
+                })
+              }
+              variant="standard"
+              fullWidth
+            />
+            <TextField
+              id="input:config.timestamp_policy.max_future"
+              label={t('pages.systemConfiguration.timestampPolicy.maxFuture')}
+              helperText={t('pages.systemConfiguration.timestampPolicy.help')}
+              type="number"
+              value={config.timestamp_policy?.max_future ?? 0}
+              onChange={(e) =>
+                setConfig({
+                  ...config,
+                  timestamp_policy: {
+                    max_past: config.timestamp_policy?.max_past ?? 0,
+                    max_future: Math.max(0, Number(e.target.value)),
+                  },
+                })
+              }
+              variant="standard"
+              fullWidth
+            />
+          </SystemConfigurationCardContent>
+        </SystemConfigurationCard>
+
+        <SystemConfigurationCard>
+          <SystemConfigurationCardHeader>
+            <SystemConfigurationCardTitle variant="titleSm">
+              {t('pages.systemConfiguration.audit.title')}
+            </SystemConfigurationCardTitle>
+          </SystemConfigurationCardHeader>
+          <SystemConfigurationCardContent>
+            <TextField
+              id="input:config.audit_forwarder"
+              label={t('pages.systemConfiguration.audit.forwarder')}
+              helperText={t('pages.systemConfiguration.audit.help')}
+              type="text"
+              value={config.audit_forwarder ?? ''}
+              onChange={(e) =>
+                setConfig({ ...config, audit_forwarder: e.target.value })
+              }
+              variant="standard"
+              fullWidth
+            />
+          </SystemConfigurationCardContent>
+        </SystemConfigurationCard>
+
         <Button
           variant="contained"
           color="secondary"